@@ -0,0 +1,130 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/yoyo0827/simple-bank-system/db"
+	"github.com/yoyo0827/simple-bank-system/internal/config"
+	"github.com/yoyo0827/simple-bank-system/internal/fee"
+	"github.com/yoyo0827/simple-bank-system/internal/fraud"
+	"github.com/yoyo0827/simple-bank-system/internal/migrate"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+)
+
+// app 各子命令共用的 Repository 與 Service
+type app struct {
+	cfg *config.Config
+	db  *sql.DB
+
+	accountRepo     *repository.AccountRepository
+	transactionRepo *repository.TransactionRepository
+	auditRepo       *repository.AuditRepository
+	outboxRepo      *repository.OutboxRepository
+	webhookRepo     *repository.WebhookRepository
+	pendingRepo     *repository.PendingTransferRepository
+	fraudRepo       *repository.FraudRepository
+	customerRepo    *repository.CustomerRepository
+	payeeRepo       *repository.PayeeRepository
+	businessDayRepo *repository.BusinessDayRepository
+
+	validator *request.Validator // API 與 gRPC 請求驗證，帳號號碼格式與開戶設定相同
+
+	accountService        *service.AccountService
+	auditService          *service.AuditService
+	webhookService        *service.WebhookService
+	pendingService        *service.PendingTransferService
+	customerService       *service.CustomerService
+	payeeService          *service.PayeeService
+	reconciliationService *service.ReconciliationService
+	businessDayService    *service.BusinessDayService
+	reportService         *service.ReportService
+}
+
+// 初始化 Service & Repository
+func newApp(cfg *config.Config, db *sql.DB) *app {
+	a := &app{
+		cfg:             cfg,
+		db:              db,
+		accountRepo:     &repository.AccountRepository{},
+		transactionRepo: &repository.TransactionRepository{},
+		auditRepo:       &repository.AuditRepository{},
+		outboxRepo:      &repository.OutboxRepository{},
+		webhookRepo:     &repository.WebhookRepository{},
+		pendingRepo:     &repository.PendingTransferRepository{},
+		fraudRepo:       &repository.FraudRepository{},
+		customerRepo:    &repository.CustomerRepository{},
+		payeeRepo:       &repository.PayeeRepository{},
+		businessDayRepo: &repository.BusinessDayRepository{},
+	}
+	a.validator = request.NewValidator(cfg.AccountNumber)
+	a.accountService = &service.AccountService{
+		DB:                        db,
+		AccountRepository:         a.accountRepo,
+		TransactionRepository:     a.transactionRepo,
+		AuditRepository:           a.auditRepo,
+		OutboxRepository:          a.outboxRepo,
+		PendingTransferRepository: a.pendingRepo,
+		FraudRepository:           a.fraudRepo,
+		CustomerRepository:        a.customerRepo,
+		PayeeRepository:           a.payeeRepo,
+		BalanceSnapshotRepository: &repository.BalanceSnapshotRepository{},
+		Fees:                      fee.NewEngine(cfg.Fees),
+		Fraud:                     fraud.NewEngine(cfg.Fraud),
+		Approval:                  cfg.Approval,
+		KYC:                       cfg.KYC,
+		AccountNumbers:            cfg.AccountNumber,
+		Payees:                    cfg.Payees,
+		BusinessDay:               cfg.BusinessDay,
+		Currency:                  cfg.Currency,
+	}
+	a.auditService = &service.AuditService{DB: db, AuditRepository: a.auditRepo}
+	a.webhookService = &service.WebhookService{DB: db, WebhookRepository: a.webhookRepo, AuditRepository: a.auditRepo}
+	a.pendingService = &service.PendingTransferService{
+		DB:                        db,
+		AccountService:            a.accountService,
+		PendingTransferRepository: a.pendingRepo,
+		FraudRepository:           a.fraudRepo,
+		AuditRepository:           a.auditRepo,
+	}
+	a.customerService = &service.CustomerService{
+		DB:                 db,
+		CustomerRepository: a.customerRepo,
+		AccountRepository:  a.accountRepo,
+		AuditRepository:    a.auditRepo,
+		KYC:                cfg.KYC,
+	}
+	a.payeeService = &service.PayeeService{
+		DB:                db,
+		PayeeRepository:   a.payeeRepo,
+		AccountRepository: a.accountRepo,
+		AuditRepository:   a.auditRepo,
+		Payees:            cfg.Payees,
+	}
+	a.reconciliationService = &service.ReconciliationService{DB: db, ReconciliationRepository: &repository.ReconciliationRepository{}}
+	a.businessDayService = &service.BusinessDayService{
+		DB:                    db,
+		BusinessDayRepository: a.businessDayRepo,
+		AuditRepository:       a.auditRepo,
+		BusinessDay:           cfg.BusinessDay,
+	}
+	a.reportService = &service.ReportService{
+		DB:                    db,
+		ReportRepository:      &repository.ReportRepository{},
+		BusinessDayRepository: a.businessDayRepo,
+		Chart:                 cfg.GL,
+		BusinessDay:           cfg.BusinessDay,
+		FeeRevenueAccount:     cfg.Fees.RevenueAccount,
+	}
+	return a
+}
+
+// 內嵌的 schema migration
+func newMigrator(sqlDB *sql.DB) (*migrate.Migrator, error) {
+	migrations, err := migrate.Load(db.Migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &migrate.Migrator{DB: sqlDB, Migrations: migrations}, nil
+}
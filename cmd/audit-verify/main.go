@@ -0,0 +1,52 @@
+// audit-verify 重算 audit_events 的 hash chain，確認稽核紀錄未被竄改
+// 使用與服務相同的設定 (設定檔 / 環境變數 / 參數)，驗證失敗時 exit code 為 1
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/yoyo0827/simple-bank-system/internal/config"
+	"github.com/yoyo0827/simple-bank-system/internal/logging"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	_ = godotenv.Load()
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		slog.Error("invalid configuration", "error", err)
+		return 2
+	}
+	slog.SetDefault(logging.New(os.Stderr, cfg.Log))
+
+	db, err := config.OpenDatabase(cfg.Database)
+	if err != nil {
+		slog.Error("failed to open database", "error", err)
+		return 2
+	}
+	defer db.Close()
+
+	svc := &service.AuditService{DB: db, AuditRepository: &repository.AuditRepository{}}
+	result, err := svc.Verify(context.Background())
+	if err != nil {
+		slog.Error("audit verification failed", "error", err)
+		return 2
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(result)
+	if !result.Valid {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+// errUnbalanced 帳務核對發現不一致 (exit code 1，結果已輸出)
+var errUnbalanced = errors.New("reconciliation found mismatches")
+
+type cli struct {
+	client *Client
+	out    *printer
+	stderr io.Writer
+}
+
+func (c *cli) dispatch(ctx context.Context, cmd string, args []string) error {
+	switch cmd {
+	case "accounts":
+		if len(args) == 0 {
+			return fmt.Errorf("%w: accounts requires a subcommand", errUsage)
+		}
+		switch args[0] {
+		case "create":
+			return c.createAccount(ctx, args[1:])
+		case "get":
+			return c.getAccount(ctx, args[1:])
+		case "freeze":
+			return c.freezeAccount(ctx, args[1:])
+		case "unfreeze":
+			return c.unfreezeAccount(ctx, args[1:])
+		}
+		return fmt.Errorf("%w: unknown accounts subcommand %q", errUsage, args[0])
+	case "deposit":
+		return c.transact(ctx, "deposit", args, false)
+	case "withdraw":
+		return c.transact(ctx, "withdraw", args, true)
+	case "transfer":
+		return c.transfer(ctx, args)
+	case "transactions":
+		return c.transactions(ctx, args)
+	case "statement":
+		return c.statement(ctx, args)
+	case "reconcile":
+		return c.reconcile(ctx, args)
+	}
+	return fmt.Errorf("%w: unknown command %q", errUsage, cmd)
+}
+
+func (c *cli) createAccount(ctx context.Context, args []string) error {
+	fs := c.flagSet("accounts create")
+	name := fs.String("name", "", "account name")
+	balance := fs.String("balance", "0", "opening balance")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	if *name == "" {
+		return fmt.Errorf("%w: -name is required", errUsage)
+	}
+	amount, err := parseAmount("-balance", *balance)
+	if err != nil {
+		return err
+	}
+	var acc domain.Account
+	body := map[string]any{"name": *name, "balance": amount}
+	if err := c.client.Post(ctx, "/accounts", body, &acc); err != nil {
+		return err
+	}
+	return c.out.account(&acc)
+}
+
+func (c *cli) getAccount(ctx context.Context, args []string) error {
+	pos, err := parseArgs(c.flagSet("accounts get"), args, 1)
+	if err != nil {
+		return err
+	}
+	var acc domain.Account
+	if err := c.client.Get(ctx, "/accounts/"+url.PathEscape(pos[0]), nil, &acc); err != nil {
+		return err
+	}
+	return c.out.account(&acc)
+}
+
+func (c *cli) freezeAccount(ctx context.Context, args []string) error {
+	fs := c.flagSet("accounts freeze")
+	reason := fs.String("reason", "", "reason for freezing the account")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *reason == "" {
+		return fmt.Errorf("%w: -reason is required", errUsage)
+	}
+	var acc domain.Account
+	if err := c.client.Post(ctx, "/admin/accounts/"+url.PathEscape(pos[0])+"/freeze", map[string]string{"reason": *reason}, &acc); err != nil {
+		return err
+	}
+	return c.out.account(&acc)
+}
+
+func (c *cli) unfreezeAccount(ctx context.Context, args []string) error {
+	pos, err := parseArgs(c.flagSet("accounts unfreeze"), args, 1)
+	if err != nil {
+		return err
+	}
+	var acc domain.Account
+	if err := c.client.Post(ctx, "/admin/accounts/"+url.PathEscape(pos[0])+"/unfreeze", nil, &acc); err != nil {
+		return err
+	}
+	return c.out.account(&acc)
+}
+
+// 存款與提款，金額一律輸入正數，提款時轉為負數
+func (c *cli) transact(ctx context.Context, name string, args []string, withdraw bool) error {
+	pos, err := parseArgs(c.flagSet(name), args, 2)
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount("AMOUNT", pos[1])
+	if err != nil {
+		return err
+	}
+	if withdraw {
+		amount = amount.Neg()
+	}
+	var res struct {
+		RefID string `json:"ref_id"`
+	}
+	if err := c.client.Post(ctx, "/accounts/"+url.PathEscape(pos[0])+"/transactions", map[string]any{"amount": amount}, &res); err != nil {
+		return err
+	}
+	return c.out.refID(res.RefID)
+}
+
+func (c *cli) transfer(ctx context.Context, args []string) error {
+	pos, err := parseArgs(c.flagSet("transfer"), args, 3)
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount("AMOUNT", pos[2])
+	if err != nil {
+		return err
+	}
+	var res domain.TransferResult
+	body := map[string]any{"from_account": pos[0], "to_account": pos[1], "amount": amount}
+	if err := c.client.Post(ctx, "/accounts/transfer", body, &res); err != nil {
+		return err
+	}
+	return c.out.transfer(&res)
+}
+
+func (c *cli) transactions(ctx context.Context, args []string) error {
+	fs := c.flagSet("transactions")
+	txType := fs.String("type", "", "deposit or withdrawal")
+	from := fs.String("from", "", "only transactions at or after DATE")
+	to := fs.String("to", "", "only transactions before DATE")
+	afterID := fs.Int64("after-id", 0, "only transactions after this ID")
+	limit := fs.Int("limit", 0, "max transactions (server max 500)")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	q := url.Values{}
+	if *txType != "" {
+		q.Set("type", *txType)
+	}
+	if err := setDate(q, "from", *from); err != nil {
+		return err
+	}
+	if err := setDate(q, "to", *to); err != nil {
+		return err
+	}
+	if *afterID > 0 {
+		q.Set("after_id", strconv.FormatInt(*afterID, 10))
+	}
+	if *limit > 0 {
+		q.Set("limit", strconv.Itoa(*limit))
+	}
+	var txs []*domain.Transaction
+	if err := c.client.Get(ctx, "/accounts/"+url.PathEscape(pos[0])+"/transactions", q, &txs); err != nil {
+		return err
+	}
+	return c.out.transactions(txs)
+}
+
+func (c *cli) statement(ctx context.Context, args []string) error {
+	fs := c.flagSet("statement")
+	from := fs.String("from", "", "period start DATE (inclusive)")
+	to := fs.String("to", "", "period end DATE (exclusive)")
+	format := fs.String("format", "", "table, json or csv (defaults to -o)")
+	outFile := fs.String("out", "", "write the statement to FILE instead of stdout")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	p := *c.out
+	switch *format {
+	case "":
+	case outputTable, outputJSON, outputCSV:
+		p.format = *format
+	default:
+		return fmt.Errorf("%w: unknown statement format %q", errUsage, *format)
+	}
+	q := url.Values{}
+	if err := setDate(q, "from", *from); err != nil {
+		return err
+	}
+	if err := setDate(q, "to", *to); err != nil {
+		return err
+	}
+	var st domain.Statement
+	if err := c.client.Get(ctx, "/accounts/"+url.PathEscape(pos[0])+"/statement", q, &st); err != nil {
+		return err
+	}
+	if *outFile == "" {
+		return p.statement(&st)
+	}
+	f, err := os.Create(*outFile)
+	if err != nil {
+		return err
+	}
+	p.w = f
+	if err := p.statement(&st); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// 帳務核對，發現不一致時 exit code 為 1
+func (c *cli) reconcile(ctx context.Context, args []string) error {
+	if _, err := parseArgs(c.flagSet("reconcile"), args, 0); err != nil {
+		return err
+	}
+	var rec domain.Reconciliation
+	if err := c.client.Get(ctx, "/admin/reconciliation", nil, &rec); err != nil {
+		return err
+	}
+	if err := c.out.reconciliation(&rec); err != nil {
+		return err
+	}
+	if !rec.Balanced {
+		return errUnbalanced
+	}
+	return nil
+}
+
+func (c *cli) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	return fs
+}
+
+// parseArgs 允許旗標與位置參數交錯，並檢查位置參數個數
+func parseArgs(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", errUsage, fs.Name(), err)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(pos) != n {
+		return nil, fmt.Errorf("%w: %s expects %d argument(s), got %d", errUsage, fs.Name(), n, len(pos))
+	}
+	return pos, nil
+}
+
+func parseAmount(name, raw string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(raw)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("%w: %s must be a decimal number", errUsage, name)
+	}
+	return d, nil
+}
+
+// setDate 將 RFC 3339 或 YYYY-MM-DD (UTC) 轉為 API 使用的 RFC 3339
+func setDate(q url.Values, key, raw string) error {
+	if raw == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		if t, err = time.Parse(time.DateOnly, raw); err != nil {
+			return fmt.Errorf("%w: -%s must be RFC 3339 or YYYY-MM-DD", errUsage, key)
+		}
+	}
+	q.Set(key, t.Format(time.RFC3339))
+	return nil
+}
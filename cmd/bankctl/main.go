@@ -0,0 +1,101 @@
+// bankctl 透過 REST API 操作帳號的命令列工具
+// 可建立帳號、存提款、轉帳、查詢交易紀錄、匯出對帳單、凍結帳號與執行帳務核對
+//
+//	bankctl [-url URL] [-api-key KEY] [-o table|json] <command> [args]
+//
+// -url 與 -api-key 預設讀取 BANKCTL_URL / BANKCTL_API_KEY 環境變數
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const usage = `Usage: bankctl [global flags] <command> [args]
+
+Commands:
+  accounts create -name NAME [-balance AMOUNT]
+  accounts get ACCOUNT
+  accounts freeze ACCOUNT -reason REASON
+  accounts unfreeze ACCOUNT
+  deposit ACCOUNT AMOUNT
+  withdraw ACCOUNT AMOUNT
+  transfer FROM_ACCOUNT TO_ACCOUNT AMOUNT
+  transactions ACCOUNT [-type deposit|withdrawal] [-from DATE] [-to DATE] [-after-id N] [-limit N]
+  statement ACCOUNT [-from DATE] [-to DATE] [-format table|json|csv] [-out FILE]
+  reconcile
+
+ACCOUNT is the account number (with check digits).
+DATE is RFC 3339 (2025-01-02T15:04:05Z) or a calendar date (2025-01-02, UTC).
+
+Global flags:
+`
+
+// errUsage 參數錯誤，exit code 為 2
+var errUsage = errors.New("usage error")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bankctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	baseURL := fs.String("url", envOr("BANKCTL_URL", "http://localhost:8080"), "API base URL (env BANKCTL_URL)")
+	apiKey := fs.String("api-key", os.Getenv("BANKCTL_API_KEY"), "API key sent as X-API-Key (env BANKCTL_API_KEY)")
+	output := fs.String("o", outputTable, "output format: table or json")
+	timeout := fs.Duration("timeout", 30*time.Second, "request timeout")
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if *output != outputTable && *output != outputJSON {
+		fmt.Fprintf(stderr, "bankctl: unknown output format %q\n", *output)
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	c := &cli{
+		client: &Client{BaseURL: *baseURL, APIKey: *apiKey, HTTP: &http.Client{}},
+		out:    &printer{w: stdout, format: *output},
+		stderr: stderr,
+	}
+	err := c.dispatch(ctx, fs.Arg(0), fs.Args()[1:])
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(stderr, "bankctl:", err)
+		fs.Usage()
+		return 2
+	case errors.Is(err, errUnbalanced):
+		return 1
+	default:
+		fmt.Fprintln(stderr, "bankctl:", err)
+		return 1
+	}
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
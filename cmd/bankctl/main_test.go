@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 模擬 REST API，記錄最後一次請求
+type fakeAPI struct {
+	method, path, query, apiKey string
+	body                        map[string]any
+}
+
+func (f *fakeAPI) server(t *testing.T, status int, data string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.method, f.path, f.query, f.apiKey = r.Method, r.URL.Path, r.URL.RawQuery, r.Header.Get("X-API-Key")
+		f.body = nil
+		_ = json.NewDecoder(r.Body).Decode(&f.body)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(data))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestWithdraw_SendsNegativeAmount(t *testing.T) {
+	var api fakeAPI
+	srv := api.server(t, http.StatusOK, `{"status":"success","data":{"ref_id":"r-1"}}`)
+
+	var out bytes.Buffer
+	code := run([]string{"-url", srv.URL, "-api-key", "k", "-o", "json", "withdraw", "7", "12.50"}, &out, &out)
+
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "POST /accounts/7/transactions", api.method+" "+api.path)
+	assert.Equal(t, "k", api.apiKey)
+	assert.Equal(t, "-12.5", api.body["amount"])
+	assert.JSONEq(t, `{"ref_id":"r-1"}`, out.String())
+}
+
+func TestTransactions_Filters(t *testing.T) {
+	var api fakeAPI
+	srv := api.server(t, http.StatusOK, `{"status":"success","data":[{"id":3,"type":2,"amount":"40","ref_id":"r-3","created_at":"2025-01-02T00:00:00Z"}]}`)
+
+	var out bytes.Buffer
+	code := run([]string{"-url", srv.URL, "transactions", "1", "-type", "deposit", "-from", "2025-01-01", "-limit", "10"}, &out, &out)
+
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "from=2025-01-01T00%3A00%3A00Z&limit=10&type=deposit", api.query)
+	assert.Contains(t, out.String(), "deposit")
+	assert.Contains(t, out.String(), "40.00")
+}
+
+func TestStatement_CSV(t *testing.T) {
+	var api fakeAPI
+	srv := api.server(t, http.StatusOK, `{"status":"success","data":{"account_number":"100000000016","name":"Alice",
+		"opening_balance":"100","closing_balance":"120","total_deposits":"50","total_withdrawals":"30",
+		"transactions":[{"id":1,"type":2,"amount":"50","ref_id":"a"},{"id":2,"type":1,"amount":"30","ref_id":"b"}]}}`)
+
+	file := filepath.Join(t.TempDir(), "statement.csv")
+	var out bytes.Buffer
+	code := run([]string{"-url", srv.URL, "statement", "1", "-format", "csv", "-out", file}, &out, &out)
+	require.Equal(t, 0, code, out.String())
+
+	b, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	assert.Equal(t, []string{
+		"id,time,type,amount,balance,ref_id,description",
+		"1,,deposit,50.00,150.00,a,",
+		"2,,withdrawal,30.00,120.00,b,",
+	}, lines)
+}
+
+func TestReconcile_MismatchExitCode(t *testing.T) {
+	var api fakeAPI
+	srv := api.server(t, http.StatusOK, `{"status":"success","data":{"balanced":false,"accounts_checked":2,
+		"mismatches":[{"account_number":"100000000024","name":"Bob","balance":"10","ledger_balance":"0","difference":"10"}]}}`)
+
+	var out bytes.Buffer
+	code := run([]string{"-url", srv.URL, "reconcile"}, &out, &out)
+
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "/admin/reconciliation", api.path)
+	assert.Contains(t, out.String(), "Bob")
+}
+
+func TestAPIError(t *testing.T) {
+	var api fakeAPI
+	srv := api.server(t, http.StatusUnprocessableEntity, `{"status":"error","error":"validation failed","errors":[{"field":"name","message":"is required"}]}`)
+
+	var out, errOut bytes.Buffer
+	code := run([]string{"-url", srv.URL, "accounts", "create", "-name", "x"}, &out, &errOut)
+
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut.String(), "422 Unprocessable Entity: validation failed")
+	assert.Contains(t, errOut.String(), "name: is required")
+}
+
+func TestUsageErrors(t *testing.T) {
+	var out bytes.Buffer
+	assert.Equal(t, 2, run([]string{"deposit", "1"}, &out, &out))
+	assert.Equal(t, 2, run([]string{"deposit", "1", "abc"}, &out, &out))
+	assert.Equal(t, 2, run([]string{"-o", "yaml", "reconcile"}, &out, &out))
+	assert.Equal(t, 2, run([]string{"nope"}, &out, &out))
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+// 輸出格式
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputCSV   = "csv"
+)
+
+// printer 依輸出格式印出結果，table 模式由 table 函式產生欄位
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func (p *printer) print(v any, table func(tw *tabwriter.Writer)) error {
+	if p.format == outputJSON {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+func row(tw *tabwriter.Writer, cols ...any) {
+	s := make([]string, len(cols))
+	for i, c := range cols {
+		s[i] = fmt.Sprint(c)
+	}
+	fmt.Fprintln(tw, strings.Join(s, "\t"))
+}
+
+func (p *printer) account(acc *domain.Account) error {
+	return p.print(acc, func(tw *tabwriter.Writer) {
+		row(tw, "ACCOUNT NUMBER", "NAME", "BALANCE", "STATUS", "REASON")
+		row(tw, acc.Number, acc.Name, acc.Balance.StringFixed(2), acc.Status, acc.StatusReason)
+	})
+}
+
+func (p *printer) transactions(txs []*domain.Transaction) error {
+	return p.print(txs, func(tw *tabwriter.Writer) {
+		transactionRows(tw, txs)
+	})
+}
+
+func transactionRows(tw *tabwriter.Writer, txs []*domain.Transaction) {
+	row(tw, "ID", "TIME", "TYPE", "AMOUNT", "REF ID", "DESCRIPTION")
+	for _, tx := range txs {
+		row(tw, tx.ID, tx.CreatedAt, txType(tx.Type), tx.Amount.StringFixed(2), tx.RefID, tx.Description)
+	}
+}
+
+func (p *printer) statement(st *domain.Statement) error {
+	if p.format == outputCSV {
+		return statementCSV(p.w, st)
+	}
+	return p.print(st, func(tw *tabwriter.Writer) {
+		row(tw, "Account:", st.AccountNumber+" ("+st.Name+")")
+		row(tw, "Period:", period(st))
+		row(tw, "Opening balance:", st.OpeningBalance.StringFixed(2))
+		row(tw, "Total deposits:", st.TotalDeposits.StringFixed(2))
+		row(tw, "Total withdrawals:", st.TotalWithdrawals.StringFixed(2))
+		row(tw, "Closing balance:", st.ClosingBalance.StringFixed(2))
+		row(tw)
+		transactionRows(tw, st.Transactions)
+	})
+}
+
+// 對帳單 CSV：每筆交易一列，附帶交易後餘額
+func statementCSV(w io.Writer, st *domain.Statement) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"id", "time", "type", "amount", "balance", "ref_id", "description"})
+	balance := st.OpeningBalance
+	for _, tx := range st.Transactions {
+		if tx.Type == 2 {
+			balance = balance.Add(tx.Amount)
+		} else {
+			balance = balance.Sub(tx.Amount)
+		}
+		_ = cw.Write([]string{
+			strconv.Itoa(tx.ID), tx.CreatedAt, txType(tx.Type),
+			tx.Amount.StringFixed(2), balance.StringFixed(2), tx.RefID, tx.Description,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (p *printer) reconciliation(rec *domain.Reconciliation) error {
+	return p.print(rec, func(tw *tabwriter.Writer) {
+		row(tw, "Accounts checked:", rec.AccountsChecked)
+		row(tw, "Balanced:", rec.Balanced)
+		if len(rec.Mismatches) == 0 {
+			return
+		}
+		row(tw)
+		row(tw, "ACCOUNT", "NAME", "BALANCE", "LEDGER", "DIFFERENCE")
+		for _, m := range rec.Mismatches {
+			row(tw, m.AccountNumber, m.Name, m.Balance.StringFixed(2), m.LedgerBalance.StringFixed(2), m.Difference.StringFixed(2))
+		}
+	})
+}
+
+func (p *printer) refID(refID string) error {
+	return p.print(map[string]string{"ref_id": refID}, func(tw *tabwriter.Writer) {
+		row(tw, "REF ID")
+		row(tw, refID)
+	})
+}
+
+// 轉帳結果，待審核或待覆核時顯示待處理轉帳 ID
+func (p *printer) transfer(res *domain.TransferResult) error {
+	return p.print(res, func(tw *tabwriter.Writer) {
+		if res.Status != domain.TransferStatusCompleted {
+			row(tw, "STATUS", "PENDING TRANSFER ID", "RULE")
+			row(tw, res.Status, res.PendingTransferID, res.Rule)
+			return
+		}
+		row(tw, "REF ID")
+		row(tw, res.RefID)
+	})
+}
+
+func period(st *domain.Statement) string {
+	from, to := "beginning", "now"
+	if st.From != nil {
+		from = st.From.Format(time.RFC3339)
+	}
+	if st.To != nil {
+		to = st.To.Format(time.RFC3339)
+	}
+	return from + " - " + to
+}
+
+func txType(t int) string {
+	if t == 2 {
+		return "deposit"
+	}
+	return "withdrawal"
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/businessday"
+	"github.com/yoyo0827/simple-bank-system/internal/config"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/logging"
+	"github.com/yoyo0827/simple-bank-system/internal/migrate"
+	"github.com/yoyo0827/simple-bank-system/internal/seed"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+)
+
+// migrateCmd 套用、回復或查詢 schema migration
+func migrateCmd(args []string) int {
+	if len(args) == 0 || (args[0] != "up" && args[0] != "down" && args[0] != "status") {
+		fmt.Fprintln(os.Stderr, "usage: bank-server migrate up | down [-steps N] | status [flags]")
+		return 2
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("migrate "+action, flag.ContinueOnError)
+	steps := 1
+	if action == "down" {
+		fs.IntVar(&steps, "steps", 1, "number of migrations to revert")
+	}
+	cfg, db, code := openCLI(fs, args[1:])
+	if cfg == nil {
+		return code
+	}
+	defer db.Close()
+	ctx, stop := signalContext()
+	defer stop()
+	migrator, err := newMigrator(db)
+	if err != nil {
+		slog.Error("invalid migrations", "error", err)
+		return 1
+	}
+
+	switch action {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			slog.Error("migrate up failed", "error", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+	case "down":
+		if steps < 1 {
+			fmt.Fprintln(os.Stderr, "-steps must be at least 1")
+			return 2
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			slog.Error("migrate down failed", "error", err)
+			return 1
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			slog.Error("migrate status failed", "error", err)
+			return 1
+		}
+		printMigrationStatus(statuses)
+	}
+	return 0
+}
+
+func printMigrationStatus(statuses []migrate.Status) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+	for _, st := range statuses {
+		applied := "pending"
+		if st.AppliedAt != nil {
+			applied = st.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%04d\t%s\t%s\n", st.Version, st.Name, applied)
+	}
+	tw.Flush()
+}
+
+// seedCmd 建立示範 / 壓測用的帳號與交易紀錄
+func seedCmd(args []string) int {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	var opts seed.Options
+	fs.IntVar(&opts.Accounts, "accounts", 100, "number of accounts to create")
+	fs.IntVar(&opts.Transactions, "transactions", 20, "average number of transactions per account")
+	fs.Uint64Var(&opts.Seed, "seed", 1, "random seed (same seed, same data)")
+	cfg, db, code := openCLI(fs, args)
+	if cfg == nil {
+		return code
+	}
+	defer db.Close()
+	ctx, stop := signalContext()
+	defer stop()
+	if opts.Accounts < 1 || opts.Transactions < 0 {
+		fmt.Fprintln(os.Stderr, "-accounts must be at least 1 and -transactions must not be negative")
+		return 2
+	}
+
+	a := newApp(cfg, db)
+	opts.Fees = a.accountService.Fees
+	start := time.Now()
+	res, err := (&seed.Seeder{AccountService: a.accountService}).Run(ctx, seed.NewPlan(opts))
+	if err != nil {
+		slog.Error("seed failed", "error", err, "accounts_created", len(res.AccountNumbers), "transactions", res.Transactions)
+		return 1
+	}
+	res.Seed = opts.Seed
+	slog.Info("seed completed", "accounts", len(res.AccountNumbers), "transactions", res.Transactions,
+		"held", res.Held, "blocked", res.Blocked, "skipped", res.Skipped, "duration_ms", time.Since(start).Milliseconds())
+	return printJSON(res)
+}
+
+// reconcileCmd 離線核對所有帳號餘額，不一致時 exit code 為 1
+func reconcileCmd(args []string) int {
+	cfg, db, code := openCLI(flag.NewFlagSet("reconcile", flag.ContinueOnError), args)
+	if cfg == nil {
+		return code
+	}
+	defer db.Close()
+	ctx, stop := signalContext()
+	defer stop()
+
+	result, err := newApp(cfg, db).reconciliationService.Reconcile(ctx)
+	if err != nil {
+		slog.Error("reconciliation failed", "error", err)
+		return 2
+	}
+	if code := printJSON(result); code != 0 {
+		return code
+	}
+	if !result.Balanced {
+		return 1
+	}
+	return 0
+}
+
+// closeDayCmd 日終結帳，未指定 -date 時結帳前一營業日 (供 cron 等外部排程使用)
+func closeDayCmd(args []string) int {
+	fs := flag.NewFlagSet("close-day", flag.ContinueOnError)
+	date := fs.String("date", "", "business date to close (YYYY-MM-DD, default: the previous business date)")
+	cfg, db, code := openCLI(fs, args)
+	if cfg == nil {
+		return code
+	}
+	defer db.Close()
+	ctx, stop := signalContext()
+	defer stop()
+	if *date == "" {
+		*date = businessday.AddDays(cfg.BusinessDay.Today(time.Now()), -1)
+	}
+
+	result, err := newApp(cfg, db).businessDayService.CloseBusinessDate(ctx, *date)
+	if err != nil {
+		slog.Error("close business date failed", "business_date", *date, "error", err)
+		return 1
+	}
+	return printJSON(result)
+}
+
+// glExportCmd 匯出營業日的總帳彙總 (預設為前一營業日)，借貸不平衡時 exit code 為 1
+func glExportCmd(args []string) int {
+	fs := flag.NewFlagSet("gl-export", flag.ContinueOnError)
+	date := fs.String("date", "", "business date (YYYY-MM-DD, default: the previous business date)")
+	format := fs.String("format", "csv", "output format: csv or json")
+	out := fs.String("out", "", "output file (default: stdout)")
+	cfg, db, code := openCLI(fs, args)
+	if cfg == nil {
+		return code
+	}
+	defer db.Close()
+	ctx, stop := signalContext()
+	defer stop()
+	if *format != "csv" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		return 2
+	}
+	if *date == "" {
+		*date = businessday.AddDays(cfg.BusinessDay.Today(time.Now()), -1)
+	}
+
+	sum, err := newApp(cfg, db).reportService.GLSummary(ctx, *date)
+	if err != nil {
+		slog.Error("gl export failed", "business_date", *date, "error", err)
+		return 1
+	}
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			slog.Error("failed to create output file", "error", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	if *format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(sum)
+	} else {
+		err = writeGLSummaryCSV(w, sum)
+	}
+	if err != nil {
+		slog.Error("failed to write output", "error", err)
+		return 1
+	}
+	if !sum.Balanced {
+		slog.Error("general ledger is out of balance", "business_date", sum.BusinessDate,
+			"total_debits", sum.TotalDebits, "total_credits", sum.TotalCredits)
+		return 1
+	}
+	return 0
+}
+
+// importAccountsCmd 從 CSV 匯入帳號與期初餘額，不受 HTTP body 大小限制；有驗證失敗的列時不建立帳號且 exit code 為 1
+// 開戶中途失敗時仍輸出已建立的帳號
+func importAccountsCmd(args []string) int {
+	fs := flag.NewFlagSet("import-accounts", flag.ContinueOnError)
+	file := fs.String("file", "", "CSV file to import (- for stdin)")
+	dryRun := fs.Bool("dry-run", false, "validate every row and report errors without creating accounts")
+	cfg, db, code := openCLI(fs, args)
+	if cfg == nil {
+		return code
+	}
+	defer db.Close()
+	ctx, stop := signalContext()
+	defer stop()
+	if *file == "" {
+		fmt.Fprintln(os.Stderr, "-file is required")
+		return 2
+	}
+	r := io.Reader(os.Stdin)
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			slog.Error("failed to open import file", "error", err)
+			return 1
+		}
+		defer f.Close()
+		r = f
+	}
+
+	result, err := newApp(cfg, db).accountService.ImportAccounts(ctx, r, *dryRun)
+	if result != nil {
+		if code := printJSON(result); code != 0 {
+			return code
+		}
+	}
+	if err != nil && !errors.Is(err, service.ErrImportInvalid) {
+		slog.Error("account import failed", "error", err)
+		return 1
+	}
+	if result.Invalid > 0 {
+		return 1
+	}
+	return 0
+}
+
+// 每個科目一列，最後一列為借貸方合計
+func writeGLSummaryCSV(w io.Writer, sum *domain.GLSummary) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"business_date", "gl_code", "gl_name", "type", "debits", "credits", "entries"})
+	for _, l := range sum.Lines {
+		_ = cw.Write([]string{sum.BusinessDate, l.Code, l.Name, l.Type, l.Debits.StringFixed(2), l.Credits.StringFixed(2), strconv.Itoa(l.Entries)})
+	}
+	_ = cw.Write([]string{sum.BusinessDate, "", "Total", "", sum.TotalDebits.StringFixed(2), sum.TotalCredits.StringFixed(2), ""})
+	cw.Flush()
+	return cw.Error()
+}
+
+// openCLI 載入設定並連線 DB，日誌輸出至 stderr，結果輸出至 stdout
+// 失敗時 cfg 為 nil 並回傳 exit code
+func openCLI(fs *flag.FlagSet, args []string) (*config.Config, *sql.DB, int) {
+	cfg, _, err := loadConfig(fs, args)
+	if err != nil {
+		return nil, nil, configError(err)
+	}
+	slog.SetDefault(logging.New(os.Stderr, cfg.Log))
+	db, err := config.OpenDatabase(cfg.Database)
+	if err != nil {
+		slog.Error("failed to open database", "error", err)
+		return nil, nil, 1
+	}
+	return cfg, db, 0
+}
+
+// 收到 SIGINT / SIGTERM 時取消
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+}
+
+func printJSON(v any) int {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		slog.Error("failed to write output", "error", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,9 @@
+// Package db 內嵌資料庫 migration，檔名格式為 <version>_<name>.up.sql / .down.sql
+package db
+
+import "embed"
+
+// Migrations 依版本排序套用的 SQL migration
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
@@ -0,0 +1,117 @@
+CREATE TABLE IF NOT EXISTS accounts (
+    id SERIAL PRIMARY KEY, -- 帳號 ID (自動增加)
+    name VARCHAR(100) NOT NULL, -- 帳號名稱
+    balance NUMERIC(15,2) NOT NULL DEFAULT 0, -- 帳號餘額
+    created_at TIMESTAMP DEFAULT NOW(), -- 建立時間
+    updated_at TIMESTAMP DEFAULT NOW() -- 更新時間
+);
+CREATE TABLE IF NOT EXISTS transactions (
+    id SERIAL PRIMARY KEY,                -- 流水號
+    account_id INT NOT NULL REFERENCES accounts(id), -- 對應哪個帳號
+    type INT NOT NULL,                    -- 1=提款, 2=存款
+    amount NUMERIC(20,2) NOT NULL,        -- 金額
+    ref_id        VARCHAR(50) NOT NULL, -- 關聯 ID
+    description VARCHAR(255),             -- 備註
+    request_id VARCHAR(64),               -- 對應 HTTP 請求的 X-Request-ID
+    created_at TIMESTAMP NOT NULL DEFAULT NOW() -- 交易時間
+);
+-- 由舊版 db/init.sql 建立的資料庫已有 transactions，上面的 CREATE 不會新增欄位
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS request_id VARCHAR(64);
+-- 稽核紀錄 (append-only，hash chain 串連前一筆事件)
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    action VARCHAR(50) NOT NULL,           -- 動作 (account.created, account.balance_changed ...)
+    entity_type VARCHAR(50) NOT NULL,      -- 實體類型
+    entity_id VARCHAR(50) NOT NULL,        -- 實體 ID
+    actor VARCHAR(100) NOT NULL,           -- 操作者
+    source_ip VARCHAR(45),                 -- 來源 IP
+    request_id VARCHAR(64),                -- 對應 HTTP 請求的 X-Request-ID
+    before_state JSON,                     -- 異動前快照 (JSON 保留原始文字，hash 才能重算)
+    after_state JSON,                      -- 異動後快照
+    prev_hash CHAR(64) NOT NULL,           -- 前一筆事件的 hash
+    hash CHAR(64) NOT NULL UNIQUE,         -- 本筆事件的 hash
+    created_at TIMESTAMPTZ NOT NULL        -- 事件時間
+);
+CREATE INDEX IF NOT EXISTS idx_audit_events_entity ON audit_events (entity_type, entity_id);
+
+-- 禁止修改或刪除稽核紀錄
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS audit_events_no_modify ON audit_events;
+CREATE TRIGGER audit_events_no_modify BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+CREATE TRIGGER audit_events_no_truncate BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
+
+-- Transactional outbox：與業務資料同一個 transaction 寫入，由 relay 投遞到下游
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,       -- 事件類型 (AccountCreated, Deposited ...)
+    aggregate_id VARCHAR(50) NOT NULL,     -- 事件所屬帳號
+    request_id VARCHAR(64),                -- 對應 HTTP 請求的 X-Request-ID
+    payload JSONB NOT NULL,                -- 事件內容
+    occurred_at TIMESTAMPTZ NOT NULL       -- 事件時間
+);
+-- 每個 sink 各自的投遞狀態
+CREATE TABLE IF NOT EXISTS outbox_deliveries (
+    event_id BIGINT NOT NULL REFERENCES outbox_events(id),
+    sink VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL,           -- retrying / delivered / failed
+    attempts INT NOT NULL DEFAULT 0,       -- 已嘗試次數
+    next_attempt_at TIMESTAMPTZ NOT NULL,  -- 下次重試時間
+    last_error TEXT,                       -- 最後一次失敗原因
+    delivered_at TIMESTAMPTZ,              -- 送達時間
+    PRIMARY KEY (event_id, sink)
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_deliveries_retry ON outbox_deliveries (sink, status, next_attempt_at);
+
+-- Webhook 訂閱
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,              -- 投遞位址
+    event_types TEXT[] NOT NULL DEFAULT '{}', -- 訂閱的事件類型，空陣列代表全部
+    account_id VARCHAR(50),                  -- 只接收此帳號的事件，NULL 代表全部
+    secret VARCHAR(128) NOT NULL,            -- HMAC 簽章 secret
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- Webhook 投遞紀錄 (每個事件對每個訂閱一筆)
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL REFERENCES outbox_events(id),
+    status VARCHAR(20) NOT NULL,           -- pending / delivered / dead
+    attempts INT NOT NULL DEFAULT 0,       -- 已嘗試次數
+    next_attempt_at TIMESTAMPTZ NOT NULL,  -- 下次投遞時間
+    response_status INT,                   -- 最後一次回應的 HTTP status
+    last_error TEXT,                       -- 最後一次失敗原因
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ,
+    UNIQUE (subscription_id, event_id)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+
+-- 帳號事件串流：依帳號查詢事件 (含轉帳轉入)，並在提交時 NOTIFY
+CREATE INDEX IF NOT EXISTS idx_outbox_events_aggregate ON outbox_events (aggregate_id, id);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_to_account ON outbox_events ((payload->>'to_account_id'), id);
+CREATE OR REPLACE FUNCTION notify_account_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('account_events', json_build_object(
+        'id', NEW.id,
+        'type', NEW.event_type,
+        'aggregate_id', NEW.aggregate_id,
+        'request_id', NEW.request_id,
+        'occurred_at', NEW.occurred_at,
+        'data', NEW.payload
+    )::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS outbox_events_notify ON outbox_events;
+CREATE TRIGGER outbox_events_notify AFTER INSERT ON outbox_events
+    FOR EACH ROW EXECUTE FUNCTION notify_account_event();
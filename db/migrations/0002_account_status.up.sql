@@ -0,0 +1,3 @@
+-- 帳號狀態 (凍結)
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'; -- 帳號狀態 (active / frozen)
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS status_reason VARCHAR(255); -- 凍結原因
@@ -0,0 +1,2 @@
+-- 帳號產品 (決定適用的手續費收費表)
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS product VARCHAR(50) NOT NULL DEFAULT 'standard';
@@ -0,0 +1,7 @@
+-- 多個服務實例共用的 rate limit 令牌桶 (RATELIMIT_BACKEND=postgres)
+-- 內容可隨時重建，使用 UNLOGGED 減少 WAL 寫入
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets (
+    key VARCHAR(255) PRIMARY KEY,          -- 路由 + 限制對象 (client / account)
+    tokens DOUBLE PRECISION NOT NULL,      -- 目前剩餘 token
+    updated_at TIMESTAMPTZ NOT NULL        -- 上次補充 token 的時間
+);
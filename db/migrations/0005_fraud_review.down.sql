@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_transactions_account_created;
+DROP TABLE IF EXISTS fraud_decisions;
+DROP TABLE IF EXISTS pending_transfers;
@@ -0,0 +1,37 @@
+-- 風控規則要求人工審核、暫緩執行的轉帳
+CREATE TABLE IF NOT EXISTS pending_transfers (
+    id BIGSERIAL PRIMARY KEY,
+    from_account_id INT NOT NULL REFERENCES accounts(id),
+    to_account_id INT NOT NULL REFERENCES accounts(id),
+    amount NUMERIC(20,2) NOT NULL,
+    reason VARCHAR(50) NOT NULL,                      -- fraud_review
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',    -- pending / approved / rejected
+    requested_by VARCHAR(100) NOT NULL,
+    request_id VARCHAR(64),
+    decided_by VARCHAR(100),
+    decision_note TEXT,
+    ref_id VARCHAR(50),                               -- 核准執行後的交易 ref_id
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_pending_transfers_status ON pending_transfers (status, id);
+
+-- 每筆轉帳的風控決策
+CREATE TABLE IF NOT EXISTS fraud_decisions (
+    id BIGSERIAL PRIMARY KEY,
+    from_account_id INT NOT NULL REFERENCES accounts(id),
+    to_account_id INT NOT NULL REFERENCES accounts(id),
+    amount NUMERIC(20,2) NOT NULL,
+    decision VARCHAR(10) NOT NULL,                    -- allow / review / block
+    rule VARCHAR(50),                                 -- 決定結果的規則
+    reason TEXT,
+    hits JSONB NOT NULL DEFAULT '[]',                 -- 所有觸發的規則
+    pending_transfer_id BIGINT REFERENCES pending_transfers(id),
+    ref_id VARCHAR(50),
+    request_id VARCHAR(64),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_fraud_decisions_from_account ON fraud_decisions (from_account_id, id);
+
+-- 風控規則依轉出帳號查詢近期的轉帳
+CREATE INDEX IF NOT EXISTS idx_transactions_account_created ON transactions (account_id, created_at);
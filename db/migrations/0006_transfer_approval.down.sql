@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS pending_transfer_history;
+DROP INDEX IF EXISTS idx_pending_transfers_expires;
+ALTER TABLE pending_transfers DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE pending_transfers DROP COLUMN IF EXISTS held_amount;
+ALTER TABLE accounts DROP COLUMN IF EXISTS held_balance;
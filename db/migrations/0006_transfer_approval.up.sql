@@ -0,0 +1,19 @@
+-- 待處理轉帳保留的款項，可用餘額 = balance - held_balance
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS held_balance NUMERIC(20,2) NOT NULL DEFAULT 0 CHECK (held_balance >= 0);
+
+-- 大額轉帳覆核：保留款項與逾期時間
+ALTER TABLE pending_transfers ADD COLUMN IF NOT EXISTS held_amount NUMERIC(20,2) NOT NULL DEFAULT 0;
+ALTER TABLE pending_transfers ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_pending_transfers_expires ON pending_transfers (expires_at) WHERE status = 'pending';
+
+-- 待處理轉帳的處理紀錄 (建立、核准、拒絕、失效)
+CREATE TABLE IF NOT EXISTS pending_transfer_history (
+    id BIGSERIAL PRIMARY KEY,
+    pending_transfer_id BIGINT NOT NULL REFERENCES pending_transfers(id),
+    action VARCHAR(20) NOT NULL,                      -- requested / approved / rejected / expired
+    actor VARCHAR(100) NOT NULL,
+    note TEXT,
+    request_id VARCHAR(64),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_pending_transfer_history_transfer ON pending_transfer_history (pending_transfer_id, id);
@@ -0,0 +1,26 @@
+-- 客戶 (KYC)，身分證號僅保存 HMAC 雜湊
+CREATE TABLE IF NOT EXISTS customers (
+    id BIGSERIAL PRIMARY KEY,
+    legal_name VARCHAR(100) NOT NULL,
+    date_of_birth DATE NOT NULL,
+    national_id_hash CHAR(64) NOT NULL UNIQUE,
+    email VARCHAR(255),
+    phone VARCHAR(30),
+    address TEXT,
+    kyc_status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending / verified / rejected
+    kyc_note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 帳號持有人，聯名帳號有多位持有人
+CREATE TABLE IF NOT EXISTS account_holders (
+    account_id INT NOT NULL REFERENCES accounts(id),
+    customer_id BIGINT NOT NULL REFERENCES customers(id),
+    role VARCHAR(20) NOT NULL,                        -- primary / joint
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, customer_id)
+);
+CREATE INDEX IF NOT EXISTS idx_account_holders_customer ON account_holders (customer_id);
+-- 每個帳號只有一位主要持有人
+CREATE UNIQUE INDEX IF NOT EXISTS idx_account_holders_primary ON account_holders (account_id) WHERE role = 'primary';
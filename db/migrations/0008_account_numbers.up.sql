@@ -0,0 +1,37 @@
+-- 對外帳號號碼，API 以此取代 SERIAL id
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS account_number VARCHAR(34);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_account_number ON accounts (account_number);
+
+-- 既有帳號補發 12 位數 Luhn 號碼 (與 account_number 預設設定相同)，重複時重新產生
+DO $$
+DECLARE
+    acc RECORD;
+    body TEXT;
+    total INT;
+    d INT;
+BEGIN
+    FOR acc IN SELECT id FROM accounts WHERE account_number IS NULL ORDER BY id LOOP
+        LOOP
+            body := lpad(floor(random() * 1e11)::BIGINT::TEXT, 11, '0');
+            total := 0;
+            FOR i IN 1..11 LOOP
+                d := substr(body, 12 - i, 1)::INT;
+                IF i % 2 = 1 THEN
+                    d := d * 2;
+                    IF d > 9 THEN
+                        d := d - 9;
+                    END IF;
+                END IF;
+                total := total + d;
+            END LOOP;
+            BEGIN
+                UPDATE accounts SET account_number = body || ((10 - total % 10) % 10)::TEXT WHERE id = acc.id;
+                EXIT;
+            EXCEPTION WHEN unique_violation THEN
+                NULL; -- 號碼重複，重新產生
+            END;
+        END LOOP;
+    END LOOP;
+END $$;
+
+ALTER TABLE accounts ALTER COLUMN account_number SET NOT NULL;
@@ -0,0 +1,15 @@
+-- 收款人 (約定轉入帳號)，上限為 0 代表不限
+-- 新增收款人或提高上限後，冷靜期內適用設定的較低上限
+CREATE TABLE IF NOT EXISTS payees (
+    id BIGSERIAL PRIMARY KEY,
+    account_id INT NOT NULL REFERENCES accounts(id),
+    payee_account_id INT NOT NULL REFERENCES accounts(id),
+    nickname VARCHAR(50) NOT NULL,
+    transfer_limit NUMERIC(15,2) NOT NULL DEFAULT 0, -- 單筆上限
+    daily_limit NUMERIC(15,2) NOT NULL DEFAULT 0,    -- 近 24 小時累計上限
+    cooling_off_until TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (account_id, payee_account_id),
+    UNIQUE (account_id, nickname)
+);
@@ -0,0 +1,9 @@
+-- 餘額快照：balance 為帳號 created_at <= as_of 的所有交易加總
+-- 查詢歷史餘額時由最近一筆快照往後加總交易紀錄，as_of 與 transactions.created_at 同為 TIMESTAMP
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+    account_id INT NOT NULL REFERENCES accounts(id),
+    as_of TIMESTAMP NOT NULL,
+    balance NUMERIC(15,2) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, as_of)
+);
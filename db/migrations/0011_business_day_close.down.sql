@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS transactions_business_date_open ON transactions;
+DROP FUNCTION IF EXISTS transactions_check_business_date();
+DROP TABLE IF EXISTS eod_balances;
+DROP TABLE IF EXISTS business_day_closes;
+DROP FUNCTION IF EXISTS append_only();
+DROP INDEX IF EXISTS idx_transactions_account_business_date;
+ALTER TABLE transactions DROP COLUMN IF EXISTS business_date;
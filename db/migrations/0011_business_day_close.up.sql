@@ -0,0 +1,71 @@
+-- 營業日：交易所屬的會計日期，與實際寫入時間 (created_at) 分開
+-- 既有交易以 DB 時區的日期回填
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS business_date DATE;
+UPDATE transactions SET business_date = created_at::date WHERE business_date IS NULL;
+ALTER TABLE transactions ALTER COLUMN business_date SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_transactions_account_business_date ON transactions (account_id, business_date);
+
+-- 日終結帳紀錄：business_date (含) 之前的營業日皆已結帳
+CREATE TABLE IF NOT EXISTS business_day_closes (
+    business_date DATE PRIMARY KEY,
+    closed_by VARCHAR(100) NOT NULL,       -- 操作者 (自動結帳為 system)
+    closed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 日終快照：每個帳號在已結帳營業日的期初、借貸方合計與期末餘額
+CREATE TABLE IF NOT EXISTS eod_balances (
+    business_date DATE NOT NULL,
+    account_id INT NOT NULL REFERENCES accounts(id),
+    opening_balance NUMERIC(15,2) NOT NULL,
+    total_debits NUMERIC(20,2) NOT NULL,   -- 提款 (type = 1) 合計
+    total_credits NUMERIC(20,2) NOT NULL,  -- 存款 (type = 2) 合計
+    closing_balance NUMERIC(15,2) NOT NULL,
+    transaction_count INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (business_date, account_id)
+);
+
+-- 結帳紀錄與日終快照皆不可修改或刪除
+CREATE OR REPLACE FUNCTION append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION '% is append-only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS business_day_closes_no_modify ON business_day_closes;
+CREATE TRIGGER business_day_closes_no_modify BEFORE UPDATE OR DELETE ON business_day_closes
+    FOR EACH ROW EXECUTE FUNCTION append_only();
+DROP TRIGGER IF EXISTS business_day_closes_no_truncate ON business_day_closes;
+CREATE TRIGGER business_day_closes_no_truncate BEFORE TRUNCATE ON business_day_closes
+    FOR EACH STATEMENT EXECUTE FUNCTION append_only();
+DROP TRIGGER IF EXISTS eod_balances_no_modify ON eod_balances;
+CREATE TRIGGER eod_balances_no_modify BEFORE UPDATE OR DELETE ON eod_balances
+    FOR EACH ROW EXECUTE FUNCTION append_only();
+DROP TRIGGER IF EXISTS eod_balances_no_truncate ON eod_balances;
+CREATE TRIGGER eod_balances_no_truncate BEFORE TRUNCATE ON eod_balances
+    FOR EACH STATEMENT EXECUTE FUNCTION append_only();
+
+-- 禁止寫入、修改或刪除已結帳營業日的交易 (SQLSTATE BD001)
+-- 寫入交易時取得共享 advisory lock，結帳時取得排他鎖，結帳會等待進行中的交易提交後才寫入快照
+CREATE OR REPLACE FUNCTION transactions_check_business_date() RETURNS trigger AS $$
+DECLARE
+    closed DATE;
+BEGIN
+    PERFORM pg_advisory_xact_lock_shared(hashtext('business_day_close'));
+    SELECT MAX(business_date) INTO closed FROM business_day_closes;
+    IF closed IS NOT NULL THEN
+        IF TG_OP <> 'INSERT' AND OLD.business_date <= closed THEN
+            RAISE EXCEPTION 'business date % is closed', OLD.business_date USING ERRCODE = 'BD001';
+        END IF;
+        IF TG_OP <> 'DELETE' AND NEW.business_date <= closed THEN
+            RAISE EXCEPTION 'business date % is closed', NEW.business_date USING ERRCODE = 'BD001';
+        END IF;
+    END IF;
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS transactions_business_date_open ON transactions;
+CREATE TRIGGER transactions_business_date_open BEFORE INSERT OR UPDATE OR DELETE ON transactions
+    FOR EACH ROW EXECUTE FUNCTION transactions_check_business_date();
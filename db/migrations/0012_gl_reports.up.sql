@@ -0,0 +1,2 @@
+-- 總帳報表依營業日加總所有帳號的交易
+CREATE INDEX IF NOT EXISTS idx_transactions_business_date ON transactions (business_date);
@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_outbox_events_to_account_number;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_to_account ON outbox_events ((payload->>'to_account_id'), id);
+
+UPDATE outbox_events e SET payload = (e.payload - 'to_account_number') || jsonb_build_object('to_account_id', a.id::TEXT)
+    FROM accounts a WHERE e.payload->>'to_account_number' = a.account_number;
+UPDATE outbox_events e SET payload = (e.payload - 'from_account_number') || jsonb_build_object('from_account_id', a.id::TEXT)
+    FROM accounts a WHERE e.payload->>'from_account_number' = a.account_number;
+UPDATE outbox_events e SET payload = (e.payload - 'account_number') || jsonb_build_object('account_id', a.id::TEXT)
+    FROM accounts a WHERE e.payload->>'account_number' = a.account_number;
+UPDATE outbox_events e SET aggregate_id = a.id::TEXT
+    FROM accounts a WHERE e.aggregate_id = a.account_number;
+
+UPDATE webhook_subscriptions s SET account_number = a.id::TEXT
+    FROM accounts a WHERE s.account_number = a.account_number;
+ALTER TABLE webhook_subscriptions ALTER COLUMN account_number TYPE VARCHAR(50);
+ALTER TABLE webhook_subscriptions RENAME COLUMN account_number TO account_id;
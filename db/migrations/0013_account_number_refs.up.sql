@@ -0,0 +1,18 @@
+-- 事件、webhook 訂閱改以帳號號碼識別帳號，不再對外露出 SERIAL id
+ALTER TABLE webhook_subscriptions RENAME COLUMN account_id TO account_number;
+ALTER TABLE webhook_subscriptions ALTER COLUMN account_number TYPE VARCHAR(34);
+UPDATE webhook_subscriptions s SET account_number = a.account_number
+    FROM accounts a WHERE s.account_number = a.id::TEXT;
+
+-- 既有事件的 aggregate_id 與內容中的帳號 ID 改為帳號號碼
+UPDATE outbox_events e SET aggregate_id = a.account_number
+    FROM accounts a WHERE e.aggregate_id = a.id::TEXT;
+UPDATE outbox_events e SET payload = (e.payload - 'account_id') || jsonb_build_object('account_number', a.account_number)
+    FROM accounts a WHERE e.payload->>'account_id' = a.id::TEXT;
+UPDATE outbox_events e SET payload = (e.payload - 'from_account_id') || jsonb_build_object('from_account_number', a.account_number)
+    FROM accounts a WHERE e.payload->>'from_account_id' = a.id::TEXT;
+UPDATE outbox_events e SET payload = (e.payload - 'to_account_id') || jsonb_build_object('to_account_number', a.account_number)
+    FROM accounts a WHERE e.payload->>'to_account_id' = a.id::TEXT;
+
+DROP INDEX IF EXISTS idx_outbox_events_to_account;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_to_account_number ON outbox_events ((payload->>'to_account_number'), id);
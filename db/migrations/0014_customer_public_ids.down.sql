@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_customers_public_id;
+ALTER TABLE customers DROP COLUMN IF EXISTS public_id;
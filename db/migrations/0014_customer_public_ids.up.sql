@@ -0,0 +1,3 @@
+-- 客戶以隨機 UUID 對外識別，不以流水號回傳，避免猜測其他客戶的 ID
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS public_id UUID NOT NULL DEFAULT gen_random_uuid();
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_public_id ON customers (public_id);
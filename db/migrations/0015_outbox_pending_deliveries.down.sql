@@ -0,0 +1,10 @@
+ALTER TABLE webhook_deliveries DROP CONSTRAINT IF EXISTS webhook_deliveries_event_id_fkey,
+    ADD CONSTRAINT webhook_deliveries_event_id_fkey FOREIGN KEY (event_id) REFERENCES outbox_events(id);
+ALTER TABLE outbox_deliveries DROP CONSTRAINT IF EXISTS outbox_deliveries_event_id_fkey,
+    ADD CONSTRAINT outbox_deliveries_event_id_fkey FOREIGN KEY (event_id) REFERENCES outbox_events(id);
+DROP INDEX IF EXISTS idx_outbox_events_occurred_at;
+
+DROP INDEX IF EXISTS idx_outbox_deliveries_due;
+DELETE FROM outbox_deliveries WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_outbox_deliveries_retry ON outbox_deliveries (sink, status, next_attempt_at);
+DROP TABLE IF EXISTS outbox_sinks;
@@ -0,0 +1,20 @@
+-- 寫入事件時即為每個已登記的 sink 建立待投遞紀錄，relay 只鎖定自己 sink 的紀錄，不再掃描 outbox_events
+CREATE TABLE IF NOT EXISTS outbox_sinks (
+    name VARCHAR(50) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+INSERT INTO outbox_sinks (name) SELECT DISTINCT sink FROM outbox_deliveries ON CONFLICT DO NOTHING;
+-- 既有 sink 尚未投遞過的事件補上待投遞紀錄
+INSERT INTO outbox_deliveries (event_id, sink, status, next_attempt_at)
+    SELECT e.id, s.name, 'pending', NOW() FROM outbox_events e CROSS JOIN outbox_sinks s
+    ON CONFLICT (event_id, sink) DO NOTHING;
+
+DROP INDEX IF EXISTS idx_outbox_deliveries_retry;
+CREATE INDEX IF NOT EXISTS idx_outbox_deliveries_due ON outbox_deliveries (sink, event_id) WHERE status IN ('pending', 'retrying');
+
+-- 超過保留期限的事件連同投遞紀錄一併刪除
+CREATE INDEX IF NOT EXISTS idx_outbox_events_occurred_at ON outbox_events (occurred_at);
+ALTER TABLE outbox_deliveries DROP CONSTRAINT IF EXISTS outbox_deliveries_event_id_fkey,
+    ADD CONSTRAINT outbox_deliveries_event_id_fkey FOREIGN KEY (event_id) REFERENCES outbox_events(id) ON DELETE CASCADE;
+ALTER TABLE webhook_deliveries DROP CONSTRAINT IF EXISTS webhook_deliveries_event_id_fkey,
+    ADD CONSTRAINT webhook_deliveries_event_id_fkey FOREIGN KEY (event_id) REFERENCES outbox_events(id) ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_fraud_decisions_blocked;
+DROP INDEX IF EXISTS idx_pending_transfers_from_account_created;
+DROP INDEX IF EXISTS idx_transactions_account_kind_created;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_kind_check;
+ALTER TABLE transactions DROP COLUMN IF EXISTS kind;
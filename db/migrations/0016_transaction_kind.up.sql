@@ -0,0 +1,25 @@
+-- 交易種類：取代以說明前綴區分轉帳、手續費與期初餘額
+-- deposit / withdrawal / opening / transfer_out / transfer_in / fee
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS kind VARCHAR(20);
+
+-- 既有交易依說明前綴回填；已結帳營業日的交易不可修改，回填期間暫停檢查
+ALTER TABLE transactions DISABLE TRIGGER transactions_business_date_open;
+UPDATE transactions SET kind = CASE
+        WHEN description LIKE 'Transfer to %' THEN 'transfer_out'
+        WHEN description LIKE 'Transfer from %' THEN 'transfer_in'
+        WHEN description LIKE 'Fee: %' THEN 'fee'
+        WHEN description = 'Opening balance' THEN 'opening'
+        WHEN type = 1 THEN 'withdrawal'
+        ELSE 'deposit'
+    END
+WHERE kind IS NULL;
+ALTER TABLE transactions ENABLE TRIGGER transactions_business_date_open;
+
+ALTER TABLE transactions ALTER COLUMN kind SET NOT NULL;
+ALTER TABLE transactions ADD CONSTRAINT transactions_kind_check
+    CHECK (kind IN ('deposit', 'withdrawal', 'opening', 'transfer_out', 'transfer_in', 'fee'));
+CREATE INDEX IF NOT EXISTS idx_transactions_account_kind_created ON transactions (account_id, kind, created_at);
+
+-- 風控規則另外計入待處理與被拒絕的轉帳
+CREATE INDEX IF NOT EXISTS idx_pending_transfers_from_account_created ON pending_transfers (from_account_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_fraud_decisions_blocked ON fraud_decisions (from_account_id, created_at) WHERE decision = 'block';
@@ -0,0 +1,20 @@
+ALTER TABLE transactions DISABLE TRIGGER transactions_business_date_open;
+ALTER TABLE eod_balances DISABLE TRIGGER eod_balances_no_modify;
+
+WITH backfill AS (
+    DELETE FROM transactions WHERE kind = 'opening' AND ref_id LIKE 'opening-backfill-%'
+    RETURNING account_id, business_date, created_at, CASE WHEN type = 2 THEN amount ELSE -amount END AS diff
+), snapshots AS (
+    UPDATE balance_snapshots s SET balance = s.balance - b.diff
+    FROM backfill b WHERE s.account_id = b.account_id AND s.as_of >= b.created_at
+)
+UPDATE eod_balances e SET
+    opening_balance = e.opening_balance - CASE WHEN e.business_date > b.business_date THEN b.diff ELSE 0 END,
+    total_credits = e.total_credits - CASE WHEN e.business_date = b.business_date AND b.diff > 0 THEN b.diff ELSE 0 END,
+    total_debits = e.total_debits - CASE WHEN e.business_date = b.business_date AND b.diff < 0 THEN -b.diff ELSE 0 END,
+    transaction_count = e.transaction_count - CASE WHEN e.business_date = b.business_date THEN 1 ELSE 0 END,
+    closing_balance = e.closing_balance - b.diff
+FROM backfill b WHERE e.account_id = b.account_id AND e.business_date >= b.business_date;
+
+ALTER TABLE eod_balances ENABLE TRIGGER eod_balances_no_modify;
+ALTER TABLE transactions ENABLE TRIGGER transactions_business_date_open;
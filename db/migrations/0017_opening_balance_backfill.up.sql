@@ -0,0 +1,34 @@
+-- 期初餘額交易之前開立的帳號，餘額沒有對應的交易紀錄；歷史餘額、日終快照、試算表與對帳皆以交易加總計算
+-- 為這些帳號補一筆 opening 交易 (餘額與交易加總的差額，日期為帳號建立時間)，
+-- 並將差額補入之後已建立的餘額快照與日終快照。回填期間暫停已結帳營業日與日終快照的檢查
+ALTER TABLE transactions DISABLE TRIGGER transactions_business_date_open;
+ALTER TABLE eod_balances DISABLE TRIGGER eod_balances_no_modify;
+
+WITH backfill AS (
+    INSERT INTO transactions (account_id, type, kind, amount, ref_id, description, business_date, created_at)
+    SELECT a.id, CASE WHEN l.diff > 0 THEN 2 ELSE 1 END, 'opening', ABS(l.diff),
+        'opening-backfill-' || a.id, 'Opening balance', o.at::date, o.at
+    FROM accounts a
+    CROSS JOIN LATERAL (
+        SELECT a.balance - COALESCE(SUM(CASE WHEN type = 2 THEN amount ELSE -amount END), 0) AS diff,
+            MIN(created_at) AS first_at
+        FROM transactions WHERE account_id = a.id
+    ) l
+    CROSS JOIN LATERAL (SELECT COALESCE(LEAST(a.created_at, l.first_at), NOW()::timestamp) AS at) o
+    WHERE l.diff <> 0
+        AND NOT EXISTS (SELECT 1 FROM transactions WHERE account_id = a.id AND kind = 'opening')
+    RETURNING account_id, business_date, created_at, CASE WHEN type = 2 THEN amount ELSE -amount END AS diff
+), snapshots AS (
+    UPDATE balance_snapshots s SET balance = s.balance + b.diff
+    FROM backfill b WHERE s.account_id = b.account_id AND s.as_of >= b.created_at
+)
+UPDATE eod_balances e SET
+    opening_balance = e.opening_balance + CASE WHEN e.business_date > b.business_date THEN b.diff ELSE 0 END,
+    total_credits = e.total_credits + CASE WHEN e.business_date = b.business_date AND b.diff > 0 THEN b.diff ELSE 0 END,
+    total_debits = e.total_debits + CASE WHEN e.business_date = b.business_date AND b.diff < 0 THEN -b.diff ELSE 0 END,
+    transaction_count = e.transaction_count + CASE WHEN e.business_date = b.business_date THEN 1 ELSE 0 END,
+    closing_balance = e.closing_balance + b.diff
+FROM backfill b WHERE e.account_id = b.account_id AND e.business_date >= b.business_date;
+
+ALTER TABLE eod_balances ENABLE TRIGGER eod_balances_no_modify;
+ALTER TABLE transactions ENABLE TRIGGER transactions_business_date_open;
@@ -0,0 +1,3 @@
+-- 匯入帳號時記錄舊系統的帳號識別，重新匯入同一檔案時略過已建立的列
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS external_id VARCHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_external_id ON accounts (external_id) WHERE external_id IS NOT NULL;
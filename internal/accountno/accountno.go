@@ -0,0 +1,76 @@
+package accountno
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Generate 產生新的帳號號碼，隨機部分使用 crypto/rand，無法由號碼推得帳號數量
+// 唯一性由資料庫的 unique 限制保證，重複時由呼叫端重新產生
+func (c Config) Generate() (string, error) {
+	n := c.Length - len(c.Prefix) - c.checkLen()
+	var b strings.Builder
+	b.WriteString(c.Prefix)
+	for range n {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", fmt.Errorf("generate account number: %w", err)
+		}
+		b.WriteByte(byte('0' + d.Int64()))
+	}
+	body := b.String()
+	return body + c.check(body), nil
+}
+
+// Valid 號碼的長度、前綴與檢查碼是否符合設定
+func (c Config) Valid(number string) bool {
+	if len(number) != c.Length || !digits(number) || !strings.HasPrefix(number, c.Prefix) {
+		return false
+	}
+	split := len(number) - c.checkLen()
+	return c.check(number[:split]) == number[split:]
+}
+
+// 依演算法計算檢查碼
+func (c Config) check(body string) string {
+	if c.Scheme == SchemeMod97 {
+		return fmt.Sprintf("%02d", 98-mod97(body+"00"))
+	}
+	return string(byte('0' + luhn(body)))
+}
+
+// luhn 由右至左，奇數位 (檢查碼左側第一位起) 乘 2，超過 9 減 9，檢查碼使總和為 10 的倍數
+func luhn(body string) int {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		d := int(body[len(body)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+// mod97 逐位計算數字字串除以 97 的餘數，避免超過整數範圍
+func mod97(s string) int {
+	r := 0
+	for i := 0; i < len(s); i++ {
+		r = (r*10 + int(s[i]-'0')) % 97
+	}
+	return r
+}
+
+func digits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package accountno
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 單元測試 已知的 Luhn 與 mod-97 號碼
+func TestValid_KnownNumbers(t *testing.T) {
+	// 常見的 Luhn 測試卡號
+	luhnCfg := Config{Scheme: SchemeLuhn, Length: 16}
+	assert.True(t, luhnCfg.Valid("4111111111111111"))
+	assert.False(t, luhnCfg.Valid("4111111111111112"))
+
+	// ISO 7064 MOD 97-10：整個號碼除以 97 餘 1
+	mod97Cfg := Config{Scheme: SchemeMod97, Length: 10}
+	number := "12345678" + mod97Cfg.check("12345678")
+	assert.Equal(t, 1, mod97(number))
+	assert.True(t, mod97Cfg.Valid(number))
+}
+
+// 單元測試 產生的號碼皆可通過驗證，且能偵測單一數字錯誤與 (大部分的) 相鄰數字對調
+func TestGenerate(t *testing.T) {
+	for _, cfg := range []Config{
+		DefaultConfig(),
+		{Scheme: SchemeMod97, Prefix: "812", Length: 14},
+	} {
+		assert.NoError(t, cfg.Validate())
+		for range 50 {
+			number, err := cfg.Generate()
+			assert.NoError(t, err)
+			assert.Len(t, number, cfg.Length)
+			assert.True(t, cfg.Valid(number), number)
+			assert.Equal(t, cfg.Prefix, number[:len(cfg.Prefix)])
+
+			typo := []byte(number)
+			typo[len(typo)-3] = '0' + (typo[len(typo)-3]-'0'+1)%10
+			assert.False(t, cfg.Valid(string(typo)), "single digit error %s", typo)
+			// Luhn 無法偵測 09 與 90 對調
+			if pair := number[4:6]; number[4] != number[5] && pair != "09" && pair != "90" {
+				swapped := []byte(number)
+				swapped[4], swapped[5] = swapped[5], swapped[4]
+				assert.False(t, cfg.Valid(string(swapped)), "transposition %s", swapped)
+			}
+		}
+	}
+	assert.False(t, DefaultConfig().Valid("12345"))
+	assert.False(t, DefaultConfig().Valid("12345678901a"))
+}
+
+func TestValidate(t *testing.T) {
+	err := Config{Scheme: "crc", Prefix: "AB", Length: 40}.Validate()
+	assert.ErrorContains(t, err, "account_number.scheme")
+	assert.ErrorContains(t, err, "account_number.prefix")
+	assert.ErrorContains(t, err, "account_number.length")
+	assert.ErrorContains(t, Config{Scheme: SchemeMod97, Prefix: "1234", Length: 10}.Validate(), "at least 6 random digits")
+}
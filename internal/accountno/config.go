@@ -0,0 +1,56 @@
+package accountno
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 檢查碼演算法
+const (
+	SchemeLuhn  = "luhn"  // 1 位檢查碼 (信用卡號使用的 Luhn mod 10)
+	SchemeMod97 = "mod97" // 2 位檢查碼 (IBAN 使用的 ISO 7064 MOD 97-10)
+)
+
+// 帳號號碼長度上限 (對應 VARCHAR(34)，與 IBAN 相同)
+const (
+	MinLength = 8
+	MaxLength = 34
+)
+
+// Config 對外帳號號碼格式：Prefix + 隨機數字 + 檢查碼，總長度為 Length
+// 已發出的號碼仍以目前的設定驗證，有帳號後不應更改演算法、前綴與長度
+type Config struct {
+	Scheme string `yaml:"scheme" toml:"scheme" env:"ACCOUNT_NUMBER_SCHEME"`
+	Prefix string `yaml:"prefix" toml:"prefix" env:"ACCOUNT_NUMBER_PREFIX"` // 固定前綴 (例如分行代碼)，僅能為數字
+	Length int    `yaml:"length" toml:"length" env:"ACCOUNT_NUMBER_LENGTH"`
+}
+
+// DefaultConfig 預設為 12 位數 Luhn 號碼 (與 migration 補發既有帳號的格式相同)
+func DefaultConfig() Config {
+	return Config{Scheme: SchemeLuhn, Length: 12}
+}
+
+// Validate 檢查演算法、前綴與長度，隨機部分至少需 6 位數
+func (c Config) Validate() error {
+	var errs []error
+	if c.Scheme != SchemeLuhn && c.Scheme != SchemeMod97 {
+		errs = append(errs, fmt.Errorf("account_number.scheme must be %s or %s", SchemeLuhn, SchemeMod97))
+	}
+	if !digits(c.Prefix) {
+		errs = append(errs, errors.New("account_number.prefix must contain only digits"))
+	}
+	if c.Length < MinLength || c.Length > MaxLength {
+		errs = append(errs, fmt.Errorf("account_number.length must be between %d and %d", MinLength, MaxLength))
+	} else if c.Length-len(c.Prefix)-c.checkLen() < 6 {
+		errs = append(errs, errors.New("account_number.length must leave at least 6 random digits after the prefix and check digits"))
+	}
+	return errors.Join(errs...)
+}
+
+// 檢查碼位數
+func (c Config) checkLen() int {
+	if c.Scheme == SchemeMod97 {
+		return 2
+	}
+	return 1
+}
@@ -1,9 +1,13 @@
 package api
 
 import (
-	"encoding/json"
+	"database/sql"
+	"errors"
 	"net/http"
 
+	"github.com/yoyo0827/simple-bank-system/internal/csvimport"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
 	"github.com/yoyo0827/simple-bank-system/internal/request"
 	"github.com/yoyo0827/simple-bank-system/internal/response"
 	"github.com/yoyo0827/simple-bank-system/internal/service"
@@ -11,27 +15,36 @@ import (
 
 type ApiHandler struct {
 	AccountService *service.AccountService
+	Validator      *request.Validator
 }
 
 // CreateAccount godoc
 // @Summary 建立帳號
-// @Description 建立一個新的帳號，初始餘額必須 >= 0
+// @Description 建立一個新的帳號，初始餘額必須 >= 0；可指定持有的客戶 (第一位為主要持有人，其餘為聯名持有人)，啟用 KYC 時客戶皆需已通過驗證
 // @Tags 帳號相關
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param account body request.CreateAccountRequest true "Account Info"
 // @Success 200 {object} response.ApiResponse
+// @Failure 403 {object} response.ApiResponse "Customer KYC is not verified"
+// @Failure 422 {object} response.ApiResponse
+// @Failure 429 {object} response.ApiResponse "Rate limit exceeded"
 // @Router /accounts [post]
 func (h *ApiHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	var req request.CreateAccountRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.WriteError(w, http.StatusBadRequest, err.Error())
+	if err := h.Validator.Decode(r.Body, &req); err != nil {
+		writeRequestError(w, err)
 		return
 	}
 
-	acc, err := h.AccountService.CreateAccount(req.Name, req.Balance)
+	acc, err := h.AccountService.CreateAccount(r.Context(), &req)
 	if err != nil {
+		if errors.Is(err, service.ErrKYCNotVerified) {
+			response.WriteError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		response.WriteError(w, http.StatusNotFound, err.Error())
 		return
 	}
@@ -41,18 +54,19 @@ func (h *ApiHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 
 // findAccount godoc
 // @Summary 查詢帳號
-// @Description 根據帳號 ID 查詢帳號資訊
+// @Description 根據帳號號碼查詢帳號資訊
 // @Tags 帳號相關
 // @Accept json
 // @Produce json
-// @Param id path int true "Account ID"
-// @Success 200 {object} response.ApiResponse
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
+// @Success 200 {object} response.ApiResponse{data=domain.Account}
+// @Failure 404 {object} response.ApiResponse
+// @Failure 422 {object} response.ApiResponse "Invalid account number"
 // @Router /accounts/{id} [get]
 func (h *ApiHandler) FindAccount(w http.ResponseWriter, r *http.Request) {
-	id := r.PathValue("id")
-	acc, err := h.AccountService.FindAccount(id)
-	if err != nil {
-		response.WriteError(w, http.StatusNotFound, err.Error())
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
 		return
 	}
 
@@ -65,20 +79,28 @@ func (h *ApiHandler) FindAccount(w http.ResponseWriter, r *http.Request) {
 // @Tags 交易相關
 // @Accept json
 // @Produce json
-// @Param id path int true "Account ID"
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
 // @Param transaction body request.TransactionRequest true "Transaction Info"
 // @Success 200 {object} response.ApiResponse
+// @Failure 409 {object} response.ApiResponse "Account is frozen"
+// @Failure 422 {object} response.ApiResponse
+// @Failure 429 {object} response.ApiResponse "Rate limit exceeded"
+// @Failure 500 {object} response.ApiResponse "Fee revenue account is misconfigured"
 // @Router /accounts/{id}/transactions [post]
 func (h *ApiHandler) CreateTransaction(w http.ResponseWriter, r *http.Request) {
-	id := r.PathValue("id")
 	var req request.TransactionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.WriteError(w, http.StatusBadRequest, err.Error())
+	if err := h.Validator.Decode(r.Body, &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
 		return
 	}
-	refID, err := h.AccountService.CreateTransaction(id, &req)
+	refID, err := h.AccountService.CreateTransaction(r.Context(), acc.ID, &req)
 	if err != nil {
-		response.WriteError(w, http.StatusBadRequest, err.Error())
+		writeTransactionError(w, err)
 		return
 	}
 	response.WriteSuccess(w, http.StatusOK, map[string]string{"ref_id": refID})
@@ -86,42 +108,331 @@ func (h *ApiHandler) CreateTransaction(w http.ResponseWriter, r *http.Request) {
 
 // Transfer godoc
 // @Summary 轉帳
-// @Description 由指定帳號進行轉帳操作
+// @Description 由指定帳號進行轉帳操作，轉入帳號以 to_account 或轉出帳號的收款人 payee_id 擇一指定；轉入帳號為收款人時適用其上限
 // @Tags 交易相關
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param transaction body request.TransferRequest true "Transfer Info"
-// @Success 200 {object} response.ApiResponse
+// @Success 200 {object} response.ApiResponse{data=domain.TransferResult}
+// @Success 202 {object} response.ApiResponse{data=domain.TransferResult} "Held for fraud review or approval"
+// @Failure 403 {object} response.ApiResponse "Blocked by a fraud rule, holder KYC is not verified or payee limit exceeded"
+// @Failure 404 {object} response.ApiResponse "Payee not found"
+// @Failure 409 {object} response.ApiResponse "Account is frozen"
+// @Failure 422 {object} response.ApiResponse
+// @Failure 429 {object} response.ApiResponse "Rate limit exceeded"
+// @Failure 500 {object} response.ApiResponse "Fee revenue account is misconfigured"
 // @Router /accounts/transfer [post]
 func (h *ApiHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
 	var req request.TransferRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.WriteError(w, http.StatusBadRequest, err.Error())
+	if err := h.Validator.Decode(r.Body, &req); err != nil {
+		writeRequestError(w, err)
 		return
 	}
-	refID, err := h.AccountService.Transfer(&req)
+	res, err := h.AccountService.Transfer(r.Context(), &req)
 	if err != nil {
-		response.WriteError(w, http.StatusBadRequest, err.Error())
+		writeTransactionError(w, err)
 		return
 	}
-	response.WriteSuccess(w, http.StatusOK, map[string]string{"ref_id": refID})
+	// 風控規則要求審核或需覆核時尚未執行
+	if res.Status != domain.TransferStatusCompleted {
+		response.WriteSuccess(w, http.StatusAccepted, res)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, res)
 }
 
 // TransactionDetail godoc
 // @Summary 取得交易紀錄
-// @Description 取得指定交易的詳細資訊
+// @Description 取得指定帳號的交易紀錄，可依類型與時間區間篩選，以 after_id 分頁
 // @Tags 交易相關
 // @Accept json
 // @Produce json
-// @Param id path int true "Account ID"
-// @Success 200 {object} response.ApiResponse
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
+// @Param type query string false "Transaction type" Enums(deposit, withdrawal)
+// @Param from query string false "From (RFC 3339)"
+// @Param to query string false "To (RFC 3339, exclusive)"
+// @Param after_id query int false "Return transactions after this ID"
+// @Param limit query int false "Max transactions (max 500)"
+// @Success 200 {object} response.ApiResponse{data=[]domain.Transaction}
+// @Failure 422 {object} response.ApiResponse
 // @Router /accounts/{id}/transactions [get]
 func (h *ApiHandler) FindTransactionDetail(w http.ResponseWriter, r *http.Request) {
-	id := r.PathValue("id")
-	transactions, err := h.AccountService.FindAccountTransactions(id)
+	var req request.TransactionQueryRequest
+	if err := h.Validator.DecodeQuery(r.URL.Query(), &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
+		return
+	}
+	filter := repository.TransactionFilter{From: req.From, To: req.To, AfterID: req.AfterID, Limit: req.Limit}
+	switch req.Type {
+	case "withdrawal":
+		filter.Type = 1
+	case "deposit":
+		filter.Type = 2
+	}
+	transactions, err := h.AccountService.FindAccountTransactions(r.Context(), acc.ID, filter)
 	if err != nil {
 		response.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	response.WriteSuccess(w, http.StatusOK, transactions)
 }
+
+// Statement godoc
+// @Summary 取得對帳單
+// @Description 取得指定帳號在 [from, to) 期間的期初 / 期末餘額、存提款合計與交易明細
+// @Tags 交易相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
+// @Param from query string false "From (RFC 3339)"
+// @Param to query string false "To (RFC 3339, exclusive)"
+// @Success 200 {object} response.ApiResponse{data=domain.Statement}
+// @Failure 404 {object} response.ApiResponse
+// @Failure 422 {object} response.ApiResponse
+// @Router /accounts/{id}/statement [get]
+func (h *ApiHandler) Statement(w http.ResponseWriter, r *http.Request) {
+	var req request.StatementQueryRequest
+	if err := h.Validator.DecodeQuery(r.URL.Query(), &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
+		return
+	}
+	st, err := h.AccountService.Statement(r.Context(), acc.ID, req.From, req.To)
+	if err != nil {
+		writeAccountError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, st)
+}
+
+// BalanceAt godoc
+// @Summary 查詢歷史餘額
+// @Description 取得指定帳號在 at 時 (含) 的餘額，由交易紀錄與定期餘額快照計算；未指定 at 時為目前時間
+// @Tags 交易相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
+// @Param at query string false "Point in time (RFC 3339, inclusive)"
+// @Success 200 {object} response.ApiResponse{data=domain.HistoricalBalance}
+// @Failure 400 {object} response.ApiResponse "at is in the future"
+// @Failure 404 {object} response.ApiResponse
+// @Failure 422 {object} response.ApiResponse
+// @Router /accounts/{id}/balance [get]
+func (h *ApiHandler) BalanceAt(w http.ResponseWriter, r *http.Request) {
+	var req request.BalanceQueryRequest
+	if err := h.Validator.DecodeQuery(r.URL.Query(), &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
+		return
+	}
+	balance, err := h.AccountService.BalanceAt(r.Context(), acc.ID, req.At)
+	if err != nil {
+		writeAccountError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, balance)
+}
+
+// PreviewFee godoc
+// @Summary 試算手續費
+// @Description 依帳號產品與收費表試算提款或轉帳的手續費，供使用者確認前顯示實際扣款金額
+// @Tags 交易相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
+// @Param operation query string true "Operation" Enums(withdrawal, transfer)
+// @Param amount query string true "Amount"
+// @Success 200 {object} response.ApiResponse{data=domain.FeePreview}
+// @Failure 404 {object} response.ApiResponse
+// @Failure 422 {object} response.ApiResponse
+// @Router /accounts/{id}/fees/preview [get]
+func (h *ApiHandler) PreviewFee(w http.ResponseWriter, r *http.Request) {
+	var req request.FeePreviewRequest
+	if err := h.Validator.DecodeQuery(r.URL.Query(), &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
+		return
+	}
+	preview, err := h.AccountService.PreviewFee(r.Context(), acc.ID, req.Operation, req.Amount)
+	if err != nil {
+		writeAccountError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, preview)
+}
+
+// FreezeAccount godoc
+// @Summary 凍結帳號
+// @Description 凍結指定帳號，凍結期間不可存款、提款或轉帳 (需 admin 權限)
+// @Tags 管理相關
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
+// @Param request body request.FreezeAccountRequest true "Freeze reason"
+// @Success 200 {object} response.ApiResponse{data=domain.Account}
+// @Failure 404 {object} response.ApiResponse
+// @Failure 422 {object} response.ApiResponse
+// @Router /admin/accounts/{id}/freeze [post]
+func (h *ApiHandler) FreezeAccount(w http.ResponseWriter, r *http.Request) {
+	var req request.FreezeAccountRequest
+	if err := h.Validator.Decode(r.Body, &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
+		return
+	}
+	acc, err := h.AccountService.SetAccountStatus(r.Context(), acc.ID, domain.AccountFrozen, req.Reason)
+	if err != nil {
+		writeAccountError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, acc)
+}
+
+// UnfreezeAccount godoc
+// @Summary 解除凍結
+// @Description 解除指定帳號的凍結 (需 admin 權限)
+// @Tags 管理相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
+// @Success 200 {object} response.ApiResponse{data=domain.Account}
+// @Failure 404 {object} response.ApiResponse
+// @Router /admin/accounts/{id}/unfreeze [post]
+func (h *ApiHandler) UnfreezeAccount(w http.ResponseWriter, r *http.Request) {
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
+		return
+	}
+	acc, err := h.AccountService.SetAccountStatus(r.Context(), acc.ID, domain.AccountActive, "")
+	if err != nil {
+		writeAccountError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, acc)
+}
+
+// ImportAccounts godoc
+// @Summary 匯入帳號
+// @Description 從 CSV (欄位 name、balance、external_id、product、customer_ids) 匯入帳號與期初餘額，所有列皆驗證通過後才分批開戶；dry_run 時只驗證並回報錯誤，大型檔案請使用 import-accounts 子命令 (需 admin 權限)
+// @Tags 管理相關
+// @Accept text/csv
+// @Produce json
+// @Security ApiKeyAuth
+// @Param dry_run query bool false "Validate only, do not create accounts"
+// @Param file body string true "CSV file"
+// @Success 200 {object} response.ApiResponse{data=domain.AccountImport}
+// @Failure 400 {object} response.ApiResponse "Invalid CSV header"
+// @Failure 409 {object} response.ApiResponse{data=domain.AccountImport} "Business date is closed, earlier batches were created"
+// @Failure 413 {object} response.ApiResponse
+// @Failure 422 {object} response.ApiResponse{data=domain.AccountImport} "Invalid rows, no accounts created"
+// @Failure 500 {object} response.ApiResponse{data=domain.AccountImport} "Import stopped, earlier batches were created"
+// @Router /admin/accounts/import [post]
+func (h *ApiHandler) ImportAccounts(w http.ResponseWriter, r *http.Request) {
+	var req request.AccountImportQueryRequest
+	if err := h.Validator.DecodeQuery(r.URL.Query(), &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	result, err := h.AccountService.ImportAccounts(r.Context(), r.Body, req.DryRun)
+	if errors.Is(err, service.ErrImportInvalid) {
+		response.WriteErrorData(w, http.StatusUnprocessableEntity, err.Error(), result)
+		return
+	}
+	if errors.Is(err, service.ErrImportIncomplete) {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrBusinessDateClosed) {
+			status = http.StatusConflict
+		}
+		response.WriteErrorData(w, status, err.Error(), result)
+		return
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.Is(err, csvimport.ErrInvalidHeader) || errors.As(err, &tooLarge) {
+		writeRequestError(w, err)
+		return
+	}
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, result)
+}
+
+// 以路徑中的帳號號碼查詢帳號，號碼格式或檢查碼錯誤回 422，不存在回 404
+func pathAccount(w http.ResponseWriter, r *http.Request, v *request.Validator, accounts *service.AccountService) (*domain.Account, bool) {
+	number := r.PathValue("id")
+	if err := v.ValidateAccountNumber("id", number); err != nil {
+		writeRequestError(w, err)
+		return nil, false
+	}
+	acc, err := accounts.FindAccountByNumber(r.Context(), number)
+	if err != nil {
+		writeAccountError(w, err)
+		return nil, false
+	}
+	return acc, true
+}
+
+// 存提款與轉帳失敗：手續費設定錯誤回 500，帳號凍結回 409，風控拒絕或超過收款人上限回 403，收款人不存在回 404，其餘回 400
+func writeTransactionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrFeeRevenueAccountMissing) {
+		response.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrAccountFrozen) || errors.Is(err, service.ErrBusinessDateClosed) {
+		response.WriteError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrTransferBlocked) || errors.Is(err, service.ErrKYCNotVerified) || errors.Is(err, service.ErrPayeeLimitExceeded) {
+		response.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrPayeeNotFound) {
+		response.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	response.WriteError(w, http.StatusBadRequest, err.Error())
+}
+
+// 帳號查詢失敗：不存在回 404，其餘回 400
+func writeAccountError(w http.ResponseWriter, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		response.WriteError(w, http.StatusNotFound, "account not found")
+		return
+	}
+	response.WriteError(w, http.StatusBadRequest, err.Error())
+}
+
+// 請求解析失敗：驗證錯誤回 422，body 過大回 413，其餘 (JSON 格式、未知欄位) 回 400
+func writeRequestError(w http.ResponseWriter, err error) {
+	var verrs request.ValidationErrors
+	if errors.As(err, &verrs) {
+		response.WriteValidationError(w, verrs)
+		return
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		response.WriteError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+	response.WriteError(w, http.StatusBadRequest, err.Error())
+}
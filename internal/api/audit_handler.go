@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/response"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+)
+
+// 未指定 limit 時的預設筆數
+const defaultAuditLimit = 100
+
+type AuditHandler struct {
+	AuditService *service.AuditService
+	Validator    *request.Validator
+}
+
+// FindAuditEvents godoc
+// @Summary 查詢稽核紀錄
+// @Description 依實體、操作者、動作與時間區間查詢稽核紀錄 (需 admin 權限)，以 after_id 分頁
+// @Tags 管理相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Param entity_type query string false "Entity type (e.g. account)"
+// @Param entity_id query string false "Entity ID"
+// @Param actor query string false "Actor"
+// @Param action query string false "Action (e.g. account.balance_changed)"
+// @Param from query string false "From (RFC 3339)"
+// @Param to query string false "To (RFC 3339, exclusive)"
+// @Param after_id query int false "Return events after this ID"
+// @Param limit query int false "Max events (default 100, max 500)"
+// @Success 200 {object} response.ApiResponse{data=[]domain.AuditEvent}
+// @Failure 422 {object} response.ApiResponse
+// @Router /admin/audit [get]
+func (h *AuditHandler) FindAuditEvents(w http.ResponseWriter, r *http.Request) {
+	var req request.AuditQueryRequest
+	if err := h.Validator.DecodeQuery(r.URL.Query(), &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultAuditLimit
+	}
+	events, err := h.AuditService.FindEvents(r.Context(), repository.AuditFilter{
+		EntityType: req.EntityType,
+		EntityID:   req.EntityID,
+		Actor:      req.Actor,
+		Action:     req.Action,
+		From:       req.From,
+		To:         req.To,
+		AfterID:    req.AfterID,
+		Limit:      req.Limit,
+	})
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, events)
+}
+
+// VerifyAuditChain godoc
+// @Summary 驗證稽核紀錄
+// @Description 重算整條 hash chain，回報第一筆被竄改的事件 (需 admin 權限)
+// @Tags 管理相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.ApiResponse{data=domain.AuditVerification}
+// @Router /admin/audit/verify [get]
+func (h *AuditHandler) VerifyAuditChain(w http.ResponseWriter, r *http.Request) {
+	result, err := h.AuditService.Verify(r.Context())
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, result)
+}
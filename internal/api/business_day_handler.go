@@ -0,0 +1,79 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/yoyo0827/simple-bank-system/internal/response"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+)
+
+type BusinessDayHandler struct {
+	BusinessDayService *service.BusinessDayService
+}
+
+// BusinessDayStatus godoc
+// @Summary 查詢營業日
+// @Description 查詢目前寫入交易的營業日與已結帳的最後一個營業日 (需 admin 權限)
+// @Tags 管理相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.ApiResponse{data=domain.BusinessDayStatus}
+// @Router /admin/business-days [get]
+func (h *BusinessDayHandler) BusinessDayStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.BusinessDayService.Status(r.Context())
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, status)
+}
+
+// CloseBusinessDate godoc
+// @Summary 日終結帳
+// @Description 結帳已結束的營業日 (含之前尚未結帳的營業日)，寫入每個帳號的日終快照；結帳後不可再寫入或修改該營業日的交易 (需 admin 權限)
+// @Tags 管理相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Param date path string true "Business date (YYYY-MM-DD)"
+// @Success 200 {object} response.ApiResponse{data=domain.BusinessDayClose}
+// @Failure 400 {object} response.ApiResponse
+// @Failure 409 {object} response.ApiResponse "Business date already closed or not ended"
+// @Router /admin/business-days/{date}/close [post]
+func (h *BusinessDayHandler) CloseBusinessDate(w http.ResponseWriter, r *http.Request) {
+	result, err := h.BusinessDayService.CloseBusinessDate(r.Context(), r.PathValue("date"))
+	if err != nil {
+		writeBusinessDayError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, result)
+}
+
+// FindEODBalances godoc
+// @Summary 查詢日終快照
+// @Description 查詢已結帳營業日每個帳號的期初餘額、借貸方合計與期末餘額 (需 admin 權限)
+// @Tags 管理相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Param date path string true "Business date (YYYY-MM-DD)"
+// @Success 200 {object} response.ApiResponse{data=[]domain.EODBalance}
+// @Failure 400 {object} response.ApiResponse
+// @Failure 409 {object} response.ApiResponse "Business date not closed"
+// @Router /admin/business-days/{date}/balances [get]
+func (h *BusinessDayHandler) FindEODBalances(w http.ResponseWriter, r *http.Request) {
+	balances, err := h.BusinessDayService.FindEODBalances(r.Context(), r.PathValue("date"))
+	if err != nil {
+		writeBusinessDayError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, balances)
+}
+
+// 結帳失敗：已結帳或尚未結束回 409，其餘 (日期格式) 回 400
+func writeBusinessDayError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrBusinessDateClosed) || errors.Is(err, service.ErrBusinessDateOpen) {
+		response.WriteError(w, http.StatusConflict, err.Error())
+		return
+	}
+	response.WriteError(w, http.StatusBadRequest, err.Error())
+}
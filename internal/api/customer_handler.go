@@ -0,0 +1,174 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/response"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+)
+
+type CustomerHandler struct {
+	CustomerService *service.CustomerService
+	AccountService  *service.AccountService // 以帳號號碼查詢帳號
+	Validator       *request.Validator
+}
+
+// CreateCustomer godoc
+// @Summary 建立客戶
+// @Description 建立客戶資料，KYC 狀態為 pending，身分證號僅保存雜湊；回傳的客戶 ID 為隨機 UUID (需 admin 權限)
+// @Tags 管理相關
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param customer body request.CreateCustomerRequest true "Customer Info"
+// @Success 201 {object} response.ApiResponse{data=domain.Customer}
+// @Failure 409 {object} response.ApiResponse "National ID already registered"
+// @Failure 422 {object} response.ApiResponse
+// @Router /admin/customers [post]
+func (h *CustomerHandler) CreateCustomer(w http.ResponseWriter, r *http.Request) {
+	var req request.CreateCustomerRequest
+	if err := h.Validator.Decode(r.Body, &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	c, err := h.CustomerService.CreateCustomer(r.Context(), &req)
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusCreated, c)
+}
+
+// FindCustomer godoc
+// @Summary 查詢客戶
+// @Description 查詢客戶資料與持有的帳號 (需 admin 權限)
+// @Tags 管理相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Customer ID (UUID)"
+// @Success 200 {object} response.ApiResponse{data=domain.Customer}
+// @Failure 404 {object} response.ApiResponse
+// @Failure 422 {object} response.ApiResponse
+// @Router /admin/customers/{id} [get]
+func (h *CustomerHandler) FindCustomer(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.Validator.ValidateVar("id", id, "uuid"); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	c, err := h.CustomerService.FindCustomer(r.Context(), id)
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, c)
+}
+
+// SetKYCStatus godoc
+// @Summary 更新 KYC 狀態
+// @Description 更新客戶的身分驗證狀態 (需 admin 權限)
+// @Tags 管理相關
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Customer ID (UUID)"
+// @Param request body request.KYCStatusRequest true "KYC status"
+// @Success 200 {object} response.ApiResponse{data=domain.Customer}
+// @Failure 404 {object} response.ApiResponse
+// @Failure 422 {object} response.ApiResponse
+// @Router /admin/customers/{id}/kyc [post]
+func (h *CustomerHandler) SetKYCStatus(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.Validator.ValidateVar("id", id, "uuid"); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	var req request.KYCStatusRequest
+	if err := h.Validator.Decode(r.Body, &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	c, err := h.CustomerService.SetKYCStatus(r.Context(), id, req.Status, req.Note)
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, c)
+}
+
+// FindAccountHolders godoc
+// @Summary 查詢帳號持有人
+// @Description 查詢帳號的主要與聯名持有人及其 KYC 狀態 (需 admin 權限)
+// @Tags 管理相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
+// @Success 200 {object} response.ApiResponse{data=[]domain.AccountHolder}
+// @Failure 404 {object} response.ApiResponse
+// @Router /admin/accounts/{id}/holders [get]
+func (h *CustomerHandler) FindAccountHolders(w http.ResponseWriter, r *http.Request) {
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
+		return
+	}
+	holders, err := h.CustomerService.FindAccountHolders(r.Context(), acc.ID)
+	if err != nil {
+		writeAccountError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, holders)
+}
+
+// AddAccountHolder godoc
+// @Summary 新增帳號持有人
+// @Description 將客戶加入帳號持有人 (聯名帳號)，帳號尚無持有人時成為主要持有人；啟用 KYC 時客戶需已通過驗證 (需 admin 權限)
+// @Tags 管理相關
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
+// @Param request body request.AddAccountHolderRequest true "Customer"
+// @Success 201 {object} response.ApiResponse{data=domain.AccountHolder}
+// @Failure 403 {object} response.ApiResponse "Customer KYC is not verified"
+// @Failure 404 {object} response.ApiResponse
+// @Failure 409 {object} response.ApiResponse "Already a holder"
+// @Failure 422 {object} response.ApiResponse
+// @Router /admin/accounts/{id}/holders [post]
+func (h *CustomerHandler) AddAccountHolder(w http.ResponseWriter, r *http.Request) {
+	var req request.AddAccountHolderRequest
+	if err := h.Validator.Decode(r.Body, &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
+		return
+	}
+	holder, err := h.CustomerService.AddAccountHolder(r.Context(), acc.ID, req.CustomerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			writeAccountError(w, err)
+			return
+		}
+		writeCustomerError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusCreated, holder)
+}
+
+// 客戶相關失敗：不存在回 404，未通過 KYC 回 403，重複回 409，其餘回 400
+func writeCustomerError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, service.ErrCustomerNotFound):
+		response.WriteError(w, http.StatusNotFound, "customer not found")
+	case errors.Is(err, service.ErrKYCNotVerified):
+		response.WriteError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrCustomerExists), errors.Is(err, service.ErrHolderExists):
+		response.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		response.WriteError(w, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/response"
+	"github.com/yoyo0827/simple-bank-system/internal/version"
+)
+
+// HealthHandler 提供給 orchestrator 探測的健康檢查端點
+type HealthHandler struct {
+	DB            *sql.DB
+	PingTimeout   time.Duration
+	SchemaVersion int64 // 此版本程式需要的 migration 版本
+	draining      atomic.Bool
+}
+
+// SetDraining 標記服務正在關閉，readiness 之後一律回傳失敗
+func (h *HealthHandler) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Liveness godoc
+// @Summary 存活檢查
+// @Description 只要程序仍在運作即回傳 200
+// @Tags 健康檢查
+// @Produce json
+// @Success 200 {object} response.ApiResponse
+// @Router /healthz [get]
+func (h *HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	response.WriteSuccess(w, http.StatusOK, map[string]string{"status": "alive"})
+}
+
+// Readiness godoc
+// @Summary 就緒檢查
+// @Description 檢查資料庫連線、schema migration 是否已套用，以及服務是否正在關閉
+// @Tags 健康檢查
+// @Produce json
+// @Success 200 {object} response.ApiResponse
+// @Failure 503 {object} response.ApiResponse
+// @Router /readyz [get]
+func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]string{}
+	ready := true
+
+	if h.draining.Load() {
+		checks["shutdown"] = "draining"
+		ready = false
+	} else {
+		checks["shutdown"] = "ok"
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.PingTimeout)
+	defer cancel()
+	if err := h.DB.PingContext(ctx); err != nil {
+		checks["database"] = err.Error()
+		checks["schema"] = "unknown"
+		ready = false
+	} else {
+		checks["database"] = "ok"
+		if err := checkSchema(ctx, h.DB, h.SchemaVersion); err != nil {
+			checks["schema"] = err.Error()
+			ready = false
+		} else {
+			checks["schema"] = "ok"
+		}
+	}
+
+	if !ready {
+		response.WriteErrorData(w, http.StatusServiceUnavailable, "not ready", checks)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, checks)
+}
+
+// Version godoc
+// @Summary 版本資訊
+// @Description 取得服務的版本與建置資訊
+// @Tags 健康檢查
+// @Produce json
+// @Success 200 {object} response.ApiResponse
+// @Router /version [get]
+func (h *HealthHandler) Version(w http.ResponseWriter, r *http.Request) {
+	response.WriteSuccess(w, http.StatusOK, version.Get())
+}
+
+// 確認 schema migration 已套用到此版本程式需要的版本
+func checkSchema(ctx context.Context, db *sql.DB, want int64) error {
+	var exists bool
+	if err := db.QueryRowContext(ctx, `SELECT to_regclass('public.schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return errors.New("schema is not initialized, run migrate up")
+	}
+	var current int64
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return err
+	}
+	if current < want {
+		return fmt.Errorf("schema version %d is behind %d, run migrate up", current, want)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// 單元測試 Readiness：DB 正常且 schema 為最新版本時回 200，關閉中回 503
+func TestReadiness(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	defer db.Close()
+	h := &HealthHandler{DB: db, PingTimeout: time.Second, SchemaVersion: 2}
+
+	mock.ExpectPing()
+	expectSchemaVersion(mock, 2)
+	rec := httptest.NewRecorder()
+	h.Readiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	h.SetDraining()
+	mock.ExpectPing()
+	expectSchemaVersion(mock, 2)
+	rec = httptest.NewRecorder()
+	h.Readiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"shutdown":"draining"`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 Readiness：schema 尚未初始化時回 503
+func TestReadiness_MissingSchema(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	defer db.Close()
+	h := &HealthHandler{DB: db, PingTimeout: time.Second, SchemaVersion: 2}
+
+	mock.ExpectPing()
+	mock.ExpectQuery(`SELECT to_regclass`).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	rec := httptest.NewRecorder()
+	h.Readiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "schema is not initialized")
+}
+
+// 單元測試 Readiness：尚有未套用的 migration 時回 503
+func TestReadiness_PendingMigrations(t *testing.T) {
+	db, mock, _ := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	defer db.Close()
+	h := &HealthHandler{DB: db, PingTimeout: time.Second, SchemaVersion: 3}
+
+	mock.ExpectPing()
+	expectSchemaVersion(mock, 2)
+	rec := httptest.NewRecorder()
+	h.Readiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "schema version 2 is behind 3")
+}
+
+func expectSchemaVersion(mock sqlmock.Sqlmock, version int64) {
+	mock.ExpectQuery(`SELECT to_regclass`).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(version\), 0\) FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(version))
+}
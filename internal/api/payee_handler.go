@@ -0,0 +1,140 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/response"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+)
+
+type PayeeHandler struct {
+	PayeeService   *service.PayeeService
+	AccountService *service.AccountService // 以帳號號碼查詢帳號
+	Validator      *request.Validator
+}
+
+// FindPayees godoc
+// @Summary 查詢收款人
+// @Description 查詢帳號的收款人 (約定轉入帳號)、上限與冷靜期
+// @Tags 收款人
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
+// @Success 200 {object} response.ApiResponse{data=[]domain.Payee}
+// @Failure 404 {object} response.ApiResponse
+// @Router /accounts/{id}/payees [get]
+func (h *PayeeHandler) FindPayees(w http.ResponseWriter, r *http.Request) {
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
+		return
+	}
+	payees, err := h.PayeeService.FindPayees(r.Context(), acc.ID)
+	if err != nil {
+		writePayeeError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, payees)
+}
+
+// AddPayee godoc
+// @Summary 新增收款人
+// @Description 以帳號號碼新增收款人，上限為 0 或省略代表不限；新增後的冷靜期內適用較低的上限
+// @Tags 收款人
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
+// @Param payee body request.CreatePayeeRequest true "Payee"
+// @Success 201 {object} response.ApiResponse{data=domain.Payee}
+// @Failure 404 {object} response.ApiResponse "Account or payee account not found"
+// @Failure 409 {object} response.ApiResponse "Payee already exists or too many payees"
+// @Failure 422 {object} response.ApiResponse
+// @Router /accounts/{id}/payees [post]
+func (h *PayeeHandler) AddPayee(w http.ResponseWriter, r *http.Request) {
+	var req request.CreatePayeeRequest
+	if err := h.Validator.Decode(r.Body, &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
+		return
+	}
+	p, err := h.PayeeService.AddPayee(r.Context(), acc.ID, &req)
+	if err != nil {
+		writePayeeError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusCreated, p)
+}
+
+// UpdatePayee godoc
+// @Summary 更新收款人
+// @Description 更新收款人暱稱與上限，提高或取消上限時重新起算冷靜期
+// @Tags 收款人
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
+// @Param payee_id path int true "Payee ID"
+// @Param payee body request.UpdatePayeeRequest true "Payee"
+// @Success 200 {object} response.ApiResponse{data=domain.Payee}
+// @Failure 404 {object} response.ApiResponse
+// @Failure 409 {object} response.ApiResponse "Nickname already used"
+// @Failure 422 {object} response.ApiResponse
+// @Router /accounts/{id}/payees/{payee_id} [put]
+func (h *PayeeHandler) UpdatePayee(w http.ResponseWriter, r *http.Request) {
+	var req request.UpdatePayeeRequest
+	if err := h.Validator.Decode(r.Body, &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
+		return
+	}
+	p, err := h.PayeeService.UpdatePayee(r.Context(), acc.ID, r.PathValue("payee_id"), &req)
+	if err != nil {
+		writePayeeError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, p)
+}
+
+// DeletePayee godoc
+// @Summary 刪除收款人
+// @Description 刪除帳號的收款人
+// @Tags 收款人
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
+// @Param payee_id path int true "Payee ID"
+// @Success 200 {object} response.ApiResponse
+// @Failure 404 {object} response.ApiResponse
+// @Router /accounts/{id}/payees/{payee_id} [delete]
+func (h *PayeeHandler) DeletePayee(w http.ResponseWriter, r *http.Request) {
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
+		return
+	}
+	if err := h.PayeeService.DeletePayee(r.Context(), acc.ID, r.PathValue("payee_id")); err != nil {
+		writePayeeError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, nil)
+}
+
+// 收款人相關失敗：不存在回 404，重複或數量已達上限回 409，其餘回 400
+func writePayeeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, service.ErrPayeeNotFound), errors.Is(err, service.ErrPayeeAccountNotFound):
+		response.WriteError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrPayeeExists), errors.Is(err, service.ErrTooManyPayees):
+		response.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		response.WriteError(w, http.StatusBadRequest, err.Error())
+	}
+}
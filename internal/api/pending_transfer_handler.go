@@ -0,0 +1,182 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/response"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+)
+
+// 未指定 limit 時的預設筆數
+const defaultPendingTransferLimit = 100
+
+type PendingTransferHandler struct {
+	PendingTransferService *service.PendingTransferService
+	Validator              *request.Validator
+}
+
+// FindPendingTransfers godoc
+// @Summary 查詢待處理轉帳
+// @Description 查詢因風控規則或大額覆核暫緩執行的轉帳 (需 admin 權限)，以 after_id 分頁
+// @Tags 管理相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Status (pending, approved, rejected, expired)"
+// @Param account_number query string false "From or to account number"
+// @Param after_id query int false "Return transfers after this ID"
+// @Param limit query int false "Max transfers (default 100, max 500)"
+// @Success 200 {object} response.ApiResponse{data=[]domain.PendingTransfer}
+// @Failure 422 {object} response.ApiResponse
+// @Router /admin/pending-transfers [get]
+func (h *PendingTransferHandler) FindPendingTransfers(w http.ResponseWriter, r *http.Request) {
+	var req request.PendingTransferQueryRequest
+	if err := h.Validator.DecodeQuery(r.URL.Query(), &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultPendingTransferLimit
+	}
+	transfers, err := h.PendingTransferService.FindPendingTransfers(r.Context(), repository.PendingTransferFilter{
+		Status:        req.Status,
+		AccountNumber: req.AccountNumber,
+		AfterID:       req.AfterID,
+		Limit:         req.Limit,
+	})
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, transfers)
+}
+
+// FindPendingTransfer godoc
+// @Summary 查詢單筆待處理轉帳
+// @Description 查詢待處理轉帳與完整的處理紀錄 (建立、核准、拒絕、失效) (需 admin 權限)
+// @Tags 管理相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Pending transfer ID"
+// @Success 200 {object} response.ApiResponse{data=domain.PendingTransfer}
+// @Failure 404 {object} response.ApiResponse
+// @Router /admin/pending-transfers/{id} [get]
+func (h *PendingTransferHandler) FindPendingTransfer(w http.ResponseWriter, r *http.Request) {
+	pt, err := h.PendingTransferService.FindPendingTransfer(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writePendingTransferError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, pt)
+}
+
+// ApprovePendingTransfer godoc
+// @Summary 核准待處理轉帳
+// @Description 核准並立即執行待處理轉帳，不可為轉帳發起者；風控審核中的轉帳需 admin 權限。餘額不足或帳號凍結時維持待處理
+// @Tags 交易相關
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Pending transfer ID"
+// @Param request body request.ApproveTransferRequest true "Approval note"
+// @Success 200 {object} response.ApiResponse{data=domain.PendingTransfer}
+// @Failure 400 {object} response.ApiResponse
+// @Failure 403 {object} response.ApiResponse "Requested by the same user, or fraud review by a non-admin"
+// @Failure 404 {object} response.ApiResponse
+// @Failure 409 {object} response.ApiResponse "Already decided, expired or account is frozen"
+// @Failure 500 {object} response.ApiResponse "Fee revenue account is misconfigured"
+// @Router /pending-transfers/{id}/approve [post]
+func (h *PendingTransferHandler) ApprovePendingTransfer(w http.ResponseWriter, r *http.Request) {
+	var req request.ApproveTransferRequest
+	if err := h.Validator.Decode(r.Body, &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	pt, err := h.PendingTransferService.Approve(r.Context(), r.PathValue("id"), req.Note)
+	if err != nil {
+		writePendingTransferError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, pt)
+}
+
+// RejectPendingTransfer godoc
+// @Summary 拒絕待處理轉帳
+// @Description 拒絕待處理轉帳並釋放保留款項，需帶拒絕原因；不可為轉帳發起者，風控審核中的轉帳需 admin 權限
+// @Tags 交易相關
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Pending transfer ID"
+// @Param request body request.RejectTransferRequest true "Rejection note"
+// @Success 200 {object} response.ApiResponse{data=domain.PendingTransfer}
+// @Failure 403 {object} response.ApiResponse "Requested by the same user, or fraud review by a non-admin"
+// @Failure 404 {object} response.ApiResponse
+// @Failure 409 {object} response.ApiResponse "Already decided or expired"
+// @Failure 422 {object} response.ApiResponse
+// @Router /pending-transfers/{id}/reject [post]
+func (h *PendingTransferHandler) RejectPendingTransfer(w http.ResponseWriter, r *http.Request) {
+	var req request.RejectTransferRequest
+	if err := h.Validator.Decode(r.Body, &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	pt, err := h.PendingTransferService.Reject(r.Context(), r.PathValue("id"), req.Note)
+	if err != nil {
+		writePendingTransferError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, pt)
+}
+
+// FindFraudDecisions godoc
+// @Summary 查詢風控決策
+// @Description 查詢每筆轉帳的風控決策與觸發的規則 (需 admin 權限)，以 after_id 分頁
+// @Tags 管理相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Param account_number query string false "From account number"
+// @Param decision query string false "Decision (allow, review, block)"
+// @Param after_id query int false "Return decisions after this ID"
+// @Param limit query int false "Max decisions (default 100, max 500)"
+// @Success 200 {object} response.ApiResponse{data=[]domain.FraudDecision}
+// @Failure 422 {object} response.ApiResponse
+// @Router /admin/fraud/decisions [get]
+func (h *PendingTransferHandler) FindFraudDecisions(w http.ResponseWriter, r *http.Request) {
+	var req request.FraudDecisionQueryRequest
+	if err := h.Validator.DecodeQuery(r.URL.Query(), &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultPendingTransferLimit
+	}
+	decisions, err := h.PendingTransferService.FindFraudDecisions(r.Context(), repository.FraudDecisionFilter{
+		AccountNumber: req.AccountNumber,
+		Decision:      req.Decision,
+		AfterID:       req.AfterID,
+		Limit:         req.Limit,
+	})
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, decisions)
+}
+
+// 處理待處理轉帳失敗：不存在回 404，發起者自行處理或非管理者處理風控審核回 403，已處理或逾期回 409，其餘依轉帳錯誤處理
+func writePendingTransferError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		response.WriteError(w, http.StatusNotFound, "pending transfer not found")
+	case errors.Is(err, service.ErrSelfApproval), errors.Is(err, service.ErrReviewRequiresAdmin):
+		response.WriteError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrPendingTransferDecided), errors.Is(err, service.ErrPendingTransferExpired):
+		response.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		writeTransactionError(w, err)
+	}
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/yoyo0827/simple-bank-system/internal/response"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+)
+
+type ReconciliationHandler struct {
+	ReconciliationService *service.ReconciliationService
+}
+
+// Reconcile godoc
+// @Summary 帳務核對
+// @Description 核對所有帳號餘額是否等於交易紀錄加總 (存款 - 提款)，回報不一致的帳號 (需 admin 權限)
+// @Tags 管理相關
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.ApiResponse{data=domain.Reconciliation}
+// @Router /admin/reconciliation [get]
+func (h *ReconciliationHandler) Reconcile(w http.ResponseWriter, r *http.Request) {
+	result, err := h.ReconciliationService.Reconcile(r.Context())
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, result)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/response"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+)
+
+type ReportHandler struct {
+	ReportService *service.ReportService
+	Validator     *request.Validator
+}
+
+// TrialBalance godoc
+// @Summary 試算表
+// @Description 截至營業日 (含) 各總帳科目 (現金、客戶存款、手續費收入、利息費用) 的借方 / 貸方餘額與合計 (需 admin 權限)
+// @Tags 總帳報表
+// @Produce json
+// @Security ApiKeyAuth
+// @Param date query string false "Business date (YYYY-MM-DD, default: current business date)"
+// @Success 200 {object} response.ApiResponse{data=domain.TrialBalance}
+// @Failure 422 {object} response.ApiResponse
+// @Router /reports/trial-balance [get]
+func (h *ReportHandler) TrialBalance(w http.ResponseWriter, r *http.Request) {
+	var req request.ReportQueryRequest
+	if err := h.Validator.DecodeQuery(r.URL.Query(), &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	tb, err := h.ReportService.TrialBalance(r.Context(), req.Date)
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, tb)
+}
+
+// GLSummary godoc
+// @Summary 每日總帳彙總
+// @Description 營業日當日各總帳科目的借方 / 貸方發生額與分錄筆數，借方合計應等於貸方合計 (需 admin 權限)
+// @Tags 總帳報表
+// @Produce json
+// @Security ApiKeyAuth
+// @Param date query string false "Business date (YYYY-MM-DD, default: current business date)"
+// @Success 200 {object} response.ApiResponse{data=domain.GLSummary}
+// @Failure 422 {object} response.ApiResponse
+// @Router /reports/gl-summary [get]
+func (h *ReportHandler) GLSummary(w http.ResponseWriter, r *http.Request) {
+	var req request.ReportQueryRequest
+	if err := h.Validator.DecodeQuery(r.URL.Query(), &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	sum, err := h.ReportService.GLSummary(r.Context(), req.Date)
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, sum)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/response"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+	"github.com/yoyo0827/simple-bank-system/internal/stream"
+)
+
+// 補齊歷史事件時每批讀取的筆數
+const replayBatch = 500
+
+type StreamHandler struct {
+	AccountService *service.AccountService
+	Broker         *stream.Broker
+	Heartbeat      time.Duration
+	Validator      *request.Validator
+}
+
+// StreamAccountEvents godoc
+// @Summary 帳號事件串流
+// @Description 以 Server-Sent Events 推送帳號的存款、提款、轉帳事件；重新連線時帶入 Last-Event-ID header (或 last_event_id 參數) 可補齊中斷期間的事件
+// @Tags 帳號相關
+// @Produce text/event-stream
+// @Security ApiKeyAuth
+// @Param id path string true "Account number"
+// @Param Last-Event-ID header int false "Resume after this event ID"
+// @Param last_event_id query int false "Resume after this event ID (for clients that cannot set headers)"
+// @Success 200 {string} string "text/event-stream"
+// @Failure 400 {object} response.ApiResponse
+// @Failure 404 {object} response.ApiResponse
+// @Failure 422 {object} response.ApiResponse "Invalid account number"
+// @Router /accounts/{id}/events [get]
+func (h *StreamHandler) StreamAccountEvents(w http.ResponseWriter, r *http.Request) {
+	lastID, err := lastEventID(r)
+	if err != nil {
+		response.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	acc, ok := pathAccount(w, r, h.Validator, h.AccountService)
+	if !ok {
+		return
+	}
+	// 事件以帳號號碼索引
+	id := acc.Number
+	// 先訂閱再補齊歷史事件，避免兩者之間的事件遺失；重複的事件以 ID 略過
+	sub, err := h.Broker.Subscribe(id)
+	if err != nil {
+		response.WriteError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	// 串流為長連線，不套用 server 的寫入逾時
+	_ = rc.SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+
+	for {
+		events, err := h.AccountService.FindAccountEvents(r.Context(), id, lastID, replayBatch)
+		if err != nil {
+			return
+		}
+		for _, ev := range events {
+			if err := writeSSE(w, ev); err != nil {
+				return
+			}
+			lastID = ev.ID
+		}
+		if len(events) < replayBatch {
+			break
+		}
+	}
+	if rc.Flush() != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-sub.C:
+			if !ok {
+				// 訂閱被中斷 (處理太慢或服務關閉)，客戶端會以 Last-Event-ID 重新連線
+				return
+			}
+			if ev.ID <= lastID {
+				continue
+			}
+			if writeSSE(w, ev) != nil || rc.Flush() != nil {
+				return
+			}
+			lastID = ev.ID
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil || rc.Flush() != nil {
+				return
+			}
+		}
+	}
+}
+
+// 寫入一則 SSE 訊息，id 為 outbox 事件 ID
+func writeSSE(w io.Writer, ev *domain.Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data)
+	return err
+}
+
+// 取得續傳位置，優先使用瀏覽器自動帶入的 Last-Event-ID header
+func lastEventID(r *http.Request) (int64, error) {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("last_event_id")
+	}
+	if v == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("invalid Last-Event-ID %q", v)
+	}
+	return id, nil
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/accountno"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+	"github.com/yoyo0827/simple-bank-system/internal/stream"
+)
+
+// 單元測試 SSE：先補齊 Last-Event-ID 之後的事件，再推送即時事件 (略過重複)
+func TestStreamAccountEvents(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	broker := stream.NewBroker(8)
+	h := &StreamHandler{
+		AccountService: &service.AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, OutboxRepository: &repository.OutboxRepository{}},
+		Validator:      request.NewValidator(accountno.DefaultConfig()),
+		Broker:         broker,
+		Heartbeat:      time.Minute,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /accounts/{id}/events", h.StreamAccountEvents)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).WithArgs("100000000016").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).
+			AddRow("1", "Alice", "100", "standard", "active", "", "0", "100000000016"))
+	mock.ExpectQuery(`FROM outbox_events`).WithArgs("100000000016", int64(4), replayBatch).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_type", "aggregate_id", "request_id", "payload", "occurred_at"}).
+			AddRow(5, domain.EventDeposited, "100000000016", "", []byte(`{"account_number":"100000000016"}`), time.Now()))
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/accounts/100000000016/events", nil)
+	req.Header.Set("Last-Event-ID", "4")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	lines := bufio.NewScanner(resp.Body)
+	readID := func() string {
+		for lines.Scan() {
+			if id, ok := strings.CutPrefix(lines.Text(), "id: "); ok {
+				return id
+			}
+		}
+		return ""
+	}
+	assert.Equal(t, "5", readID())
+
+	// 補齊過的事件不重複推送；轉帳轉入的帳號也會收到
+	broker.Publish(&domain.Event{ID: 5, Type: domain.EventDeposited, AggregateID: "100000000016"})
+	broker.Publish(&domain.Event{ID: 6, Type: domain.EventTransferCompleted, AggregateID: "100000000024",
+		Data: []byte(`{"from_account_number":"100000000024","to_account_number":"100000000016"}`)})
+	assert.Equal(t, "6", readID())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 帳號號碼檢查碼錯誤時不查詢資料庫
+func TestStreamAccountEvents_InvalidAccountNumber(t *testing.T) {
+	h := &StreamHandler{Broker: stream.NewBroker(1), Heartbeat: time.Minute, Validator: request.NewValidator(accountno.DefaultConfig())}
+	req := httptest.NewRequest(http.MethodGet, "/accounts/100000000017/events", nil)
+	req.SetPathValue("id", "100000000017")
+	rec := httptest.NewRecorder()
+	h.StreamAccountEvents(rec, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+// 單元測試 Last-Event-ID 格式錯誤
+func TestStreamAccountEvents_InvalidLastEventID(t *testing.T) {
+	h := &StreamHandler{Broker: stream.NewBroker(1), Heartbeat: time.Minute, Validator: request.NewValidator(accountno.DefaultConfig())}
+	req := httptest.NewRequest(http.MethodGet, "/accounts/1/events?last_event_id=abc", nil)
+	req.SetPathValue("id", "1")
+	rec := httptest.NewRecorder()
+	h.StreamAccountEvents(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
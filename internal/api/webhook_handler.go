@@ -0,0 +1,188 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/response"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+)
+
+// 未指定 limit 時的預設筆數
+const defaultDeliveryLimit = 100
+
+type WebhookHandler struct {
+	WebhookService *service.WebhookService
+	Validator      *request.Validator
+}
+
+// CreateWebhook godoc
+// @Summary 建立 webhook 訂閱
+// @Description 訂閱帳務事件，未指定 secret 時自動產生 (僅在回應中出現一次)；event_types 為空代表所有事件。url 解析到內部網路或 link-local 位址時不會投遞 (需 admin 權限)
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param webhook body request.WebhookSubscriptionRequest true "Subscription"
+// @Success 201 {object} response.ApiResponse{data=domain.WebhookSubscription}
+// @Failure 422 {object} response.ApiResponse
+// @Router /admin/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req request.WebhookSubscriptionRequest
+	if err := h.Validator.Decode(r.Body, &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	sub, err := h.WebhookService.CreateSubscription(r.Context(), &req)
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.WriteSuccess(w, http.StatusCreated, sub)
+}
+
+// ListWebhooks godoc
+// @Summary 查詢 webhook 訂閱
+// @Description 列出所有 webhook 訂閱 (需 admin 權限)
+// @Tags Webhook
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} response.ApiResponse{data=[]domain.WebhookSubscription}
+// @Router /admin/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.WebhookService.ListSubscriptions(r.Context())
+	if err != nil {
+		response.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, subs)
+}
+
+// FindWebhook godoc
+// @Summary 查詢單一 webhook 訂閱
+// @Tags Webhook
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} response.ApiResponse{data=domain.WebhookSubscription}
+// @Failure 404 {object} response.ApiResponse
+// @Router /admin/webhooks/{id} [get]
+func (h *WebhookHandler) FindWebhook(w http.ResponseWriter, r *http.Request) {
+	sub, err := h.WebhookService.FindSubscription(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, sub)
+}
+
+// UpdateWebhook godoc
+// @Summary 更新 webhook 訂閱
+// @Description 以新內容取代訂閱設定，指定 secret 時更換簽章 secret (需 admin 權限)
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Subscription ID"
+// @Param webhook body request.WebhookSubscriptionRequest true "Subscription"
+// @Success 200 {object} response.ApiResponse{data=domain.WebhookSubscription}
+// @Failure 404 {object} response.ApiResponse
+// @Failure 422 {object} response.ApiResponse
+// @Router /admin/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req request.WebhookSubscriptionRequest
+	if err := h.Validator.Decode(r.Body, &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	sub, err := h.WebhookService.UpdateSubscription(r.Context(), r.PathValue("id"), &req)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, sub)
+}
+
+// DeleteWebhook godoc
+// @Summary 刪除 webhook 訂閱
+// @Description 刪除訂閱與其投遞紀錄 (需 admin 權限)
+// @Tags Webhook
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} response.ApiResponse
+// @Failure 404 {object} response.ApiResponse
+// @Router /admin/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := h.WebhookService.DeleteSubscription(r.Context(), r.PathValue("id")); err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, nil)
+}
+
+// FindWebhookDeliveries godoc
+// @Summary 查詢 webhook 投遞紀錄
+// @Description 查詢訂閱的投遞狀態、嘗試次數與最後一次錯誤，以 after_id 分頁 (需 admin 權限)
+// @Tags Webhook
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Subscription ID"
+// @Param status query string false "pending / delivered / dead"
+// @Param after_id query int false "Return deliveries after this ID"
+// @Param limit query int false "Max deliveries (default 100, max 500)"
+// @Success 200 {object} response.ApiResponse{data=[]domain.WebhookDelivery}
+// @Failure 404 {object} response.ApiResponse
+// @Failure 422 {object} response.ApiResponse
+// @Router /admin/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) FindWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	var req request.WebhookDeliveryQueryRequest
+	if err := h.Validator.DecodeQuery(r.URL.Query(), &req); err != nil {
+		writeRequestError(w, err)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultDeliveryLimit
+	}
+	deliveries, err := h.WebhookService.FindDeliveries(r.Context(), repository.DeliveryFilter{
+		SubscriptionID: r.PathValue("id"),
+		Status:         req.Status,
+		AfterID:        req.AfterID,
+		Limit:          req.Limit,
+	})
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusOK, deliveries)
+}
+
+// RedeliverWebhook godoc
+// @Summary 重送 webhook
+// @Description 將投遞紀錄重新排入佇列並重置嘗試次數，可用於 dead letter (需 admin 權限)
+// @Tags Webhook
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Delivery ID"
+// @Success 202 {object} response.ApiResponse{data=domain.WebhookDelivery}
+// @Failure 404 {object} response.ApiResponse
+// @Router /admin/webhooks/deliveries/{id}/redeliver [post]
+func (h *WebhookHandler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.WebhookService.Redeliver(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+	response.WriteSuccess(w, http.StatusAccepted, delivery)
+}
+
+func writeWebhookError(w http.ResponseWriter, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		response.WriteError(w, http.StatusNotFound, "webhook not found")
+		return
+	}
+	response.WriteError(w, http.StatusInternalServerError, err.Error())
+}
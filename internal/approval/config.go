@@ -0,0 +1,48 @@
+package approval
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Config 大額轉帳覆核 (maker-checker) 設定
+// 保留款項與逾期設定適用於所有待處理轉帳 (含風控審核)，門檻僅能由設定檔指定 (approval.threshold)
+type Config struct {
+	Enabled       bool            `yaml:"enabled" toml:"enabled" env:"APPROVAL_ENABLED"`
+	Threshold     decimal.Decimal `yaml:"threshold" toml:"threshold"`                             // 超過此金額的轉帳需由另一位使用者核准
+	HoldFunds     bool            `yaml:"hold_funds" toml:"hold_funds" env:"APPROVAL_HOLD_FUNDS"` // 等待處理期間保留轉出金額 (含手續費)
+	Expiry        time.Duration   `yaml:"expiry" toml:"expiry" env:"APPROVAL_EXPIRY"`             // 逾期未處理即失效，0 代表不失效
+	SweepInterval time.Duration   `yaml:"sweep_interval" toml:"sweep_interval" env:"APPROVAL_SWEEP_INTERVAL"`
+}
+
+// DefaultConfig 預設不啟用覆核，待處理轉帳保留款項並於 24 小時後失效
+func DefaultConfig() Config {
+	return Config{
+		Threshold:     decimal.NewFromInt(100000),
+		HoldFunds:     true,
+		Expiry:        24 * time.Hour,
+		SweepInterval: time.Minute,
+	}
+}
+
+// Validate 檢查門檻與期間
+func (c Config) Validate() error {
+	var errs []error
+	if c.Enabled && !c.Threshold.IsPositive() {
+		errs = append(errs, errors.New("approval.threshold must be positive when approval is enabled"))
+	}
+	if c.Expiry < 0 {
+		errs = append(errs, errors.New("approval.expiry cannot be negative"))
+	}
+	if c.Expiry > 0 && c.SweepInterval <= 0 {
+		errs = append(errs, errors.New("approval.sweep_interval must be positive when expiry is set"))
+	}
+	return errors.Join(errs...)
+}
+
+// Required 轉帳金額是否需要覆核
+func (c Config) Required(amount decimal.Decimal) bool {
+	return c.Enabled && amount.GreaterThan(c.Threshold)
+}
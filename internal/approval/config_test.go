@@ -0,0 +1,29 @@
+package approval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// 單元測試 只有超過門檻的轉帳需要覆核
+func TestRequired(t *testing.T) {
+	cfg := Config{Enabled: true, Threshold: decimal.NewFromInt(1000)}
+	assert.False(t, cfg.Required(decimal.NewFromInt(1000)))
+	assert.True(t, cfg.Required(decimal.RequireFromString("1000.01")))
+
+	cfg.Enabled = false
+	assert.False(t, cfg.Required(decimal.NewFromInt(5000)))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig().Validate())
+
+	cfg := Config{Enabled: true, Expiry: time.Hour}
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, "approval.threshold")
+	assert.ErrorContains(t, err, "approval.sweep_interval")
+	assert.ErrorContains(t, Config{Expiry: -time.Second}.Validate(), "approval.expiry")
+}
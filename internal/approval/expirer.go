@@ -0,0 +1,31 @@
+package approval
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/worker"
+)
+
+// Expirable 將逾期未處理的待處理轉帳標記為失效並釋放保留款項，回傳處理的筆數
+type Expirable interface {
+	ExpireDue(ctx context.Context) (int, error)
+}
+
+// Expirer 定期處理逾期的待處理轉帳
+type Expirer struct {
+	Service  Expirable
+	Interval time.Duration
+}
+
+// Run 持續處理直到 ctx 結束
+func (e *Expirer) Run(ctx context.Context) {
+	worker.RunEvery(ctx, "pending transfer expirer", e.Interval, func(ctx context.Context) error {
+		n, err := e.Service.ExpireDue(ctx)
+		if n > 0 {
+			slog.Info("expired pending transfers", "count", n)
+		}
+		return err
+	})
+}
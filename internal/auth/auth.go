@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/yoyo0827/simple-bank-system/internal/response"
+)
+
+// 角色
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Anonymous 未設定 API key 時的預設身分
+var Anonymous = Principal{ID: "anonymous", Role: RoleUser}
+
+// APIKeyHeader 用於傳遞 API key 的 header
+const APIKeyHeader = "X-API-Key"
+
+// Config 認證設定，APIKeys 格式為 "key:user:role"，例如 "s3cr3t:alice:admin"
+type Config struct {
+	APIKeys []string `yaml:"api_keys" toml:"api_keys" env:"AUTH_API_KEYS"`
+}
+
+// Validate 檢查 API key 設定格式
+func (c Config) Validate() error {
+	_, err := NewAuthenticator(c)
+	return err
+}
+
+// Principal 發出請求的使用者
+type Principal struct {
+	ID   string `json:"id"`
+	Role string `json:"role"`
+}
+
+// IsAdmin 是否為管理者
+func (p Principal) IsAdmin() bool {
+	return p.Role == RoleAdmin
+}
+
+// Authenticator 以 API key 辨識使用者
+// 只保存 key 的 SHA-256，避免明文 key 留在記憶體中的查詢表
+type Authenticator struct {
+	keys map[string]Principal
+}
+
+// NewAuthenticator 解析設定中的 API key
+func NewAuthenticator(cfg Config) (*Authenticator, error) {
+	a := &Authenticator{keys: make(map[string]Principal, len(cfg.APIKeys))}
+	for i, entry := range cfg.APIKeys {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("auth.api_keys[%d]: expected format key:user:role", i)
+		}
+		if parts[2] != RoleUser && parts[2] != RoleAdmin {
+			return nil, fmt.Errorf("auth.api_keys[%d]: unknown role %q", i, parts[2])
+		}
+		a.keys[hashKey(parts[0])] = Principal{ID: parts[1], Role: parts[2]}
+	}
+	return a, nil
+}
+
+// Enabled 是否有設定任何 API key
+func (a *Authenticator) Enabled() bool {
+	return len(a.keys) > 0
+}
+
+// Authenticate 依 API key 找出使用者
+func (a *Authenticator) Authenticate(key string) (Principal, bool) {
+	p, ok := a.keys[hashKey(key)]
+	return p, ok
+}
+
+// Middleware 辨識 API key 與來源 IP 並存入 context
+// 未設定任何 API key 時一律視為 Anonymous；有設定但 key 錯誤時回 401
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := WithClientIP(r.Context(), clientIP(r))
+		if !a.Enabled() {
+			ctx = WithPrincipal(ctx, Anonymous)
+		} else if key := apiKey(r); key != "" {
+			p, ok := a.Authenticate(key)
+			if !ok {
+				response.WriteError(w, http.StatusUnauthorized, "invalid API key")
+				return
+			}
+			ctx = WithPrincipal(ctx, p)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Require 要求已認證且具備指定角色
+func Require(role string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, ok := FromContext(r.Context())
+		if !ok {
+			response.WriteError(w, http.StatusUnauthorized, "missing API key")
+			return
+		}
+		if role == RoleAdmin && !p.IsAdmin() {
+			response.WriteError(w, http.StatusForbidden, "admin role required")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func apiKey(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return token
+	}
+	return ""
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+type principalKey struct{}
+type clientIPKey struct{}
+
+// WithPrincipal 將使用者存入 context
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext 從 context 取出使用者
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Actor 稽核紀錄使用的操作者，非 HTTP 來源 (例如排程、命令列) 時為 "system"
+func Actor(ctx context.Context) string {
+	if p, ok := FromContext(ctx); ok {
+		return p.ID
+	}
+	return "system"
+}
+
+// WithClientIP 將來源 IP 存入 context
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIP 從 context 取出來源 IP
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(a *Authenticator, role string, key string) (int, Principal) {
+	var got Principal
+	h := a.Middleware(Require(role, func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if key != "" {
+		req.Header.Set(APIKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code, got
+}
+
+// 單元測試 API key 認證與角色檢查
+func TestAuthenticator(t *testing.T) {
+	a, err := NewAuthenticator(Config{APIKeys: []string{"k-admin:alice:admin", "k-user:bob:user"}})
+	assert.NoError(t, err)
+
+	code, p := serve(a, RoleAdmin, "k-admin")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, Principal{ID: "alice", Role: RoleAdmin}, p)
+
+	code, _ = serve(a, RoleAdmin, "k-user")
+	assert.Equal(t, http.StatusForbidden, code)
+
+	code, p = serve(a, RoleUser, "k-admin")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "alice", p.ID)
+
+	code, _ = serve(a, RoleUser, "wrong")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, _ = serve(a, RoleUser, "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+// 單元測試 未設定 API key 時視為匿名使用者，且無法使用管理功能
+func TestAuthenticator_Disabled(t *testing.T) {
+	a, err := NewAuthenticator(Config{})
+	assert.NoError(t, err)
+
+	code, p := serve(a, RoleUser, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, Anonymous, p)
+
+	code, _ = serve(a, RoleAdmin, "")
+	assert.Equal(t, http.StatusForbidden, code)
+}
+
+// 單元測試 API key 設定格式
+func TestConfig_Validate(t *testing.T) {
+	assert.Error(t, Config{APIKeys: []string{"only-key"}}.Validate())
+	assert.Error(t, Config{APIKeys: []string{"k:alice:root"}}.Validate())
+	assert.NoError(t, Config{APIKeys: []string{"k:alice:user"}}.Validate())
+}
@@ -0,0 +1,95 @@
+package businessday
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	_ "time/tzdata" // 容器映像檔不一定有 zoneinfo
+)
+
+// DateLayout 營業日格式
+const DateLayout = "2006-01-02"
+
+// Config 營業日與日終結帳設定
+// 交易記錄在所屬時區的當日營業日，已結帳的營業日不可再寫入或修改交易
+type Config struct {
+	TimeZone   string        `yaml:"time_zone" toml:"time_zone" env:"BUSINESS_DAY_TIME_ZONE"`       // IANA 時區，空值為 UTC
+	AutoClose  bool          `yaml:"auto_close" toml:"auto_close" env:"BUSINESS_DAY_AUTO_CLOSE"`    // 換日後自動結帳前一營業日
+	CloseDelay time.Duration `yaml:"close_delay" toml:"close_delay" env:"BUSINESS_DAY_CLOSE_DELAY"` // 換日後等待跨日的交易完成再結帳
+	Interval   time.Duration `yaml:"interval" toml:"interval" env:"BUSINESS_DAY_INTERVAL"`          // 自動結帳的檢查間隔
+}
+
+// DefaultConfig 預設以 UTC 為營業日時區，不自動結帳
+func DefaultConfig() Config {
+	return Config{
+		TimeZone:   "UTC",
+		CloseDelay: 5 * time.Minute,
+		Interval:   5 * time.Minute,
+	}
+}
+
+// Validate 檢查時區與期間
+func (c Config) Validate() error {
+	var errs []error
+	if _, err := location(c.TimeZone); err != nil {
+		errs = append(errs, fmt.Errorf("business_day.time_zone: %w", err))
+	}
+	if c.CloseDelay < 0 {
+		errs = append(errs, errors.New("business_day.close_delay cannot be negative"))
+	}
+	if c.AutoClose && c.Interval <= 0 {
+		errs = append(errs, errors.New("business_day.interval must be positive when auto_close is enabled"))
+	}
+	return errors.Join(errs...)
+}
+
+// Zone 營業日時區名稱
+func (c Config) Zone() string {
+	if c.TimeZone == "" {
+		return "UTC"
+	}
+	return c.TimeZone
+}
+
+// Today now 在營業日時區的日期
+func (c Config) Today(now time.Time) string {
+	loc, err := location(c.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return now.In(loc).Format(DateLayout)
+}
+
+// ParseDate 驗證營業日格式 (YYYY-MM-DD)
+func ParseDate(s string) (time.Time, error) {
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid business date %q, expected YYYY-MM-DD", s)
+	}
+	return t, nil
+}
+
+// AddDays 營業日加減天數
+func AddDays(date string, days int) string {
+	t, err := ParseDate(date)
+	if err != nil {
+		return date
+	}
+	return t.AddDate(0, 0, days).Format(DateLayout)
+}
+
+// 已載入的時區，避免每筆交易重新讀取 zoneinfo
+var locations sync.Map
+
+func location(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
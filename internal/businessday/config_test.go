@@ -0,0 +1,34 @@
+package businessday
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig().Validate())
+	assert.NoError(t, Config{}.Validate())
+
+	err := Config{TimeZone: "Mars/Olympus", AutoClose: true, CloseDelay: -time.Second}.Validate()
+	assert.ErrorContains(t, err, "business_day.time_zone")
+	assert.ErrorContains(t, err, "business_day.close_delay")
+	assert.ErrorContains(t, err, "business_day.interval")
+}
+
+// 單元測試 營業日依設定的時區換日
+func TestToday(t *testing.T) {
+	now := time.Date(2026, 3, 31, 17, 30, 0, 0, time.UTC)
+	assert.Equal(t, "2026-03-31", Config{}.Today(now))
+	assert.Equal(t, "2026-04-01", Config{TimeZone: "Asia/Taipei"}.Today(now))
+	assert.Equal(t, "2026-03-31", Config{TimeZone: "America/New_York"}.Today(now))
+}
+
+func TestAddDays(t *testing.T) {
+	assert.Equal(t, "2026-02-28", AddDays("2026-03-01", -1))
+	assert.Equal(t, "2027-01-01", AddDays("2026-12-31", 1))
+
+	_, err := ParseDate("2026-02-30")
+	assert.ErrorContains(t, err, "expected YYYY-MM-DD")
+}
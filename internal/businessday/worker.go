@@ -0,0 +1,36 @@
+package businessday
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/worker"
+)
+
+// Closer 結帳 through (含) 之前所有尚未結帳的營業日，回傳結帳的天數 (已結帳時為 0)
+type Closer interface {
+	CloseOpenDates(ctx context.Context, through string) (int, error)
+}
+
+// Worker 換日後自動結帳前一營業日，寫入日終快照
+type Worker struct {
+	Service Closer
+	Config  Config
+}
+
+// Run 持續處理直到 ctx 結束
+func (w *Worker) Run(ctx context.Context) {
+	worker.RunEvery(ctx, "end-of-day worker", w.Config.Interval, func(ctx context.Context) error {
+		through := AddDays(w.Config.Today(time.Now().Add(-w.Config.CloseDelay)), -1)
+		n, err := w.Service.CloseOpenDates(ctx, through)
+		if err != nil {
+			return fmt.Errorf("close business dates through %s: %w", through, err)
+		}
+		if n > 0 {
+			slog.Info("business dates closed", "through", through, "count", n)
+		}
+		return nil
+	}, "time_zone", w.Config.TimeZone)
+}
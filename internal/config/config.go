@@ -0,0 +1,187 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/yoyo0827/simple-bank-system/internal/accountno"
+	"github.com/yoyo0827/simple-bank-system/internal/approval"
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/businessday"
+	"github.com/yoyo0827/simple-bank-system/internal/fee"
+	"github.com/yoyo0827/simple-bank-system/internal/fraud"
+	"github.com/yoyo0827/simple-bank-system/internal/gl"
+	"github.com/yoyo0827/simple-bank-system/internal/kyc"
+	"github.com/yoyo0827/simple-bank-system/internal/logging"
+	"github.com/yoyo0827/simple-bank-system/internal/outbox"
+	"github.com/yoyo0827/simple-bank-system/internal/payee"
+	"github.com/yoyo0827/simple-bank-system/internal/ratelimit"
+	"github.com/yoyo0827/simple-bank-system/internal/snapshot"
+	"github.com/yoyo0827/simple-bank-system/internal/stream"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+	"github.com/yoyo0827/simple-bank-system/internal/webhook"
+	"gopkg.in/yaml.v3"
+)
+
+// Config 服務的完整設定
+// 優先順序：預設值 < 設定檔 (YAML / TOML) < 環境變數 < 命令列參數
+type Config struct {
+	Currency  string           `yaml:"currency" toml:"currency" env:"BANK_CURRENCY"`
+	Database  DatabaseConfig   `yaml:"database" toml:"database"`
+	Server    ServerConfig     `yaml:"server" toml:"server"`
+	GRPC      GRPCConfig       `yaml:"grpc" toml:"grpc"`
+	Features  FeatureConfig    `yaml:"features" toml:"features"`
+	Log       logging.Config   `yaml:"log" toml:"log"`
+	Tracing   tracing.Config   `yaml:"tracing" toml:"tracing"`
+	Auth      auth.Config      `yaml:"auth" toml:"auth"`
+	Outbox    outbox.Config    `yaml:"outbox" toml:"outbox"`
+	Webhooks  webhook.Config   `yaml:"webhooks" toml:"webhooks"`
+	Stream    stream.Config    `yaml:"stream" toml:"stream"`
+	Fees      fee.Config       `yaml:"fees" toml:"fees"`
+	Fraud     fraud.Config     `yaml:"fraud" toml:"fraud"`
+	RateLimit ratelimit.Config `yaml:"ratelimit" toml:"ratelimit"`
+	Approval  approval.Config  `yaml:"approval" toml:"approval"`
+	KYC       kyc.Config       `yaml:"kyc" toml:"kyc"`
+	Payees    payee.Config     `yaml:"payees" toml:"payees"`
+	// 定期餘額快照 (歷史餘額查詢)
+	BalanceSnapshots snapshot.Config `yaml:"balance_snapshots" toml:"balance_snapshots"`
+	// 營業日與日終結帳
+	BusinessDay businessday.Config `yaml:"business_day" toml:"business_day"`
+	// 總帳科目表
+	GL gl.Config `yaml:"gl" toml:"gl"`
+	// 對外帳號號碼格式
+	AccountNumber accountno.Config `yaml:"account_number" toml:"account_number"`
+}
+
+// FeatureConfig 功能開關
+type FeatureConfig struct {
+	Swagger bool `yaml:"swagger" toml:"swagger" env:"FEATURE_SWAGGER"`
+	Metrics bool `yaml:"metrics" toml:"metrics" env:"FEATURE_METRICS"`
+}
+
+// Default 回傳預設設定
+func Default() *Config {
+	return &Config{
+		Currency: "TWD",
+		Database: defaultDatabaseConfig(),
+		Server:   defaultServerConfig(),
+		GRPC:     defaultGRPCConfig(),
+		Features: FeatureConfig{
+			Swagger: true,
+			Metrics: true,
+		},
+		Log: logging.Config{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: tracing.Config{
+			ServiceName: "simple-bank-system",
+			SampleRatio: 1,
+		},
+		Outbox:    outbox.DefaultConfig(),
+		Webhooks:  webhook.DefaultConfig(),
+		Stream:    stream.DefaultConfig(),
+		Fees:      fee.DefaultConfig(),
+		Fraud:     fraud.DefaultConfig(),
+		RateLimit: ratelimit.DefaultConfig(),
+		Approval:  approval.DefaultConfig(),
+		KYC:       kyc.DefaultConfig(),
+		Payees:    payee.DefaultConfig(),
+
+		BalanceSnapshots: snapshot.DefaultConfig(),
+		BusinessDay:      businessday.DefaultConfig(),
+		GL:               gl.DefaultConfig(),
+
+		AccountNumber: accountno.DefaultConfig(),
+	}
+}
+
+// Load 依序套用設定檔、環境變數與命令列參數，並驗證設定
+// 設定檔路徑可由 -config 參數或 CONFIG_FILE 環境變數指定
+func Load(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	load := Bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return load()
+}
+
+// Bind 在 fs 上註冊設定參數 (-config 與各設定欄位)，讓子命令可加入自己的參數
+// 回傳的函式需在 fs.Parse 之後呼叫，依序套用設定檔、環境變數與命令列參數並驗證
+func Bind(fs *flag.FlagSet) func() (*Config, error) {
+	cfg := Default()
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to YAML or TOML config file")
+	fields := bindFields(cfg)
+	flagValues := make(map[string]*string, len(fields))
+	for _, f := range fields {
+		flagValues[f.flag] = fs.String(f.flag, "", "overrides "+f.env)
+	}
+
+	return func() (*Config, error) {
+		if *configFile != "" {
+			if err := loadFile(cfg, *configFile); err != nil {
+				return nil, err
+			}
+		}
+
+		var errs []error
+		for _, f := range fields {
+			if v, ok := os.LookupEnv(f.env); ok && v != "" {
+				if err := f.set(v); err != nil {
+					errs = append(errs, fmt.Errorf("env %s: %w", f.env, err))
+				}
+			}
+		}
+		fs.Visit(func(fl *flag.Flag) {
+			for _, f := range fields {
+				if f.flag == fl.Name {
+					if err := f.set(*flagValues[fl.Name]); err != nil {
+						errs = append(errs, fmt.Errorf("flag -%s: %w", fl.Name, err))
+					}
+				}
+			}
+		})
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+	}
+}
+
+// Validate 檢查設定值是否合理，一次回傳所有錯誤
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Currency == "" {
+		errs = append(errs, errors.New("currency is required"))
+	}
+	return errors.Join(append(errs, c.Database.validate(), c.Server.validate(), c.GRPC.validate(c.Server), c.Log.Validate(), c.Tracing.Validate(), c.Auth.Validate(), c.Outbox.Validate(), c.Webhooks.Validate(), c.Stream.Validate(), c.Fees.Validate(), c.Fraud.Validate(), c.RateLimit.Validate(), c.Approval.Validate(), c.KYC.Validate(), c.Payees.Validate(), c.BalanceSnapshots.Validate(), c.BusinessDay.Validate(), c.GL.Validate(), c.AccountNumber.Validate())...)
+}
+
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("unsupported config file format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
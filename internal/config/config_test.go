@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/ratelimit"
+)
+
+// 單元測試 設定檔 < 環境變數 < 命令列參數 的優先順序
+func TestLoad_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+database:
+  host: file-host
+  name: bankdb
+  max_open_conns: 50
+  conn_max_lifetime: 1h
+server:
+  port: "9000"
+`), 0o600)
+	assert.NoError(t, err)
+
+	t.Setenv("DB_HOST", "env-host")
+	t.Setenv("SERVER_PORT", "9100")
+
+	cfg, err := Load([]string{"-config", path, "-server-port", "9200", "-feature-swagger=false"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "env-host", cfg.Database.Host)           // env 覆寫設定檔
+	assert.Equal(t, "bankdb", cfg.Database.Name)             // 設定檔
+	assert.Equal(t, 50, cfg.Database.MaxOpenConns)           // 設定檔
+	assert.Equal(t, time.Hour, cfg.Database.ConnMaxLifetime) // 設定檔
+	assert.Equal(t, 10, cfg.Database.ConnectRetries)         // 預設值
+	assert.Equal(t, ":9200", cfg.Server.Addr())              // 參數覆寫 env
+	assert.False(t, cfg.Features.Swagger)                    // 參數
+	assert.Equal(t, "disable", cfg.Database.SSLMode)         // 預設值
+	assert.Equal(t, 15*time.Second, cfg.Server.WriteTimeout) // 預設值
+}
+
+// 單元測試 TOML 設定檔
+func TestLoad_TOML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	err := os.WriteFile(path, []byte(`
+[database]
+name = "bankdb"
+sslmode = "require"
+retry_max_backoff = "30s"
+`), 0o600)
+	assert.NoError(t, err)
+
+	cfg, err := Load([]string{"-config", path})
+	assert.NoError(t, err)
+	assert.Equal(t, "require", cfg.Database.SSLMode)
+	assert.Equal(t, 30*time.Second, cfg.Database.RetryMaxBackoff)
+}
+
+// 單元測試 由設定檔載入手續費收費表 (含級距)
+func TestLoad_FeeSchedules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+database:
+  name: bankdb
+fees:
+  enabled: true
+  revenue_account: "100000000016"
+  schedules:
+    - name: transfer
+      operation: transfer
+      percent: 0.5
+      min: 10
+      max: "100"
+    - name: premium-withdrawal
+      operation: withdrawal
+      product: premium
+      tiers:
+        - {up_to: 1000, flat: 0}
+        - {flat: 5}
+`), 0o600)
+	assert.NoError(t, err)
+
+	cfg, err := Load([]string{"-config", path})
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Fees.Schedules, 2)
+	assert.Equal(t, "0.5", cfg.Fees.Schedules[0].Percent.String())
+	assert.Equal(t, "100", cfg.Fees.Schedules[0].Max.String())
+	assert.Equal(t, "5", cfg.Fees.Schedules[1].Tiers[1].Flat.String())
+}
+
+// 單元測試 rate limit 規則可由設定檔 (TOML) 覆蓋預設規則
+func TestLoad_RateLimitRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	err := os.WriteFile(path, []byte(`
+[database]
+name = "bankdb"
+
+[ratelimit]
+backend = "postgres"
+
+[[ratelimit.rules]]
+route = "POST /accounts/transfer"
+key = "account"
+requests = 5
+per = "10s"
+burst = 2
+`), 0o600)
+	assert.NoError(t, err)
+
+	cfg, err := Load([]string{"-config", path})
+	assert.NoError(t, err)
+	assert.Equal(t, "postgres", cfg.RateLimit.Backend)
+	assert.Len(t, cfg.RateLimit.Rules, 1)
+	assert.Equal(t, ratelimit.Limit{Burst: 2, Interval: 2 * time.Second}, cfg.RateLimit.Rules[0].Limit())
+}
+
+// 單元測試 設定驗證會一次回傳所有錯誤
+func TestLoad_Invalid(t *testing.T) {
+	t.Setenv("DB_NAME", "bankdb")
+	t.Setenv("DB_SSLMODE", "sometimes")
+	t.Setenv("DB_MAX_OPEN_CONNS", "5")
+	t.Setenv("DB_MAX_IDLE_CONNS", "10")
+	t.Setenv("SERVER_TLS_CERT_FILE", "cert.pem")
+
+	_, err := Load(nil)
+	assert.ErrorContains(t, err, `database.sslmode "sometimes" is invalid`)
+	assert.ErrorContains(t, err, "database.max_idle_conns cannot exceed database.max_open_conns")
+	assert.ErrorContains(t, err, "server.tls_cert_file and server.tls_key_file must be set together")
+
+	t.Setenv("DB_MAX_OPEN_CONNS", "many")
+	_, err = Load(nil)
+	assert.ErrorContains(t, err, "env DB_MAX_OPEN_CONNS")
+}
+
+// 單元測試 DSN 會正確跳脫特殊字元
+func TestDatabaseConfig_DSN(t *testing.T) {
+	c := DatabaseConfig{Host: "db", Port: "5432", User: "admin", Password: "p@ss word", Name: "bankdb", SSLMode: "disable"}
+	assert.Equal(t, "postgres://admin:p%40ss%20word@db:5432/bankdb?sslmode=disable", c.DSN())
+}
@@ -2,43 +2,116 @@ package config
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"net"
+	"net/url"
 	"time"
 
 	_ "github.com/lib/pq"
 )
 
-var DB *sql.DB
-
-// InitDatabase 會在服務啟動時初始化資料庫連線
-func InitDatabase() {
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-
-	connectStr := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname,
-	)
-
-	var err error
-	for i := 0; i < 10; i++ { // 1s一次，最多重試 10 次
-		DB, err = sql.Open("postgres", connectStr)
-		if err == nil {
-			if pingErr := DB.Ping(); pingErr == nil {
-				log.Println(" Database connected")
-				return
-			} else {
-				err = pingErr
-			}
-		}
-		log.Printf(" Waiting for database... (%d/10) %v", i+1, err)
-		time.Sleep(1 * time.Second)
+// DatabaseConfig PostgreSQL 連線與連線池設定
+type DatabaseConfig struct {
+	Host     string `yaml:"host" toml:"host" env:"DB_HOST"`
+	Port     string `yaml:"port" toml:"port" env:"DB_PORT"`
+	User     string `yaml:"user" toml:"user" env:"DB_USER"`
+	Password string `yaml:"password" toml:"password" env:"DB_PASSWORD"`
+	Name     string `yaml:"name" toml:"name" env:"DB_NAME"`
+	SSLMode  string `yaml:"sslmode" toml:"sslmode" env:"DB_SSLMODE"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns" toml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" toml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+
+	ConnectRetries      int           `yaml:"connect_retries" toml:"connect_retries" env:"DB_CONNECT_RETRIES"`
+	RetryInitialBackoff time.Duration `yaml:"retry_initial_backoff" toml:"retry_initial_backoff" env:"DB_RETRY_INITIAL_BACKOFF"`
+	RetryMaxBackoff     time.Duration `yaml:"retry_max_backoff" toml:"retry_max_backoff" env:"DB_RETRY_MAX_BACKOFF"`
+}
+
+var sslModes = map[string]bool{
+	"disable": true, "allow": true, "prefer": true, "require": true, "verify-ca": true, "verify-full": true,
+}
+
+func defaultDatabaseConfig() DatabaseConfig {
+	return DatabaseConfig{
+		Host:                "localhost",
+		Port:                "5432",
+		SSLMode:             "disable",
+		MaxOpenConns:        25,
+		MaxIdleConns:        25,
+		ConnMaxLifetime:     30 * time.Minute,
+		ConnMaxIdleTime:     5 * time.Minute,
+		ConnectRetries:      10,
+		RetryInitialBackoff: 500 * time.Millisecond,
+		RetryMaxBackoff:     10 * time.Second,
 	}
+}
 
-	log.Fatalf("Could not connect to database: %v", err)
+func (c DatabaseConfig) validate() error {
+	var errs []error
+	if c.Host == "" {
+		errs = append(errs, errors.New("database.host is required"))
+	}
+	if c.Name == "" {
+		errs = append(errs, errors.New("database.name is required"))
+	}
+	if !sslModes[c.SSLMode] {
+		errs = append(errs, fmt.Errorf("database.sslmode %q is invalid", c.SSLMode))
+	}
+	if c.MaxOpenConns < 0 || c.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("database connection pool sizes cannot be negative"))
+	}
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		errs = append(errs, errors.New("database.max_idle_conns cannot exceed database.max_open_conns"))
+	}
+	if c.ConnectRetries < 1 {
+		errs = append(errs, errors.New("database.connect_retries must be at least 1"))
+	}
+	if c.RetryInitialBackoff <= 0 || c.RetryMaxBackoff < c.RetryInitialBackoff {
+		errs = append(errs, errors.New("database retry backoff must be positive and max >= initial"))
+	}
+	return errors.Join(errs...)
+}
+
+// DSN 組出 PostgreSQL 連線字串
+func (c DatabaseConfig) DSN() string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.User, c.Password),
+		Host:     net.JoinHostPort(c.Host, c.Port),
+		Path:     "/" + c.Name,
+		RawQuery: url.Values{"sslmode": {c.SSLMode}}.Encode(),
+	}
+	return u.String()
+}
+
+// OpenDatabase 建立資料庫連線並設定連線池，連線失敗時以指數退避重試
+func OpenDatabase(c DatabaseConfig) (*sql.DB, error) {
+	db, err := sql.Open("postgres", c.DSN())
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	db.SetMaxIdleConns(c.MaxIdleConns)
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(c.ConnMaxIdleTime)
+
+	backoff := c.RetryInitialBackoff
+	for i := 1; i <= c.ConnectRetries; i++ {
+		if err = db.Ping(); err == nil {
+			slog.Info("database connected", "host", c.Host, "name", c.Name)
+			return db, nil
+		}
+		if i == c.ConnectRetries {
+			break
+		}
+		slog.Warn("waiting for database", "attempt", i, "max_attempts", c.ConnectRetries, "error", err, "retry_in", backoff.String())
+		time.Sleep(backoff)
+		backoff = min(backoff*2, c.RetryMaxBackoff)
+	}
+	db.Close()
+	return nil, fmt.Errorf("could not connect to database: %w", err)
 }
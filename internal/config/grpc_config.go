@@ -0,0 +1,41 @@
+package config
+
+import (
+	"errors"
+	"net"
+)
+
+// GRPCConfig gRPC server 相關設定，與 HTTP server 使用不同的 port
+// 關閉逾時沿用 server.shutdown_timeout
+type GRPCConfig struct {
+	Enabled    bool   `yaml:"enabled" toml:"enabled" env:"GRPC_ENABLED"`
+	Host       string `yaml:"host" toml:"host" env:"GRPC_HOST"`
+	Port       string `yaml:"port" toml:"port" env:"GRPC_PORT"`
+	Reflection bool   `yaml:"reflection" toml:"reflection" env:"GRPC_REFLECTION"`
+}
+
+func defaultGRPCConfig() GRPCConfig {
+	return GRPCConfig{
+		Enabled:    true,
+		Port:       "9090",
+		Reflection: true,
+	}
+}
+
+func (c GRPCConfig) validate(server ServerConfig) error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Port == "" {
+		return errors.New("grpc.port is required")
+	}
+	if c.Port == server.Port && c.Host == server.Host {
+		return errors.New("grpc.port must differ from server.port")
+	}
+	return nil
+}
+
+// Addr 監聽位址，例如 ":9090"
+func (c GRPCConfig) Addr() string {
+	return net.JoinHostPort(c.Host, c.Port)
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+// ServerConfig HTTP server 相關設定
+type ServerConfig struct {
+	Host              string        `yaml:"host" toml:"host" env:"SERVER_HOST"`
+	Port              string        `yaml:"port" toml:"port" env:"SERVER_PORT"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" toml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" toml:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" toml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
+	DrainDelay        time.Duration `yaml:"drain_delay" toml:"drain_delay" env:"SERVER_DRAIN_DELAY"`
+	ReadinessTimeout  time.Duration `yaml:"readiness_timeout" toml:"readiness_timeout" env:"SERVER_READINESS_TIMEOUT"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes" toml:"max_header_bytes" env:"SERVER_MAX_HEADER_BYTES"`
+	MaxBodyBytes      int64         `yaml:"max_body_bytes" toml:"max_body_bytes" env:"SERVER_MAX_BODY_BYTES"`
+	TLSCertFile       string        `yaml:"tls_cert_file" toml:"tls_cert_file" env:"SERVER_TLS_CERT_FILE"`
+	TLSKeyFile        string        `yaml:"tls_key_file" toml:"tls_key_file" env:"SERVER_TLS_KEY_FILE"`
+}
+
+func defaultServerConfig() ServerConfig {
+	return ServerConfig{
+		Port:              "8080",
+		ReadTimeout:       10 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      15 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		ShutdownTimeout:   30 * time.Second,
+		ReadinessTimeout:  2 * time.Second,
+		MaxHeaderBytes:    1 << 20,
+		MaxBodyBytes:      1 << 20,
+	}
+}
+
+func (c ServerConfig) validate() error {
+	var errs []error
+	if c.Port == "" {
+		errs = append(errs, errors.New("server.port is required"))
+	}
+	if c.ReadTimeout <= 0 || c.WriteTimeout <= 0 || c.IdleTimeout <= 0 || c.ReadHeaderTimeout <= 0 {
+		errs = append(errs, errors.New("server timeouts must be positive"))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server.shutdown_timeout must be positive"))
+	}
+	if c.DrainDelay < 0 {
+		errs = append(errs, errors.New("server.drain_delay cannot be negative"))
+	}
+	if c.ReadinessTimeout <= 0 {
+		errs = append(errs, errors.New("server.readiness_timeout must be positive"))
+	}
+	if c.MaxHeaderBytes <= 0 || c.MaxBodyBytes <= 0 {
+		errs = append(errs, errors.New("server.max_header_bytes and server.max_body_bytes must be positive"))
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, errors.New("server.tls_cert_file and server.tls_key_file must be set together"))
+	}
+	return errors.Join(errs...)
+}
+
+// Addr 監聽位址，例如 ":8080"
+func (c ServerConfig) Addr() string {
+	return net.JoinHostPort(c.Host, c.Port)
+}
@@ -0,0 +1,174 @@
+package csvimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+)
+
+// 匯入檔的欄位名稱
+const (
+	ColumnExternalID  = "external_id"  // 舊系統的帳號識別，原樣回傳於結果中 (選填)
+	ColumnName        = "name"         // 帳號名稱，指定客戶時可留空
+	ColumnBalance     = "balance"      // 期初餘額
+	ColumnProduct     = "product"      // 帳號產品 (選填)
+	ColumnCustomerIDs = "customer_ids" // 以 ; 分隔的客戶 ID，第一位為主要持有人 (選填)
+)
+
+// 外部識別的長度上限
+const maxExternalID = 64
+
+var (
+	requiredColumns = []string{ColumnName, ColumnBalance}
+	knownColumns    = map[string]bool{ColumnExternalID: true, ColumnName: true, ColumnBalance: true, ColumnProduct: true, ColumnCustomerIDs: true}
+	utf8BOM         = []byte{0xEF, 0xBB, 0xBF}
+)
+
+// ErrInvalidHeader 標題列缺少必要欄位或含有未知、重複的欄位
+var ErrInvalidHeader = errors.New("invalid csv header")
+
+// Row 匯入檔的一列，Errors 非空時代表該列驗證失敗
+type Row struct {
+	Line       int // 檔案中的行號 (標題列為第 1 行)
+	ExternalID string
+	Request    *request.CreateAccountRequest
+	Errors     request.ValidationErrors
+}
+
+// Reader 逐列讀取並驗證帳號匯入檔，不會將整個檔案載入記憶體
+type Reader struct {
+	csv        *csv.Reader
+	validator  *request.Validator
+	columns    map[string]int
+	externalID map[string]int // 已出現的外部識別與所在行號
+}
+
+// NewReader 讀取並檢查標題列，標題列不分大小寫並忽略前後空白
+// 每一列以 v 執行與 API 相同的驗證
+func NewReader(r io.Reader, v *request.Validator) (*Reader, error) {
+	cr := csv.NewReader(skipBOM(r))
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidHeader)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidHeader, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(h))
+		if !knownColumns[name] {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidHeader, h)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate column %q", ErrInvalidHeader, name)
+		}
+		columns[name] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidHeader, name)
+		}
+	}
+	return &Reader{csv: cr, validator: v, columns: columns, externalID: map[string]int{}}, nil
+}
+
+// Next 讀取下一列並驗證，檔案結束時回傳 io.EOF
+// CSV 格式錯誤記為該列的錯誤，其他讀取錯誤直接回傳
+func (r *Reader) Next() (*Row, error) {
+	for {
+		record, err := r.csv.Read()
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			return &Row{Line: perr.StartLine, Errors: request.ValidationErrors{{Field: "row", Message: perr.Err.Error()}}}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := r.csv.FieldPos(0)
+		if blank(record) {
+			continue
+		}
+		return r.parse(line, record), nil
+	}
+}
+
+// 將一列轉為開戶請求並執行與 API 相同的驗證
+func (r *Reader) parse(line int, record []string) *Row {
+	row := &Row{Line: line, ExternalID: r.field(record, ColumnExternalID), Request: &request.CreateAccountRequest{
+		Name:    r.field(record, ColumnName),
+		Product: r.field(record, ColumnProduct),
+	}}
+	if len(record) != len(r.columns) {
+		row.Errors = append(row.Errors, request.FieldError{Field: "row", Message: fmt.Sprintf("expected %d columns, got %d", len(r.columns), len(record))})
+	}
+	if row.ExternalID != "" {
+		if len(row.ExternalID) > maxExternalID {
+			row.Errors = append(row.Errors, request.FieldError{Field: ColumnExternalID, Message: fmt.Sprintf("must be at most %d characters", maxExternalID)})
+		} else if prev, ok := r.externalID[row.ExternalID]; ok {
+			row.Errors = append(row.Errors, request.FieldError{Field: ColumnExternalID, Message: fmt.Sprintf("duplicate of line %d", prev)})
+		} else {
+			r.externalID[row.ExternalID] = line
+		}
+	}
+	if ids := r.field(record, ColumnCustomerIDs); ids != "" {
+		for _, id := range strings.Split(ids, ";") {
+			row.Request.CustomerIDs = append(row.Request.CustomerIDs, strings.TrimSpace(id))
+		}
+	}
+	balance, perr := decimal.NewFromString(r.field(record, ColumnBalance))
+	if perr != nil {
+		row.Errors = append(row.Errors, request.FieldError{Field: ColumnBalance, Message: "must be a decimal number"})
+	}
+	row.Request.Balance = balance
+	var verrs request.ValidationErrors
+	if err := r.validator.Validate(row.Request); errors.As(err, &verrs) {
+		for _, fe := range verrs {
+			// 餘額無法解析時不再重複回報
+			if fe.Field == ColumnBalance && perr != nil {
+				continue
+			}
+			row.Errors = append(row.Errors, fe)
+		}
+	}
+	return row
+}
+
+// 取得欄位值，沒有該欄或該列欄位不足時為空字串
+func (r *Reader) field(record []string, name string) string {
+	i, ok := r.columns[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// 空白列 (例如檔案結尾多出的換行) 直接略過
+func blank(record []string) bool {
+	for _, f := range record {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// 略過試算表軟體輸出的 UTF-8 BOM
+func skipBOM(r io.Reader) io.Reader {
+	buf := make([]byte, len(utf8BOM))
+	n, err := io.ReadFull(r, buf)
+	if err == nil && bytes.Equal(buf, utf8BOM) {
+		return r
+	}
+	return io.MultiReader(bytes.NewReader(buf[:n]), r)
+}
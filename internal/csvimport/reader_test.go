@@ -0,0 +1,73 @@
+package csvimport
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/accountno"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+)
+
+func readAll(t *testing.T, r *Reader) []*Row {
+	t.Helper()
+	var rows []*Row
+	for {
+		row, err := r.Next()
+		if err == io.EOF {
+			return rows
+		}
+		if !assert.NoError(t, err) {
+			return rows
+		}
+		rows = append(rows, row)
+	}
+}
+
+// 單元測試 逐列解析並驗證，錯誤附上行號
+func TestReader(t *testing.T) {
+	data := "\xef\xbb\xbfExternal_ID, Name ,BALANCE,customer_ids\n" +
+		"L-1,Alice,100.50,\n" +
+		"L-2,,0,0b6f4c8e-5c1a-4e0e-9d7a-6a1f3e2b7c05; 0b6f4c8e-5c1a-4e0e-9d7a-6a1f3e2b7c06\n" +
+		"\n" +
+		",,,\n" +
+		"L-3,Bob,abc,\n" +
+		"L-1,Carol,-5,\n" +
+		"L-4,\"Dave\n"
+	r, err := NewReader(strings.NewReader(data), request.NewValidator(accountno.DefaultConfig()))
+	assert.NoError(t, err)
+
+	rows := readAll(t, r)
+	assert.Len(t, rows, 5)
+
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, "L-1", rows[0].ExternalID)
+	assert.Equal(t, "Alice", rows[0].Request.Name)
+	assert.Equal(t, "100.5", rows[0].Request.Balance.String())
+	assert.Empty(t, rows[0].Errors)
+
+	// 指定客戶時名稱可留空
+	assert.Equal(t, []string{"0b6f4c8e-5c1a-4e0e-9d7a-6a1f3e2b7c05", "0b6f4c8e-5c1a-4e0e-9d7a-6a1f3e2b7c06"}, rows[1].Request.CustomerIDs)
+	assert.Empty(t, rows[1].Errors)
+
+	// 空白列略過，行號仍對應檔案
+	assert.Equal(t, 6, rows[2].Line)
+	assert.Equal(t, request.ValidationErrors{{Field: "balance", Message: "must be a decimal number"}}, rows[2].Errors)
+
+	assert.Equal(t, request.ValidationErrors{
+		{Field: "external_id", Message: "duplicate of line 2"},
+		{Field: "balance", Message: "cannot be negative"},
+	}, rows[3].Errors)
+
+	assert.Equal(t, 8, rows[4].Line)
+	assert.Equal(t, "row", rows[4].Errors[0].Field)
+}
+
+// 單元測試 標題列缺少必要欄位或含有未知欄位
+func TestNewReader_InvalidHeader(t *testing.T) {
+	for _, data := range []string{"", "name\n", "name,balance,email\n", "name,balance,Name\n"} {
+		_, err := NewReader(strings.NewReader(data), request.NewValidator(accountno.DefaultConfig()))
+		assert.ErrorIs(t, err, ErrInvalidHeader, data)
+	}
+}
@@ -2,8 +2,27 @@ package domain
 
 import "github.com/shopspring/decimal"
 
+// 帳號狀態
+const (
+	AccountActive = "active"
+	AccountFrozen = "frozen" // 凍結中，不可存款、提款或轉帳
+)
+
+// ProductStandard 未指定產品時的預設帳號產品
+const ProductStandard = "standard"
+
 type Account struct {
-	ID      string          `json:"id"`
-	Name    string          `json:"name"`
-	Balance decimal.Decimal `json:"balance"`
+	ID           string          `json:"-"`              // 內部 ID (SERIAL)，不對外公開
+	Number       string          `json:"account_number"` // 對外帳號號碼 (含檢查碼)
+	Name         string          `json:"name"`
+	Balance      decimal.Decimal `json:"balance"`
+	Product      string          `json:"product"` // 帳號產品，決定適用的手續費收費表
+	Status       string          `json:"status"`
+	StatusReason string          `json:"status_reason,omitempty"` // 凍結原因
+	HeldBalance  decimal.Decimal `json:"held_balance"`            // 待處理轉帳保留的款項
+}
+
+// Available 可用餘額 (扣除保留款項)
+func (a *Account) Available() decimal.Decimal {
+	return a.Balance.Sub(a.HeldBalance)
 }
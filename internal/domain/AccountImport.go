@@ -0,0 +1,34 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// AccountImport 帳號匯入的結果；有任何一列驗證失敗時不會建立帳號
+type AccountImport struct {
+	DryRun              bool                 `json:"dry_run"`
+	Committed           bool                 `json:"committed"` // 已建立所有帳號
+	Rows                int                  `json:"rows"`
+	Valid               int                  `json:"valid"`
+	Invalid             int                  `json:"invalid"`
+	TotalOpeningBalance decimal.Decimal      `json:"total_opening_balance"` // 驗證通過的列的期初餘額合計
+	Errors              []AccountImportError `json:"errors"`
+	ErrorsTruncated     bool                 `json:"errors_truncated"` // 錯誤過多，只列出前面的部分
+	Accounts            []ImportedAccount    `json:"accounts"`         // 已提交的批次建立的帳號
+	Skipped             []ImportedAccount    `json:"skipped"`          // external_id 先前已匯入而略過的列 (帳號為先前建立的帳號)
+}
+
+// AccountImportError 匯入檔中某一列的欄位錯誤
+type AccountImportError struct {
+	Line       int    `json:"line"`
+	ExternalID string `json:"external_id,omitempty"`
+	Field      string `json:"field"`
+	Message    string `json:"message"`
+}
+
+// ImportedAccount 匯入建立的帳號與來源列
+type ImportedAccount struct {
+	Line          int             `json:"line"`
+	ExternalID    string          `json:"external_id,omitempty"`
+	AccountNumber string          `json:"account_number"`
+	Name          string          `json:"name"`
+	Balance       decimal.Decimal `json:"balance"`
+}
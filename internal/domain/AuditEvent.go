@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 稽核事件類型
+const (
+	AuditAccountCreated        = "account.created"
+	AuditAccountBalanceChanged = "account.balance_changed"
+	AuditAccountStatusChanged  = "account.status_changed"
+	AuditTransferPending       = "transfer.pending"
+	AuditTransferApproved      = "transfer.approved"
+	AuditTransferRejected      = "transfer.rejected"
+	AuditTransferExpired       = "transfer.expired"
+	AuditCustomerCreated       = "customer.created"
+	AuditCustomerKYCChanged    = "customer.kyc_changed"
+	AuditAccountHolderAdded    = "account.holder_added"
+	AuditPayeeAdded            = "payee.added"
+	AuditPayeeUpdated          = "payee.updated"
+	AuditPayeeDeleted          = "payee.deleted"
+	AuditBusinessDayClosed     = "business_day.closed"
+	AuditWebhookCreated        = "webhook.created"
+	AuditWebhookUpdated        = "webhook.updated"
+	AuditWebhookDeleted        = "webhook.deleted"
+	AuditWebhookRedelivered    = "webhook.redelivered"
+)
+
+// AuditEvent 不可變的稽核紀錄，Hash 串連前一筆的 PrevHash 形成 hash chain
+type AuditEvent struct {
+	ID         int64           `json:"id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Actor      string          `json:"actor"`
+	SourceIP   string          `json:"source_ip,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditVerification hash chain 驗證結果
+type AuditVerification struct {
+	Valid         bool   `json:"valid"`
+	EventsChecked int    `json:"events_checked"`
+	BrokenAtID    int64  `json:"broken_at_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
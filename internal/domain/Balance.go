@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BalanceSnapshot 帳號在 AsOf 時的餘額 (created_at <= AsOf 的交易加總)
+type BalanceSnapshot struct {
+	AccountID string          `json:"-"`
+	AsOf      time.Time       `json:"as_of"`
+	Balance   decimal.Decimal `json:"balance"`
+}
+
+// HistoricalBalance 帳號在指定時間點的餘額
+type HistoricalBalance struct {
+	AccountNumber string          `json:"account_number"`
+	At            time.Time       `json:"at"`
+	Balance       decimal.Decimal `json:"balance"`
+}
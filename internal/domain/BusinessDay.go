@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BusinessDayStatus 目前的營業日與結帳進度
+type BusinessDayStatus struct {
+	BusinessDate  string `json:"business_date"`            // 目前寫入交易的營業日
+	ClosedThrough string `json:"closed_through,omitempty"` // 此日 (含) 之前皆已結帳，尚未結帳時省略
+	TimeZone      string `json:"time_zone"`
+}
+
+// BusinessDayClose 日終結帳結果
+type BusinessDayClose struct {
+	BusinessDate string    `json:"business_date"`
+	ClosedDates  []string  `json:"closed_dates"` // 本次結帳的營業日 (含先前未結帳的日期)
+	Snapshots    int       `json:"snapshots"`    // 寫入的日終快照筆數
+	ClosedBy     string    `json:"closed_by"`
+	ClosedAt     time.Time `json:"closed_at"`
+}
+
+// EODBalance 帳號在已結帳營業日的日終快照，寫入後不可修改
+type EODBalance struct {
+	BusinessDate     string          `json:"business_date"`
+	AccountID        string          `json:"-"`
+	AccountNumber    string          `json:"account_number"`
+	OpeningBalance   decimal.Decimal `json:"opening_balance"`
+	TotalDebits      decimal.Decimal `json:"total_debits"`  // 提款合計 (含手續費)
+	TotalCredits     decimal.Decimal `json:"total_credits"` // 存款合計
+	ClosingBalance   decimal.Decimal `json:"closing_balance"`
+	TransactionCount int             `json:"transaction_count"`
+}
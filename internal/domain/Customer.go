@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// KYC 狀態
+const (
+	KYCPending  = "pending" // 尚未完成身分驗證
+	KYCVerified = "verified"
+	KYCRejected = "rejected"
+)
+
+// 帳號持有人角色
+const (
+	HolderPrimary = "primary"
+	HolderJoint   = "joint" // 聯名帳號的其他持有人
+)
+
+// Customer 客戶，一位客戶可持有多個帳號
+type Customer struct {
+	ID             string           `json:"id"` // 對外的客戶 ID (隨機 UUID)
+	LegalName      string           `json:"legal_name"`
+	DateOfBirth    string           `json:"date_of_birth"` // YYYY-MM-DD
+	NationalIDHash string           `json:"-"`             // 身分證號的 HMAC 雜湊，不對外回傳
+	Email          string           `json:"email,omitempty"`
+	Phone          string           `json:"phone,omitempty"`
+	Address        string           `json:"address,omitempty"`
+	KYCStatus      string           `json:"kyc_status"`
+	KYCNote        string           `json:"kyc_note,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	Accounts       []*AccountHolder `json:"accounts,omitempty"` // 查詢單筆時帶持有的帳號
+}
+
+// AccountHolder 帳號與持有人的關聯
+type AccountHolder struct {
+	AccountID     string    `json:"-"` // 內部帳號 ID
+	AccountNumber string    `json:"account_number"`
+	CustomerID    string    `json:"customer_id"`
+	LegalName     string    `json:"legal_name"`
+	Role          string    `json:"role"`
+	KYCStatus     string    `json:"kyc_status"`
+	CreatedAt     time.Time `json:"created_at"`
+}
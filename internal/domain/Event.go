@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 領域事件類型
+const (
+	EventAccountCreated    = "AccountCreated"
+	EventDeposited         = "Deposited"
+	EventWithdrawn         = "Withdrawn"
+	EventTransferCompleted = "TransferCompleted"
+)
+
+// Event 寫入 outbox、送往下游的事件
+type Event struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID string          `json:"aggregate_id"` // 事件所屬的帳號號碼
+	RequestID   string          `json:"request_id,omitempty"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Data        json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// AccountCreatedData AccountCreated 事件內容
+type AccountCreatedData struct {
+	AccountNumber string          `json:"account_number"`
+	Name          string          `json:"name"`
+	Balance       decimal.Decimal `json:"balance"`
+}
+
+// BalanceChangedData Deposited / Withdrawn 事件內容
+type BalanceChangedData struct {
+	AccountNumber string          `json:"account_number"`
+	RefID         string          `json:"ref_id"`
+	Amount        decimal.Decimal `json:"amount"`
+	Balance       decimal.Decimal `json:"balance"`
+}
+
+// TransferCompletedData TransferCompleted 事件內容
+type TransferCompletedData struct {
+	RefID             string          `json:"ref_id"`
+	FromAccountNumber string          `json:"from_account_number"`
+	ToAccountNumber   string          `json:"to_account_number"`
+	Amount            decimal.Decimal `json:"amount"`
+}
+
+// AccountNumbers 事件涉及的帳號號碼 (轉帳包含轉出與轉入帳號)
+func (e *Event) AccountNumbers() []string {
+	if e.Type == EventTransferCompleted {
+		var data TransferCompletedData
+		if err := json.Unmarshal(e.Data, &data); err == nil {
+			return []string{data.FromAccountNumber, data.ToAccountNumber}
+		}
+	}
+	return []string{e.AggregateID}
+}
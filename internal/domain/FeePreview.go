@@ -0,0 +1,13 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// FeePreview 手續費試算結果
+type FeePreview struct {
+	AccountNumber string          `json:"account_number"`
+	Operation     string          `json:"operation"` // withdrawal / transfer
+	Amount        decimal.Decimal `json:"amount"`
+	Fee           decimal.Decimal `json:"fee"`
+	Total         decimal.Decimal `json:"total"`              // 實際扣款金額 (金額 + 手續費)
+	Schedule      string          `json:"schedule,omitempty"` // 適用的收費表
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FraudDecision 每筆轉帳的風控決策與觸發的規則
+type FraudDecision struct {
+	ID                int64           `json:"id"`
+	FromAccountID     string          `json:"-"`
+	ToAccountID       string          `json:"-"`
+	FromAccount       string          `json:"from_account"` // 轉出帳號號碼
+	ToAccount         string          `json:"to_account"`   // 轉入帳號號碼
+	Amount            decimal.Decimal `json:"amount"`
+	Decision          string          `json:"decision"` // allow / review / block
+	Rule              string          `json:"rule,omitempty"`
+	Reason            string          `json:"reason,omitempty"`
+	Hits              json.RawMessage `json:"hits" swaggertype:"array,object"` // 所有觸發的規則
+	PendingTransferID string          `json:"pending_transfer_id,omitempty"`
+	RefID             string          `json:"ref_id,omitempty"` // 放行時的交易 ref_id
+	RequestID         string          `json:"request_id,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+}
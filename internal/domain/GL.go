@@ -0,0 +1,42 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// TrialBalance 截至營業日 (含) 各總帳科目的借方 / 貸方餘額
+type TrialBalance struct {
+	BusinessDate string             `json:"business_date"`
+	Final        bool               `json:"final"` // 營業日已結帳，金額不會再變動
+	Lines        []TrialBalanceLine `json:"lines"`
+	TotalDebits  decimal.Decimal    `json:"total_debits"`
+	TotalCredits decimal.Decimal    `json:"total_credits"`
+	Balanced     bool               `json:"balanced"` // 借方合計等於貸方合計
+}
+
+// TrialBalanceLine 科目的淨餘額，依借貸方向填入 Debit 或 Credit
+type TrialBalanceLine struct {
+	Code   string          `json:"code"`
+	Name   string          `json:"name"`
+	Type   string          `json:"type"` // asset / liability / revenue / expense
+	Debit  decimal.Decimal `json:"debit"`
+	Credit decimal.Decimal `json:"credit"`
+}
+
+// GLSummary 營業日當日各總帳科目的借貸方發生額
+type GLSummary struct {
+	BusinessDate string          `json:"business_date"`
+	Final        bool            `json:"final"`
+	Lines        []GLSummaryLine `json:"lines"`
+	TotalDebits  decimal.Decimal `json:"total_debits"`
+	TotalCredits decimal.Decimal `json:"total_credits"`
+	Balanced     bool            `json:"balanced"`
+}
+
+// GLSummaryLine 科目當日的借方、貸方合計與分錄筆數
+type GLSummaryLine struct {
+	Code    string          `json:"code"`
+	Name    string          `json:"name"`
+	Type    string          `json:"type"`
+	Debits  decimal.Decimal `json:"debits"`
+	Credits decimal.Decimal `json:"credits"`
+	Entries int             `json:"entries"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Payee 帳號的收款人 (約定轉入帳號)，上限為 0 代表不限
+type Payee struct {
+	ID              string          `json:"id"`
+	AccountID       string          `json:"-"` // 轉出帳號的內部 ID
+	PayeeAccountID  string          `json:"-"` // 收款帳號的內部 ID
+	AccountNumber   string          `json:"account_number"`
+	Nickname        string          `json:"nickname"`
+	TransferLimit   decimal.Decimal `json:"transfer_limit"` // 單筆上限
+	DailyLimit      decimal.Decimal `json:"daily_limit"`    // 近 24 小時累計上限
+	CoolingOffUntil time.Time       `json:"cooling_off_until"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// CoolingOff 是否仍在冷靜期內
+func (p *Payee) CoolingOff(now time.Time) bool {
+	return now.Before(p.CoolingOffUntil)
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 轉帳結果
+const (
+	TransferStatusCompleted       = "completed"
+	TransferStatusPendingReview   = "pending_review"   // 風控規則要求人工審核，尚未執行
+	TransferStatusPendingApproval = "pending_approval" // 金額超過覆核門檻，需由另一位使用者核准
+)
+
+// 待處理轉帳狀態
+const (
+	PendingTransferPending  = "pending"
+	PendingTransferApproved = "approved" // 已核准並執行
+	PendingTransferRejected = "rejected"
+	PendingTransferExpired  = "expired" // 逾期未處理，已釋放保留款項
+)
+
+// 待處理轉帳的原因
+const (
+	PendingReasonFraudReview = "fraud_review"
+	PendingReasonApproval    = "approval_required"
+)
+
+// 待處理轉帳的處理紀錄
+const (
+	PendingActionRequested = "requested"
+	PendingActionApproved  = "approved"
+	PendingActionRejected  = "rejected"
+	PendingActionExpired   = "expired"
+)
+
+// TransferResult 轉帳結果，暫緩執行時帶待處理轉帳 ID
+type TransferResult struct {
+	Status            string `json:"status"`
+	RefID             string `json:"ref_id,omitempty"`
+	PendingTransferID string `json:"pending_transfer_id,omitempty"`
+	Rule              string `json:"rule,omitempty"` // 要求審核的風控規則
+}
+
+// PendingTransfer 暫緩執行、等待人工處理的轉帳
+type PendingTransfer struct {
+	ID            string                   `json:"id"`
+	FromAccountID string                   `json:"-"`
+	ToAccountID   string                   `json:"-"`
+	FromAccount   string                   `json:"from_account"` // 轉出帳號號碼
+	ToAccount     string                   `json:"to_account"`   // 轉入帳號號碼
+	Amount        decimal.Decimal          `json:"amount"`
+	Reason        string                   `json:"reason"`
+	Status        string                   `json:"status"`
+	RequestedBy   string                   `json:"requested_by"`
+	RequestID     string                   `json:"request_id,omitempty"`
+	DecidedBy     string                   `json:"decided_by,omitempty"`
+	DecisionNote  string                   `json:"decision_note,omitempty"`
+	RefID         string                   `json:"ref_id,omitempty"`     // 核准執行後的交易 ref_id
+	HeldAmount    decimal.Decimal          `json:"held_amount"`          // 轉出帳號保留的款項 (含手續費)
+	ExpiresAt     *time.Time               `json:"expires_at,omitempty"` // 逾期未處理即失效
+	CreatedAt     time.Time                `json:"created_at"`
+	DecidedAt     *time.Time               `json:"decided_at,omitempty"`
+	History       []*PendingTransferAction `json:"history,omitempty"` // 查詢單筆時帶處理紀錄
+}
+
+// PendingTransferAction 待處理轉帳的一筆處理紀錄
+type PendingTransferAction struct {
+	ID                string    `json:"id"`
+	PendingTransferID string    `json:"pending_transfer_id"`
+	Action            string    `json:"action"`
+	Actor             string    `json:"actor"`
+	Note              string    `json:"note,omitempty"`
+	RequestID         string    `json:"request_id,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
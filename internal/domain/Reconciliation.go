@@ -0,0 +1,19 @@
+package domain
+
+import "github.com/shopspring/decimal"
+
+// Reconciliation 帳號餘額與交易紀錄加總的核對結果
+type Reconciliation struct {
+	Balanced        bool                     `json:"balanced"`
+	AccountsChecked int                      `json:"accounts_checked"`
+	Mismatches      []ReconciliationMismatch `json:"mismatches"`
+}
+
+// ReconciliationMismatch 餘額與交易紀錄不符的帳號
+type ReconciliationMismatch struct {
+	AccountNumber string          `json:"account_number"`
+	Name          string          `json:"name"`
+	Balance       decimal.Decimal `json:"balance"`        // accounts.balance
+	LedgerBalance decimal.Decimal `json:"ledger_balance"` // 存款 - 提款
+	Difference    decimal.Decimal `json:"difference"`     // balance - ledger_balance
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Statement 帳號在期間內的對帳單
+type Statement struct {
+	AccountNumber    string          `json:"account_number"`
+	Name             string          `json:"name"`
+	From             *time.Time      `json:"from,omitempty"`
+	To               *time.Time      `json:"to,omitempty"`
+	OpeningBalance   decimal.Decimal `json:"opening_balance"`
+	ClosingBalance   decimal.Decimal `json:"closing_balance"`
+	TotalDeposits    decimal.Decimal `json:"total_deposits"`
+	TotalWithdrawals decimal.Decimal `json:"total_withdrawals"`
+	Transactions     []*Transaction  `json:"transactions"`
+}
@@ -2,12 +2,32 @@ package domain
 
 import "github.com/shopspring/decimal"
 
+// 交易種類，查詢轉帳、手續費時以此區分共用 ref_id 的紀錄
+const (
+	TxKindDeposit     = "deposit"
+	TxKindWithdrawal  = "withdrawal"
+	TxKindOpening     = "opening" // 開戶時的期初餘額
+	TxKindTransferOut = "transfer_out"
+	TxKindTransferIn  = "transfer_in"
+	TxKindFee         = "fee" // 付款帳號的手續費提款與手續費收入帳號的存款
+)
+
+// 轉帳與手續費交易紀錄的說明前綴，僅供顯示
+const (
+	DescTransferOut = "Transfer to "
+	DescTransferIn  = "Transfer from "
+	DescFee         = "Fee: "
+)
+
 type Transaction struct {
-	ID          int             `json:"id"`
-	Name        string          `json:"name"`
-	Type        int             `json:"type"` // 1=提款, 2=存款
-	Amount      decimal.Decimal `json:"amount"`
-	RefID       string          `json:"ref_id"`
-	Description string          `json:"description"`
-	CreatedAt   string          `json:"created_at"`
+	ID           int             `json:"id"`
+	Name         string          `json:"name"`
+	Type         int             `json:"type"` // 1=提款, 2=存款
+	Kind         string          `json:"kind"` // TxKind*
+	Amount       decimal.Decimal `json:"amount"`
+	RefID        string          `json:"ref_id"`
+	Description  string          `json:"description"`
+	RequestID    string          `json:"request_id,omitempty"` // 對應 HTTP 請求的 X-Request-ID
+	BusinessDate string          `json:"business_date"`        // 所屬營業日 (YYYY-MM-DD)
+	CreatedAt    string          `json:"created_at"`
 }
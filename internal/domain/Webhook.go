@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// webhook 投遞狀態
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead" // 超過重試次數，需手動重送
+)
+
+// WebhookSubscription 合作方訂閱的 webhook
+// EventTypes 為空代表訂閱所有事件，AccountNumber 為空代表不限帳號
+type WebhookSubscription struct {
+	ID            string    `json:"id"`
+	URL           string    `json:"url"`
+	EventTypes    []string  `json:"event_types"`
+	AccountNumber string    `json:"account_number,omitempty"`
+	Secret        string    `json:"secret,omitempty"` // 僅在建立或更換時回傳
+	Active        bool      `json:"active"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery 單一事件對單一訂閱的投遞紀錄
+type WebhookDelivery struct {
+	ID             string     `json:"id"`
+	SubscriptionID string     `json:"subscription_id"`
+	EventID        int64      `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	ResponseStatus int        `json:"response_status,omitempty"` // 最後一次回應的 HTTP status
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,92 @@
+package fee
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// 收費的操作
+const (
+	OpWithdrawal = "withdrawal"
+	OpTransfer   = "transfer"
+)
+
+// Config 手續費設定，收取的手續費會轉入 RevenueAccount (帳號號碼)
+// 收費表僅能由設定檔指定 (fees.schedules)
+type Config struct {
+	Enabled        bool       `yaml:"enabled" toml:"enabled" env:"FEES_ENABLED"`
+	RevenueAccount string     `yaml:"revenue_account" toml:"revenue_account" env:"FEES_REVENUE_ACCOUNT"`
+	Schedules      []Schedule `yaml:"schedules" toml:"schedules"`
+}
+
+// Schedule 單一操作 (與帳號產品) 的收費方式
+// 有 Tiers 時依金額級距計算，否則為 Flat + 金額 * Percent%，結果再以 Min / Max 限制
+type Schedule struct {
+	Name      string          `yaml:"name" toml:"name"`
+	Operation string          `yaml:"operation" toml:"operation"` // withdrawal / transfer
+	Product   string          `yaml:"product" toml:"product"`     // 空白代表所有產品
+	Flat      decimal.Decimal `yaml:"flat" toml:"flat"`
+	Percent   decimal.Decimal `yaml:"percent" toml:"percent"`
+	Min       decimal.Decimal `yaml:"min" toml:"min"`
+	Max       decimal.Decimal `yaml:"max" toml:"max"` // 0 代表不限
+	Tiers     []Tier          `yaml:"tiers" toml:"tiers"`
+}
+
+// Tier 金額 <= UpTo 時適用的收費，UpTo 為 0 代表不限 (只能是最後一級)
+type Tier struct {
+	UpTo    decimal.Decimal `yaml:"up_to" toml:"up_to"`
+	Flat    decimal.Decimal `yaml:"flat" toml:"flat"`
+	Percent decimal.Decimal `yaml:"percent" toml:"percent"`
+}
+
+// DefaultConfig 預設不收費
+func DefaultConfig() Config {
+	return Config{}
+}
+
+// Validate 檢查收費表，同一個操作與產品只能有一個收費表
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	var errs []error
+	if c.RevenueAccount == "" {
+		errs = append(errs, errors.New("fees revenue_account is required when fees are enabled"))
+	}
+	seen := map[[2]string]bool{}
+	for i, s := range c.Schedules {
+		name := s.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if s.Operation != OpWithdrawal && s.Operation != OpTransfer {
+			errs = append(errs, fmt.Errorf("fee schedule %s: unknown operation %q", name, s.Operation))
+		}
+		key := [2]string{s.Operation, s.Product}
+		if seen[key] {
+			errs = append(errs, fmt.Errorf("fee schedule %s: duplicate schedule for operation %q and product %q", name, s.Operation, s.Product))
+		}
+		seen[key] = true
+		if s.Flat.IsNegative() || s.Percent.IsNegative() || s.Min.IsNegative() || s.Max.IsNegative() {
+			errs = append(errs, fmt.Errorf("fee schedule %s: amounts must not be negative", name))
+		}
+		if s.Max.IsPositive() && s.Max.LessThan(s.Min) {
+			errs = append(errs, fmt.Errorf("fee schedule %s: max must be >= min", name))
+		}
+		for j, t := range s.Tiers {
+			if t.Flat.IsNegative() || t.Percent.IsNegative() || t.UpTo.IsNegative() {
+				errs = append(errs, fmt.Errorf("fee schedule %s: tier %d amounts must not be negative", name, j+1))
+			}
+			last := j == len(s.Tiers)-1
+			if t.UpTo.IsZero() && !last {
+				errs = append(errs, fmt.Errorf("fee schedule %s: only the last tier may be unbounded", name))
+			}
+			if j > 0 && !t.UpTo.IsZero() && !t.UpTo.GreaterThan(s.Tiers[j-1].UpTo) {
+				errs = append(errs, fmt.Errorf("fee schedule %s: tiers must be in ascending up_to order", name))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
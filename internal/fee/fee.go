@@ -0,0 +1,78 @@
+// Package fee 依收費表計算提款與轉帳的手續費
+package fee
+
+import "github.com/shopspring/decimal"
+
+var hundred = decimal.NewFromInt(100)
+
+// Quote 手續費試算結果
+type Quote struct {
+	Fee      decimal.Decimal
+	Schedule string // 適用的收費表名稱，未收費時為空
+}
+
+// Engine 依操作與帳號產品挑選收費表
+type Engine struct {
+	revenueAccount string
+	schedules      []Schedule
+}
+
+// NewEngine 未啟用時回傳 nil (不收費)
+func NewEngine(cfg Config) *Engine {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Engine{revenueAccount: cfg.RevenueAccount, schedules: cfg.Schedules}
+}
+
+// RevenueAccount 手續費收入帳號的號碼
+func (e *Engine) RevenueAccount() string {
+	if e == nil {
+		return ""
+	}
+	return e.revenueAccount
+}
+
+// Quote 計算手續費，優先使用指定產品的收費表，其次為不限產品的收費表
+func (e *Engine) Quote(op, product string, amount decimal.Decimal) Quote {
+	if e == nil {
+		return Quote{}
+	}
+	var match *Schedule
+	for i := range e.schedules {
+		s := &e.schedules[i]
+		if s.Operation != op {
+			continue
+		}
+		if s.Product == product {
+			match = s
+			break
+		}
+		if s.Product == "" && match == nil {
+			match = s
+		}
+	}
+	if match == nil {
+		return Quote{}
+	}
+	return Quote{Fee: match.Calculate(amount), Schedule: match.Name}
+}
+
+// Calculate 計算金額的手續費 (四捨五入至小數 2 位)
+func (s *Schedule) Calculate(amount decimal.Decimal) decimal.Decimal {
+	flat, percent := s.Flat, s.Percent
+	for _, t := range s.Tiers {
+		if t.UpTo.IsZero() || amount.LessThanOrEqual(t.UpTo) {
+			flat, percent = t.Flat, t.Percent
+			break
+		}
+	}
+	fee := flat.Add(amount.Mul(percent).Div(hundred))
+	if fee.LessThan(s.Min) {
+		fee = s.Min
+	}
+	if s.Max.IsPositive() && fee.GreaterThan(s.Max) {
+		fee = s.Max
+	}
+	return fee.Round(2)
+}
@@ -0,0 +1,69 @@
+package fee
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func d(s string) decimal.Decimal { return decimal.RequireFromString(s) }
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		amount   string
+		want     string
+	}{
+		{"flat", Schedule{Flat: d("15")}, "1000", "15"},
+		{"percent", Schedule{Percent: d("0.5")}, "1000", "5"},
+		{"percent below min", Schedule{Percent: d("0.5"), Min: d("10")}, "1000", "10"},
+		{"percent above max", Schedule{Percent: d("0.5"), Max: d("100")}, "50000", "100"},
+		{"flat plus percent rounded", Schedule{Flat: d("1"), Percent: d("0.333")}, "100.50", "1.33"},
+		{"first tier", Schedule{Tiers: []Tier{{UpTo: d("1000"), Flat: d("10")}, {UpTo: d("0"), Percent: d("1")}}}, "1000", "10"},
+		{"unbounded tier", Schedule{Tiers: []Tier{{UpTo: d("1000"), Flat: d("10")}, {UpTo: d("0"), Percent: d("1")}}}, "5000", "50"},
+		{"above all tiers falls back", Schedule{Flat: d("99"), Tiers: []Tier{{UpTo: d("1000"), Flat: d("10")}}}, "5000", "99"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.schedule.Calculate(d(tt.amount)).String())
+		})
+	}
+}
+
+// 單元測試 Quote：優先使用指定產品的收費表
+func TestQuote(t *testing.T) {
+	e := NewEngine(Config{Enabled: true, RevenueAccount: "100000000016", Schedules: []Schedule{
+		{Name: "standard-transfer", Operation: OpTransfer, Flat: d("15")},
+		{Name: "premium-transfer", Operation: OpTransfer, Product: "premium"},
+		{Name: "withdrawal", Operation: OpWithdrawal, Percent: d("1"), Min: d("5")},
+	}})
+
+	q := e.Quote(OpTransfer, "standard", d("100"))
+	assert.Equal(t, "standard-transfer", q.Schedule)
+	assert.Equal(t, "15", q.Fee.String())
+	assert.Equal(t, "premium-transfer", e.Quote(OpTransfer, "premium", d("100")).Schedule)
+	assert.True(t, e.Quote(OpTransfer, "premium", d("100")).Fee.IsZero())
+	assert.Equal(t, "5", e.Quote(OpWithdrawal, "premium", d("100")).Fee.String())
+
+	var disabled *Engine = NewEngine(Config{})
+	assert.Nil(t, disabled)
+	assert.True(t, disabled.Quote(OpTransfer, "standard", d("100")).Fee.IsZero())
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.ErrorContains(t, Config{Enabled: true}.Validate(), "revenue_account is required")
+
+	err := Config{Enabled: true, RevenueAccount: "100000000016", Schedules: []Schedule{
+		{Name: "a", Operation: "deposit"},
+		{Name: "b", Operation: OpTransfer, Min: d("10"), Max: d("5")},
+		{Name: "c", Operation: OpTransfer},
+		{Name: "d", Operation: OpWithdrawal, Tiers: []Tier{{UpTo: d("0")}, {UpTo: d("100")}}},
+	}}.Validate()
+	assert.ErrorContains(t, err, `unknown operation "deposit"`)
+	assert.ErrorContains(t, err, "max must be >= min")
+	assert.ErrorContains(t, err, "duplicate schedule")
+	assert.ErrorContains(t, err, "only the last tier may be unbounded")
+}
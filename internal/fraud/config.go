@@ -0,0 +1,77 @@
+package fraud
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Config 轉帳風控規則設定，規則的門檻為 0 時停用該規則
+type Config struct {
+	Enabled     bool              `yaml:"enabled" toml:"enabled" env:"FRAUD_ENABLED"`
+	Velocity    VelocityConfig    `yaml:"velocity" toml:"velocity"`
+	NewPayee    NewPayeeConfig    `yaml:"new_payee" toml:"new_payee"`
+	Structuring StructuringConfig `yaml:"structuring" toml:"structuring"`
+	Blocklist   []string          `yaml:"blocklist" toml:"blocklist" env:"FRAUD_BLOCKLIST"` // 禁止轉出或轉入的帳號號碼
+}
+
+// VelocityConfig Window 期間內轉出超過 MaxTransfers 筆
+type VelocityConfig struct {
+	MaxTransfers int           `yaml:"max_transfers" toml:"max_transfers" env:"FRAUD_VELOCITY_MAX_TRANSFERS"`
+	Window       time.Duration `yaml:"window" toml:"window" env:"FRAUD_VELOCITY_WINDOW"`
+	Action       string        `yaml:"action" toml:"action" env:"FRAUD_VELOCITY_ACTION"`
+}
+
+// NewPayeeConfig 首次轉給某個帳號且金額達 Amount
+type NewPayeeConfig struct {
+	Amount decimal.Decimal `yaml:"amount" toml:"amount"`
+	Action string          `yaml:"action" toml:"action" env:"FRAUD_NEW_PAYEE_ACTION"`
+}
+
+// StructuringConfig Window 期間內 Multiple 整數倍的轉帳 (含本次) 達 Count 筆，疑似拆分金額規避申報
+type StructuringConfig struct {
+	Multiple decimal.Decimal `yaml:"multiple" toml:"multiple"`
+	Count    int             `yaml:"count" toml:"count" env:"FRAUD_STRUCTURING_COUNT"`
+	Window   time.Duration   `yaml:"window" toml:"window" env:"FRAUD_STRUCTURING_WINDOW"`
+	Action   string          `yaml:"action" toml:"action" env:"FRAUD_STRUCTURING_ACTION"`
+}
+
+// DefaultConfig 預設不啟用，啟用後的規則皆轉人工審核
+func DefaultConfig() Config {
+	return Config{
+		Velocity:    VelocityConfig{MaxTransfers: 10, Window: 10 * time.Minute, Action: Review},
+		NewPayee:    NewPayeeConfig{Amount: decimal.NewFromInt(50000), Action: Review},
+		Structuring: StructuringConfig{Multiple: decimal.NewFromInt(1000), Count: 3, Window: 24 * time.Hour, Action: Review},
+	}
+}
+
+// Validate 檢查規則的處置方式與期間
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	var errs []error
+	action := func(rule, a string) {
+		if a != Review && a != Block {
+			errs = append(errs, fmt.Errorf("fraud %s action must be %q or %q", rule, Review, Block))
+		}
+	}
+	if c.Velocity.MaxTransfers > 0 {
+		action(RuleVelocity, c.Velocity.Action)
+		if c.Velocity.Window <= 0 {
+			errs = append(errs, errors.New("fraud velocity window must be positive"))
+		}
+	}
+	if c.NewPayee.Amount.IsPositive() {
+		action(RuleNewPayee, c.NewPayee.Action)
+	}
+	if c.Structuring.Count > 0 {
+		action(RuleStructuring, c.Structuring.Action)
+		if !c.Structuring.Multiple.IsPositive() || c.Structuring.Window <= 0 {
+			errs = append(errs, errors.New("fraud structuring multiple and window must be positive"))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,119 @@
+package fraud
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// 規則的決策，嚴重程度 Allow < Review < Block
+const (
+	Allow  = "allow"
+	Review = "review" // 暫緩執行，等待人工審核
+	Block  = "block"  // 拒絕轉帳
+)
+
+// Transfer 待檢查的轉帳
+// FromID / ToID 為內部帳號 ID，用於查詢歷史轉帳；黑名單與決策原因使用帳號號碼
+type Transfer struct {
+	FromID     string
+	ToID       string
+	FromNumber string
+	ToNumber   string
+	Amount     decimal.Decimal
+	At         time.Time
+}
+
+// History 規則查詢轉出帳號的歷史轉帳
+type History interface {
+	// TransferAmountsSince 帳號自 since 起 (含) 嘗試轉出的金額，包含待處理與被拒絕的轉帳
+	TransferAmountsSince(ctx context.Context, fromID string, since time.Time) ([]decimal.Decimal, error)
+	// HasTransferredTo 帳號是否曾轉帳給 toID
+	HasTransferredTo(ctx context.Context, fromID, toID string) (bool, error)
+}
+
+// Hit 觸發的規則
+type Hit struct {
+	Rule     string `json:"rule"`
+	Decision string `json:"decision"`
+	Reason   string `json:"reason"`
+}
+
+// Rule 風控規則，未觸發時回傳 nil
+type Rule interface {
+	Name() string
+	Evaluate(ctx context.Context, t Transfer, h History) (*Hit, error)
+}
+
+// Result 所有規則的檢查結果，Decision 取最嚴重的規則
+type Result struct {
+	Decision string
+	Rule     string // 決定結果的規則，Allow 時為空
+	Reason   string
+	Hits     []Hit
+}
+
+// Engine 依序執行所有規則
+type Engine struct {
+	rules []Rule
+}
+
+// NewEngine 依設定建立內建規則，extra 為額外的自訂規則；未啟用時回傳 nil (一律放行)
+func NewEngine(cfg Config, extra ...Rule) *Engine {
+	if !cfg.Enabled {
+		return nil
+	}
+	e := &Engine{}
+	if len(cfg.Blocklist) > 0 {
+		e.rules = append(e.rules, NewBlocklist(cfg.Blocklist))
+	}
+	if cfg.Velocity.MaxTransfers > 0 {
+		e.rules = append(e.rules, &Velocity{Config: cfg.Velocity})
+	}
+	if cfg.NewPayee.Amount.IsPositive() {
+		e.rules = append(e.rules, &NewPayee{Config: cfg.NewPayee})
+	}
+	if cfg.Structuring.Count > 0 {
+		e.rules = append(e.rules, &Structuring{Config: cfg.Structuring})
+	}
+	e.rules = append(e.rules, extra...)
+	return e
+}
+
+// Enabled 是否需要檢查轉帳
+func (e *Engine) Enabled() bool {
+	return e != nil
+}
+
+// Screen 執行所有規則，同樣嚴重時以先觸發的規則為準
+func (e *Engine) Screen(ctx context.Context, t Transfer, h History) (Result, error) {
+	res := Result{Decision: Allow}
+	if e == nil {
+		return res, nil
+	}
+	for _, rule := range e.rules {
+		hit, err := rule.Evaluate(ctx, t, h)
+		if err != nil {
+			return Result{}, err
+		}
+		if hit == nil {
+			continue
+		}
+		res.Hits = append(res.Hits, *hit)
+		if severity(hit.Decision) > severity(res.Decision) {
+			res.Decision, res.Rule, res.Reason = hit.Decision, hit.Rule, hit.Reason
+		}
+	}
+	return res, nil
+}
+
+func severity(decision string) int {
+	switch decision {
+	case Review:
+		return 1
+	case Block:
+		return 2
+	}
+	return 0
+}
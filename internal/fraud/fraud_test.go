@@ -0,0 +1,143 @@
+package fraud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func d(s string) decimal.Decimal { return decimal.RequireFromString(s) }
+
+// 測試用的轉帳紀錄
+type fakeHistory struct {
+	amounts []decimal.Decimal
+	payees  map[string]bool
+	since   time.Time
+}
+
+func (h *fakeHistory) TransferAmountsSince(_ context.Context, _ string, since time.Time) ([]decimal.Decimal, error) {
+	h.since = since
+	return h.amounts, nil
+}
+
+func (h *fakeHistory) HasTransferredTo(_ context.Context, _, toID string) (bool, error) {
+	return h.payees[toID], nil
+}
+
+var now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		amount  string
+		history []string
+		payees  map[string]bool
+		want    string // 空白代表未觸發
+	}{
+		{"velocity under limit", &Velocity{Config: VelocityConfig{MaxTransfers: 3, Window: time.Hour, Action: Review}}, "10", []string{"1", "2"}, nil, ""},
+		{"velocity over limit", &Velocity{Config: VelocityConfig{MaxTransfers: 3, Window: time.Hour, Action: Block}}, "10", []string{"1", "2", "3"}, nil, Block},
+		{"new payee small amount", &NewPayee{Config: NewPayeeConfig{Amount: d("1000"), Action: Review}}, "999.99", nil, nil, ""},
+		{"new payee large amount", &NewPayee{Config: NewPayeeConfig{Amount: d("1000"), Action: Review}}, "1000", nil, nil, Review},
+		{"known payee large amount", &NewPayee{Config: NewPayeeConfig{Amount: d("1000"), Action: Review}}, "5000", nil, map[string]bool{"2": true}, ""},
+		{"structuring non-round amount", &Structuring{Config: StructuringConfig{Multiple: d("1000"), Count: 2, Window: time.Hour, Action: Review}}, "1500", []string{"9000"}, nil, ""},
+		{"structuring too few", &Structuring{Config: StructuringConfig{Multiple: d("1000"), Count: 3, Window: time.Hour, Action: Review}}, "9000", []string{"9000", "850"}, nil, ""},
+		{"structuring", &Structuring{Config: StructuringConfig{Multiple: d("1000"), Count: 3, Window: time.Hour, Action: Review}}, "9000", []string{"9000", "850", "8000"}, nil, Review},
+		{"blocklisted payee", NewBlocklist([]string{"100000000024"}), "10", nil, nil, Block},
+		{"blocklist other accounts", NewBlocklist([]string{"100000000032"}), "10", nil, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &fakeHistory{payees: tt.payees}
+			for _, a := range tt.history {
+				h.amounts = append(h.amounts, d(a))
+			}
+			hit, err := tt.rule.Evaluate(context.Background(), Transfer{FromID: "1", ToID: "2", FromNumber: "100000000016", ToNumber: "100000000024", Amount: d(tt.amount), At: now}, h)
+			assert.NoError(t, err)
+			if tt.want == "" {
+				assert.Nil(t, hit)
+				return
+			}
+			if assert.NotNil(t, hit) {
+				assert.Equal(t, tt.want, hit.Decision)
+				assert.Equal(t, tt.rule.Name(), hit.Rule)
+			}
+		})
+	}
+}
+
+// 單元測試 Velocity 依設定的期間查詢歷史轉帳
+func TestVelocity_Window(t *testing.T) {
+	h := &fakeHistory{}
+	rule := &Velocity{Config: VelocityConfig{MaxTransfers: 1, Window: 10 * time.Minute, Action: Review}}
+	_, err := rule.Evaluate(context.Background(), Transfer{FromID: "1", ToID: "2", FromNumber: "100000000016", ToNumber: "100000000024", Amount: d("1"), At: now}, h)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(-10*time.Minute), h.since)
+}
+
+// 單元測試 Screen 取最嚴重的決策並保留所有觸發的規則
+func TestScreen(t *testing.T) {
+	cfg := Config{
+		Enabled:     true,
+		Velocity:    VelocityConfig{MaxTransfers: 1, Window: time.Hour, Action: Review},
+		NewPayee:    NewPayeeConfig{Amount: d("100"), Action: Review},
+		Structuring: StructuringConfig{Multiple: d("1000"), Count: 5, Window: time.Hour, Action: Block},
+		Blocklist:   []string{"100000000099"},
+	}
+	e := NewEngine(cfg)
+	h := &fakeHistory{amounts: []decimal.Decimal{d("5")}}
+
+	res, err := e.Screen(context.Background(), Transfer{FromID: "1", ToID: "2", FromNumber: "100000000016", ToNumber: "100000000024", Amount: d("500"), At: now}, h)
+	assert.NoError(t, err)
+	assert.Equal(t, Review, res.Decision)
+	assert.Equal(t, RuleVelocity, res.Rule) // 同樣嚴重時以先觸發的為準
+	assert.Len(t, res.Hits, 2)
+
+	res, err = e.Screen(context.Background(), Transfer{FromID: "1", ToID: "9", FromNumber: "100000000016", ToNumber: "100000000099", Amount: d("500"), At: now}, h)
+	assert.NoError(t, err)
+	assert.Equal(t, Block, res.Decision)
+	assert.Equal(t, RuleBlocklist, res.Rule)
+	assert.Len(t, res.Hits, 3)
+
+	// 未啟用時一律放行
+	var disabled *Engine
+	res, err = disabled.Screen(context.Background(), Transfer{FromID: "1", ToID: "9", FromNumber: "100000000016", ToNumber: "100000000099", Amount: d("500"), At: now}, h)
+	assert.NoError(t, err)
+	assert.Equal(t, Allow, res.Decision)
+	assert.Nil(t, NewEngine(Config{}))
+}
+
+// 單元測試 自訂規則可加入 Engine
+func TestScreen_CustomRule(t *testing.T) {
+	e := NewEngine(Config{Enabled: true}, ruleFunc(func(t Transfer) *Hit {
+		if t.Amount.GreaterThan(d("100")) {
+			return &Hit{Rule: "custom", Decision: Block, Reason: "too large"}
+		}
+		return nil
+	}))
+	res, err := e.Screen(context.Background(), Transfer{Amount: d("101")}, &fakeHistory{})
+	assert.NoError(t, err)
+	assert.Equal(t, "custom", res.Rule)
+}
+
+type ruleFunc func(t Transfer) *Hit
+
+func (f ruleFunc) Name() string { return "custom" }
+
+func (f ruleFunc) Evaluate(_ context.Context, t Transfer, _ History) (*Hit, error) { return f(t), nil }
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Config{Enabled: true}.Validate())
+	cfg := DefaultConfig()
+	cfg.Enabled = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Velocity.Action = Allow
+	cfg.Structuring.Window = 0
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, "fraud velocity action")
+	assert.ErrorContains(t, err, "fraud structuring multiple and window")
+}
@@ -0,0 +1,111 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// 內建規則名稱
+const (
+	RuleBlocklist   = "blocklist"
+	RuleVelocity    = "velocity"
+	RuleNewPayee    = "new_payee"
+	RuleStructuring = "structuring"
+)
+
+// Blocklist 轉出或轉入帳號在黑名單中時拒絕
+type Blocklist struct {
+	accounts map[string]bool
+}
+
+// NewBlocklist 以帳號號碼建立黑名單規則
+func NewBlocklist(accounts []string) *Blocklist {
+	b := &Blocklist{accounts: make(map[string]bool, len(accounts))}
+	for _, number := range accounts {
+		b.accounts[number] = true
+	}
+	return b
+}
+
+func (b *Blocklist) Name() string { return RuleBlocklist }
+
+func (b *Blocklist) Evaluate(_ context.Context, t Transfer, _ History) (*Hit, error) {
+	for _, number := range []string{t.FromNumber, t.ToNumber} {
+		if b.accounts[number] {
+			return &Hit{Rule: RuleBlocklist, Decision: Block, Reason: fmt.Sprintf("account %s is blocklisted", number)}, nil
+		}
+	}
+	return nil, nil
+}
+
+// Velocity 短時間內轉出筆數過多
+type Velocity struct {
+	Config VelocityConfig
+}
+
+func (v *Velocity) Name() string { return RuleVelocity }
+
+func (v *Velocity) Evaluate(ctx context.Context, t Transfer, h History) (*Hit, error) {
+	amounts, err := h.TransferAmountsSince(ctx, t.FromID, t.At.Add(-v.Config.Window))
+	if err != nil {
+		return nil, err
+	}
+	if n := len(amounts) + 1; n > v.Config.MaxTransfers {
+		return &Hit{Rule: RuleVelocity, Decision: v.Config.Action,
+			Reason: fmt.Sprintf("%d transfers within %s exceeds %d", n, v.Config.Window, v.Config.MaxTransfers)}, nil
+	}
+	return nil, nil
+}
+
+// NewPayee 首次轉給某個帳號的大額轉帳
+type NewPayee struct {
+	Config NewPayeeConfig
+}
+
+func (p *NewPayee) Name() string { return RuleNewPayee }
+
+func (p *NewPayee) Evaluate(ctx context.Context, t Transfer, h History) (*Hit, error) {
+	if t.Amount.LessThan(p.Config.Amount) {
+		return nil, nil
+	}
+	known, err := h.HasTransferredTo(ctx, t.FromID, t.ToID)
+	if err != nil || known {
+		return nil, err
+	}
+	return &Hit{Rule: RuleNewPayee, Decision: p.Config.Action,
+		Reason: fmt.Sprintf("first transfer to account %s is at least %s", t.ToNumber, p.Config.Amount)}, nil
+}
+
+// Structuring 連續的整數金額轉帳
+type Structuring struct {
+	Config StructuringConfig
+}
+
+func (s *Structuring) Name() string { return RuleStructuring }
+
+func (s *Structuring) Evaluate(ctx context.Context, t Transfer, h History) (*Hit, error) {
+	if !s.round(t.Amount) {
+		return nil, nil
+	}
+	amounts, err := h.TransferAmountsSince(ctx, t.FromID, t.At.Add(-s.Config.Window))
+	if err != nil {
+		return nil, err
+	}
+	n := 1
+	for _, a := range amounts {
+		if s.round(a) {
+			n++
+		}
+	}
+	if n < s.Config.Count {
+		return nil, nil
+	}
+	return &Hit{Rule: RuleStructuring, Decision: s.Config.Action,
+		Reason: fmt.Sprintf("%d transfers in multiples of %s within %s", n, s.Config.Multiple, s.Config.Window)}, nil
+}
+
+func (s *Structuring) round(amount decimal.Decimal) bool {
+	return amount.IsPositive() && amount.Mod(s.Config.Multiple).IsZero()
+}
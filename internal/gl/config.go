@@ -0,0 +1,84 @@
+package gl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 科目類型，決定正常餘額方向
+const (
+	TypeAsset     = "asset"
+	TypeLiability = "liability"
+	TypeRevenue   = "revenue"
+	TypeExpense   = "expense"
+)
+
+// 科目表中各科目的識別，交易依此分類後再對應到設定的科目代碼
+const (
+	KeyCash             = "cash"
+	KeyCustomerDeposits = "customer_deposits"
+	KeyFeeRevenue       = "fee_revenue"
+	KeyInterestExpense  = "interest_expense"
+)
+
+// Account 總帳科目
+type Account struct {
+	Code string `yaml:"code" toml:"code"`
+	Name string `yaml:"name" toml:"name"`
+}
+
+// Config 會計科目表與內部帳號的對應
+// 一般帳號的交易記入客戶存款，存提款 (非轉帳、非手續費) 另以現金為對方科目
+// 手續費收入帳號 (fees.revenue_account) 與利息費用帳號的交易記入各自的科目
+type Config struct {
+	Cash             Account `yaml:"cash" toml:"cash"`
+	CustomerDeposits Account `yaml:"customer_deposits" toml:"customer_deposits"`
+	FeeRevenue       Account `yaml:"fee_revenue" toml:"fee_revenue"`
+	InterestExpense  Account `yaml:"interest_expense" toml:"interest_expense"`
+	// 支付利息的內部帳號號碼，由此帳號轉給客戶的利息記為利息費用；空值代表沒有利息帳號
+	InterestExpenseAccount string `yaml:"interest_expense_account" toml:"interest_expense_account" env:"GL_INTEREST_EXPENSE_ACCOUNT"`
+}
+
+// DefaultConfig 預設科目代碼
+func DefaultConfig() Config {
+	return Config{
+		Cash:             Account{Code: "1000", Name: "Cash"},
+		CustomerDeposits: Account{Code: "2000", Name: "Customer deposits"},
+		FeeRevenue:       Account{Code: "4000", Name: "Fee revenue"},
+		InterestExpense:  Account{Code: "5000", Name: "Interest expense"},
+	}
+}
+
+// Validate 科目代碼必填且不可重複
+func (c Config) Validate() error {
+	var errs []error
+	seen := map[string]string{}
+	for _, e := range c.Chart() {
+		if e.Code == "" {
+			errs = append(errs, fmt.Errorf("gl.%s.code is required", e.Key))
+			continue
+		}
+		if other, ok := seen[e.Code]; ok {
+			errs = append(errs, fmt.Errorf("gl.%s.code %q is already used by gl.%s", e.Key, e.Code, other))
+		}
+		seen[e.Code] = e.Key
+	}
+	return errors.Join(errs...)
+}
+
+// Entry 科目表中的一個科目
+type Entry struct {
+	Account
+	Key  string
+	Type string
+}
+
+// Chart 科目表，依資產、負債、收入、費用排列
+func (c Config) Chart() []Entry {
+	return []Entry{
+		{Account: c.Cash, Key: KeyCash, Type: TypeAsset},
+		{Account: c.CustomerDeposits, Key: KeyCustomerDeposits, Type: TypeLiability},
+		{Account: c.FeeRevenue, Key: KeyFeeRevenue, Type: TypeRevenue},
+		{Account: c.InterestExpense, Key: KeyInterestExpense, Type: TypeExpense},
+	}
+}
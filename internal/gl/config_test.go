@@ -0,0 +1,18 @@
+package gl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig().Validate())
+
+	cfg := DefaultConfig()
+	cfg.FeeRevenue.Code = "1000"
+	cfg.InterestExpense.Code = ""
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, `gl.fee_revenue.code "1000" is already used by gl.cash`)
+	assert.ErrorContains(t, err, "gl.interest_expense.code is required")
+}
@@ -0,0 +1,87 @@
+package grpcapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// 錯誤詳細資訊使用的 domain
+const errorDomain = "bank.v1"
+
+// toStatus 將 service 錯誤轉為 gRPC status
+// 驗證錯誤附帶 BadRequest (逐欄位)，商業規則錯誤附帶 ErrorInfo (可供程式判斷的 reason)
+// 其他錯誤只記錄於日誌，回傳不含內部細節 (SQL、連線資訊) 的 Internal
+func toStatus(ctx context.Context, err error) error {
+	var verrs request.ValidationErrors
+	switch {
+	case errors.As(err, &verrs):
+		br := &errdetails.BadRequest{}
+		for _, fe := range verrs {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       fe.Field,
+				Description: fe.Message,
+			})
+		}
+		return withDetails(codes.InvalidArgument, err.Error(), br)
+	case errors.Is(err, sql.ErrNoRows):
+		return status.Error(codes.NotFound, "account not found")
+	case errors.Is(err, service.ErrInsufficientFunds):
+		return withDetails(codes.FailedPrecondition, err.Error(), &errdetails.ErrorInfo{
+			Reason: "INSUFFICIENT_FUNDS",
+			Domain: errorDomain,
+		})
+	case errors.Is(err, service.ErrAccountFrozen):
+		return withDetails(codes.FailedPrecondition, err.Error(), &errdetails.ErrorInfo{
+			Reason: "ACCOUNT_FROZEN",
+			Domain: errorDomain,
+		})
+	case errors.Is(err, service.ErrBusinessDateClosed):
+		return withDetails(codes.FailedPrecondition, err.Error(), &errdetails.ErrorInfo{
+			Reason: "BUSINESS_DATE_CLOSED",
+			Domain: errorDomain,
+		})
+	case errors.Is(err, service.ErrTransferBlocked):
+		return withDetails(codes.PermissionDenied, err.Error(), &errdetails.ErrorInfo{
+			Reason: "TRANSFER_BLOCKED",
+			Domain: errorDomain,
+		})
+	case errors.Is(err, service.ErrKYCNotVerified):
+		return withDetails(codes.PermissionDenied, err.Error(), &errdetails.ErrorInfo{
+			Reason: "KYC_NOT_VERIFIED",
+			Domain: errorDomain,
+		})
+	case errors.Is(err, service.ErrPayeeLimitExceeded):
+		return withDetails(codes.PermissionDenied, err.Error(), &errdetails.ErrorInfo{
+			Reason: "PAYEE_LIMIT_EXCEEDED",
+			Domain: errorDomain,
+		})
+	case errors.Is(err, service.ErrPayeeNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	slog.ErrorContext(ctx, "grpc internal error", "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
+
+func withDetails(code codes.Code, msg string, details ...protoadapt.MessageV1) error {
+	st := status.New(code, msg)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
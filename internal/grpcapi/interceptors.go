@@ -0,0 +1,193 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/grpcapi/bankv1"
+	"github.com/yoyo0827/simple-bank-system/internal/logging"
+	"github.com/yoyo0827/simple-bank-system/internal/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// gRPC metadata key (皆為小寫)
+const (
+	metadataAPIKey        = "x-api-key"
+	metadataAuthorization = "authorization"
+	metadataRequestID     = "x-request-id"
+	metadataRetryAfter    = "retry-after"
+)
+
+// 套用 rate limit 的方法與對應的 HTTP 路由，兩者共用同一組規則與令牌桶
+var rateLimitRoutes = map[string]string{
+	bankv1.BankService_CreateAccount_FullMethodName: "POST /accounts",
+	bankv1.BankService_Deposit_FullMethodName:       "POST /accounts/{id}/transactions",
+	bankv1.BankService_Withdraw_FullMethodName:      "POST /accounts/{id}/transactions",
+	bankv1.BankService_Transfer_FullMethodName:      "POST /accounts/transfer",
+}
+
+// 不需認證的服務 (reflection 與 health check)
+var publicServices = []string{
+	"/grpc.reflection.",
+	"/grpc.health.",
+}
+
+// LoggingUnaryInterceptor 設定 request ID 並記錄 access log
+func LoggingUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx = withRequestID(ctx)
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logCall(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+// LoggingStreamInterceptor 串流版本的 LoggingUnaryInterceptor
+func LoggingStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := withRequestID(ss.Context())
+	start := time.Now()
+	err := handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	logCall(ctx, info.FullMethod, start, err)
+	return err
+}
+
+// AuthUnaryInterceptor 依 API key 驗證呼叫者，規則與 HTTP 相同：
+// 未設定任何 API key 時皆為匿名 (role user)，否則必須帶入合法的 key
+func AuthUnaryInterceptor(authn *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, authn, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor 串流版本的 AuthUnaryInterceptor
+func AuthStreamInterceptor(authn *auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), authn, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// RateLimitUnaryInterceptor 以與 HTTP 相同的規則限制資金異動與開戶，需在 AuthUnaryInterceptor 之後執行
+// 超過限制時回 ResourceExhausted，並於 retry-after header 帶建議的重試秒數
+func RateLimitUnaryInterceptor(l *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		route, ok := rateLimitRoutes[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		res, _, ok := l.Check(ctx, route, func() string { return requestAccount(req) })
+		if ok && !res.Allowed {
+			_ = grpc.SetHeader(ctx, metadata.Pairs(metadataRetryAfter, strconv.Itoa(ratelimit.RetryAfterSeconds(res))))
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// 請求操作的帳號號碼，對應 HTTP 路徑的 {id} 或轉帳的 from_account
+func requestAccount(req any) string {
+	switch r := req.(type) {
+	case *bankv1.DepositRequest:
+		return r.GetAccountNumber()
+	case *bankv1.WithdrawRequest:
+		return r.GetAccountNumber()
+	case *bankv1.TransferRequest:
+		return r.GetFromAccount()
+	}
+	return ""
+}
+
+func authenticate(ctx context.Context, authn *auth.Authenticator, method string) (context.Context, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		ctx = auth.WithClientIP(ctx, peerIP(p.Addr))
+	}
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(method, prefix) {
+			return ctx, nil
+		}
+	}
+	if !authn.Enabled() {
+		return auth.WithPrincipal(ctx, auth.Anonymous), nil
+	}
+	key := apiKey(ctx)
+	if key == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing API key")
+	}
+	p, ok := authn.Authenticate(key)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid API key")
+	}
+	return auth.WithPrincipal(ctx, p), nil
+}
+
+func apiKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(metadataAPIKey); len(v) > 0 {
+		return v[0]
+	}
+	if v := md.Get(metadataAuthorization); len(v) > 0 {
+		if token, ok := strings.CutPrefix(v[0], "Bearer "); ok {
+			return token
+		}
+	}
+	return ""
+}
+
+// 沿用上游傳入的 x-request-id (或產生新的)，並回傳給呼叫端
+func withRequestID(ctx context.Context) context.Context {
+	var upstream string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(metadataRequestID); len(v) > 0 {
+			upstream = v[0]
+		}
+	}
+	id := logging.EnsureRequestID(upstream)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(metadataRequestID, id))
+	return logging.WithRequestID(ctx, id)
+}
+
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	if code == codes.Internal || code == codes.Unknown {
+		level = slog.LevelError
+	}
+	slog.Log(ctx, level, "grpc request",
+		"method", method,
+		"code", code.String(),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+}
+
+func peerIP(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// wrappedStream 替換串流的 context
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedStream) Context() context.Context {
+	return s.ctx
+}
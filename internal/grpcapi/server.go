@@ -0,0 +1,220 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/grpcapi/bankv1"
+	"github.com/yoyo0827/simple-bank-system/internal/ratelimit"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// BankServer 以 gRPC 提供與 ApiHandler 相同的功能，共用 AccountService
+type BankServer struct {
+	bankv1.UnimplementedBankServiceServer
+	AccountService *service.AccountService
+	Validator      *request.Validator
+}
+
+// NewServer 建立已註冊 BankService 與攔截器的 gRPC server，limiter 為 nil 時不做 rate limit
+func NewServer(bank *BankServer, authn *auth.Authenticator, limiter *ratelimit.Limiter, enableReflection bool) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(LoggingUnaryInterceptor, AuthUnaryInterceptor(authn), RateLimitUnaryInterceptor(limiter)),
+		grpc.ChainStreamInterceptor(LoggingStreamInterceptor, AuthStreamInterceptor(authn)),
+	)
+	bankv1.RegisterBankServiceServer(srv, bank)
+	if enableReflection {
+		reflection.Register(srv)
+	}
+	return srv
+}
+
+// Serve 啟動 gRPC server，ctx 結束時等待進行中的呼叫完成 (最多 shutdownTimeout)
+func Serve(ctx context.Context, srv *grpc.Server, addr string, shutdownTimeout time.Duration) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("grpc server started", "addr", addr)
+		errCh <- srv.Serve(lis)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	slog.Info("shutting down grpc server")
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		slog.Warn("grpc graceful shutdown timed out, forcing stop")
+		srv.Stop()
+	}
+	return nil
+}
+
+func (s *BankServer) CreateAccount(ctx context.Context, in *bankv1.CreateAccountRequest) (*bankv1.CreateAccountResponse, error) {
+	req := request.CreateAccountRequest{Name: in.GetName(), Product: in.GetProduct(), CustomerIDs: in.GetCustomerIds()}
+	if in.GetBalance() != "" {
+		balance, err := parseAmount("balance", in.GetBalance())
+		if err != nil {
+			return nil, toStatus(ctx, err)
+		}
+		req.Balance = balance
+	}
+	if err := s.Validator.Validate(&req); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	acc, err := s.AccountService.CreateAccount(ctx, &req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &bankv1.CreateAccountResponse{Account: toAccount(acc)}, nil
+}
+
+func (s *BankServer) GetAccount(ctx context.Context, in *bankv1.GetAccountRequest) (*bankv1.GetAccountResponse, error) {
+	acc, err := s.findAccount(ctx, in.GetAccountNumber())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &bankv1.GetAccountResponse{Account: toAccount(acc)}, nil
+}
+
+func (s *BankServer) Deposit(ctx context.Context, in *bankv1.DepositRequest) (*bankv1.DepositResponse, error) {
+	refID, err := s.transact(ctx, in.GetAccountNumber(), in.GetAmount(), false)
+	if err != nil {
+		return nil, err
+	}
+	return &bankv1.DepositResponse{RefId: refID}, nil
+}
+
+func (s *BankServer) Withdraw(ctx context.Context, in *bankv1.WithdrawRequest) (*bankv1.WithdrawResponse, error) {
+	refID, err := s.transact(ctx, in.GetAccountNumber(), in.GetAmount(), true)
+	if err != nil {
+		return nil, err
+	}
+	return &bankv1.WithdrawResponse{RefId: refID}, nil
+}
+
+func (s *BankServer) Transfer(ctx context.Context, in *bankv1.TransferRequest) (*bankv1.TransferResponse, error) {
+	amount, err := parsePositiveAmount(in.GetAmount())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	req := request.TransferRequest{FromAccount: in.GetFromAccount(), ToAccount: in.GetToAccount(), PayeeID: in.GetPayeeId(), Amount: amount}
+	if err := s.Validator.Validate(&req); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	res, err := s.AccountService.Transfer(ctx, &req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &bankv1.TransferResponse{RefId: res.RefID, Status: res.Status, PendingTransferId: res.PendingTransferID}, nil
+}
+
+func (s *BankServer) ListTransactions(in *bankv1.ListTransactionsRequest, stream grpc.ServerStreamingServer[bankv1.ListTransactionsResponse]) error {
+	ctx := stream.Context()
+	acc, err := s.findAccount(ctx, in.GetAccountNumber())
+	if err != nil {
+		return toStatus(ctx, err)
+	}
+	transactions, err := s.AccountService.FindAccountTransactions(ctx, acc.ID, repository.TransactionFilter{})
+	if err != nil {
+		return toStatus(ctx, err)
+	}
+	for _, tx := range transactions {
+		if err := stream.Send(&bankv1.ListTransactionsResponse{Transaction: toTransaction(tx)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 驗證帳號號碼的檢查碼後查詢帳號
+func (s *BankServer) findAccount(ctx context.Context, number string) (*domain.Account, error) {
+	if err := s.Validator.ValidateAccountNumber("account_number", number); err != nil {
+		return nil, err
+	}
+	return s.AccountService.FindAccountByNumber(ctx, number)
+}
+
+// 存款與提款共用，金額一律為正數，提款時轉為負數交給 AccountService
+func (s *BankServer) transact(ctx context.Context, number, rawAmount string, withdraw bool) (string, error) {
+	amount, err := parsePositiveAmount(rawAmount)
+	if err != nil {
+		return "", toStatus(ctx, err)
+	}
+	if withdraw {
+		amount = amount.Neg()
+	}
+	req := request.TransactionRequest{Amount: amount}
+	if err := s.Validator.Validate(&req); err != nil {
+		return "", toStatus(ctx, err)
+	}
+	acc, err := s.findAccount(ctx, number)
+	if err != nil {
+		return "", toStatus(ctx, err)
+	}
+	refID, err := s.AccountService.CreateTransaction(ctx, acc.ID, &req)
+	if err != nil {
+		return "", toStatus(ctx, err)
+	}
+	return refID, nil
+}
+
+func parseAmount(field, v string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(v)
+	if err != nil {
+		return decimal.Zero, request.ValidationErrors{{Field: field, Message: "must be a decimal number"}}
+	}
+	return d, nil
+}
+
+func parsePositiveAmount(v string) (decimal.Decimal, error) {
+	d, err := parseAmount("amount", v)
+	if err != nil {
+		return d, err
+	}
+	if !d.IsPositive() {
+		return d, request.ValidationErrors{{Field: "amount", Message: "must be greater than zero"}}
+	}
+	return d, nil
+}
+
+func toAccount(acc *domain.Account) *bankv1.Account {
+	return &bankv1.Account{AccountNumber: acc.Number, Name: acc.Name, Balance: acc.Balance.StringFixed(2)}
+}
+
+func toTransaction(tx *domain.Transaction) *bankv1.Transaction {
+	return &bankv1.Transaction{
+		Id:           int64(tx.ID),
+		Name:         tx.Name,
+		Type:         bankv1.TransactionType(tx.Type),
+		Kind:         tx.Kind,
+		Amount:       tx.Amount.StringFixed(2),
+		RefId:        tx.RefID,
+		Description:  tx.Description,
+		RequestId:    tx.RequestID,
+		CreatedAt:    tx.CreatedAt,
+		BusinessDate: tx.BusinessDate,
+	}
+}
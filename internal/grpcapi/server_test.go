@@ -0,0 +1,233 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yoyo0827/simple-bank-system/internal/accountno"
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/grpcapi/bankv1"
+	"github.com/yoyo0827/simple-bank-system/internal/ratelimit"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// 以 bufconn 啟動 gRPC server，回傳 client 與 sqlmock
+func newTestClient(t *testing.T, keys ...string) (bankv1.BankServiceClient, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	authn, err := auth.NewAuthenticator(auth.Config{APIKeys: keys})
+	require.NoError(t, err)
+	svc := &service.AccountService{
+		DB:                    db,
+		AccountRepository:     &repository.AccountRepository{},
+		TransactionRepository: &repository.TransactionRepository{},
+		AuditRepository:       &repository.AuditRepository{},
+		OutboxRepository:      &repository.OutboxRepository{},
+	}
+	srv := NewServer(&BankServer{AccountService: svc, Validator: request.NewValidator(accountno.DefaultConfig())}, authn, nil, true)
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Stop()
+		db.Close()
+	})
+	return bankv1.NewBankServiceClient(conn), mock
+}
+
+// 單元測試查詢帳號與找不到帳號時回傳 NotFound
+func TestGetAccount(t *testing.T) {
+	client, mock := newTestClient(t)
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).WithArgs("100000000016").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("1", "Alice", "100", "standard", "active", "", "0", "100000000016"))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).WithArgs("100000000024").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}))
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "req-1")
+	resp, err := client.GetAccount(ctx, &bankv1.GetAccountRequest{AccountNumber: "100000000016"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", resp.GetAccount().GetName())
+	assert.Equal(t, "100000000016", resp.GetAccount().GetAccountNumber())
+	assert.Equal(t, "100.00", resp.GetAccount().GetBalance())
+	assert.Equal(t, []string{"req-1"}, header.Get("x-request-id"))
+
+	_, err = client.GetAccount(context.Background(), &bankv1.GetAccountRequest{AccountNumber: "100000000024"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// 檢查碼錯誤時不查詢資料庫
+	_, err = client.GetAccount(context.Background(), &bankv1.GetAccountRequest{AccountNumber: "100000000025"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試開戶時 product 與 customer_ids 與 REST 相同方式驗證
+func TestCreateAccount_InvalidArgument(t *testing.T) {
+	client, _ := newTestClient(t)
+	_, err := client.CreateAccount(context.Background(), &bankv1.CreateAccountRequest{
+		Product:     strings.Repeat("x", 51),
+		CustomerIds: []string{"not-a-uuid"},
+	})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	var fields []string
+	for _, v := range st.Details()[0].(*errdetails.BadRequest).GetFieldViolations() {
+		fields = append(fields, v.GetField())
+	}
+	assert.ElementsMatch(t, []string{"product", "customer_ids[0]"}, fields)
+}
+
+// 單元測試驗證錯誤回傳 InvalidArgument 與逐欄位的 BadRequest
+func TestTransfer_InvalidArgument(t *testing.T) {
+	client, _ := newTestClient(t)
+	_, err := client.Transfer(context.Background(), &bankv1.TransferRequest{FromAccount: "100000000016", ToAccount: "100000000016", Amount: "10.123"})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	br := st.Details()[0].(*errdetails.BadRequest)
+	var fields []string
+	for _, v := range br.GetFieldViolations() {
+		fields = append(fields, v.GetField())
+	}
+	assert.ElementsMatch(t, []string{"amount", "to_account"}, fields)
+
+	// 訊息與 REST 的驗證錯誤相同
+	_, err = client.Deposit(context.Background(), &bankv1.DepositRequest{AccountNumber: "100000000016", Amount: "0"})
+	st = status.Convert(err)
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, "must be greater than zero", st.Details()[0].(*errdetails.BadRequest).GetFieldViolations()[0].GetDescription())
+}
+
+// 單元測試餘額不足回傳 FailedPrecondition 與 ErrorInfo
+func TestWithdraw_InsufficientFunds(t *testing.T) {
+	client, mock := newTestClient(t)
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).
+			AddRow("1", "Alice", "10", "standard", "active", "", "0", "100000000016")
+	}
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).WithArgs("100000000016").WillReturnRows(rows())
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1`).WithArgs("1").WillReturnRows(rows())
+	mock.ExpectRollback()
+
+	_, err := client.Withdraw(context.Background(), &bankv1.WithdrawRequest{AccountNumber: "100000000016", Amount: "50"})
+	st := status.Convert(err)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, "INSUFFICIENT_FUNDS", st.Details()[0].(*errdetails.ErrorInfo).GetReason())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試未預期的錯誤回傳 Internal，訊息不含資料庫錯誤內容
+func TestInternalErrorHidden(t *testing.T) {
+	client, mock := newTestClient(t)
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).WithArgs("100000000016").
+		WillReturnError(errors.New(`pq: relation "accounts" does not exist`))
+
+	_, err := client.GetAccount(context.Background(), &bankv1.GetAccountRequest{AccountNumber: "100000000016"})
+	st := status.Convert(err)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "internal error", st.Message())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 ListTransactions 逐筆串流
+func TestListTransactions(t *testing.T) {
+	client, mock := newTestClient(t)
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).WithArgs("100000000016").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("1", "Alice", "100", "standard", "active", "", "0", "100000000016"))
+	mock.ExpectQuery(`FROM transactions`).WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "type", "kind", "amount", "ref_id", "description", "request_id", "business_date", "created_at"}).
+			AddRow(1, "Alice", 2, "deposit", "100", "ref-1", "", "", "2025-01-01", "2025-01-01 00:00:00").
+			AddRow(2, "Alice", 1, "withdrawal", "30", "ref-2", "", "req-2", "2025-01-02", "2025-01-02 00:00:00"))
+
+	stream, err := client.ListTransactions(context.Background(), &bankv1.ListTransactionsRequest{AccountNumber: "100000000016"})
+	require.NoError(t, err)
+	var got []*bankv1.Transaction
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, resp.GetTransaction())
+	}
+	require.Len(t, got, 2)
+	assert.Equal(t, bankv1.TransactionType_TRANSACTION_TYPE_DEPOSIT, got[0].GetType())
+	assert.Equal(t, bankv1.TransactionType_TRANSACTION_TYPE_WITHDRAWAL, got[1].GetType())
+	assert.Equal(t, "30.00", got[1].GetAmount())
+}
+
+// 單元測試設定 API key 後 unary 與串流呼叫皆需認證
+func TestAuthInterceptor(t *testing.T) {
+	client, mock := newTestClient(t, "secret-key:alice:user")
+
+	_, err := client.GetAccount(context.Background(), &bankv1.GetAccountRequest{AccountNumber: "100000000016"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "wrong")
+	_, err = client.GetAccount(ctx, &bankv1.GetAccountRequest{AccountNumber: "100000000016"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).WithArgs("100000000016").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("1", "Alice", "100", "standard", "active", "", "0", "100000000016"))
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret-key")
+	_, err = client.GetAccount(ctx, &bankv1.GetAccountRequest{AccountNumber: "100000000016"})
+	assert.NoError(t, err)
+
+	stream, err := client.ListTransactions(context.Background(), &bankv1.ListTransactionsRequest{AccountNumber: "100000000016"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+// 單元測試 rate limit 攔截器：與 HTTP 共用規則，依使用者與轉出帳號限制，查詢不受限制
+func TestRateLimitInterceptor(t *testing.T) {
+	l := ratelimit.New(ratelimit.Config{Enabled: true, Rules: []ratelimit.Rule{
+		{Route: "POST /accounts/transfer", Key: ratelimit.KeyAccount, Requests: 1, Per: time.Minute},
+		{Route: "POST /accounts/{id}/transactions", Key: ratelimit.KeyClient, Requests: 1, Per: time.Minute},
+	}}, ratelimit.NewMemoryStore())
+	intercept := RateLimitUnaryInterceptor(l)
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{ID: "alice", Role: auth.RoleUser})
+	call := func(method string, req any) error {
+		_, err := intercept(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, any) (any, error) { return nil, nil })
+		return err
+	}
+
+	transfer := &bankv1.TransferRequest{FromAccount: "100000000016", ToAccount: "100000000024", Amount: "10"}
+	assert.NoError(t, call(bankv1.BankService_Transfer_FullMethodName, transfer))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(call(bankv1.BankService_Transfer_FullMethodName, transfer)))
+	assert.NoError(t, call(bankv1.BankService_Transfer_FullMethodName, &bankv1.TransferRequest{FromAccount: "100000000024", Amount: "10"}))
+
+	// 存款與提款共用同一路由的限制
+	assert.NoError(t, call(bankv1.BankService_Deposit_FullMethodName, &bankv1.DepositRequest{AccountNumber: "100000000016", Amount: "10"}))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(call(bankv1.BankService_Withdraw_FullMethodName, &bankv1.WithdrawRequest{AccountNumber: "100000000016", Amount: "10"})))
+
+	for range 3 {
+		assert.NoError(t, call(bankv1.BankService_GetAccount_FullMethodName, &bankv1.GetAccountRequest{AccountNumber: "100000000016"}))
+	}
+}
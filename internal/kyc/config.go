@@ -0,0 +1,24 @@
+package kyc
+
+import "errors"
+
+// Config 客戶身分驗證 (KYC) 設定
+type Config struct {
+	// 啟用後開戶需指定已驗證的客戶，轉出帳號的所有持有人皆需已驗證
+	Enabled bool `yaml:"enabled" toml:"enabled" env:"KYC_ENABLED"`
+	// 身分證號以 HMAC-SHA256 雜湊後儲存，更換金鑰後既有客戶無法以身分證號比對
+	HashKey string `yaml:"hash_key" toml:"hash_key" env:"KYC_HASH_KEY"`
+}
+
+// DefaultConfig 預設不啟用
+func DefaultConfig() Config {
+	return Config{}
+}
+
+// Validate 啟用時需設定雜湊金鑰
+func (c Config) Validate() error {
+	if c.Enabled && len(c.HashKey) < 16 {
+		return errors.New("kyc.hash_key must be at least 16 characters when kyc is enabled")
+	}
+	return nil
+}
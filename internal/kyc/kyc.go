@@ -0,0 +1,26 @@
+package kyc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+// NormalizeNationalID 去除空白與分隔符號並轉為大寫，讓同一個證號的不同寫法得到相同雜湊
+func NormalizeNationalID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, id)
+}
+
+// HashNationalID 以 HMAC-SHA256 雜湊正規化後的身分證號 (hex)，資料庫不保存原始證號
+func HashNationalID(key, id string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(NormalizeNationalID(id)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,23 @@
+package kyc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 單元測試 身分證號的不同寫法得到相同雜湊，不同金鑰得到不同雜湊
+func TestHashNationalID(t *testing.T) {
+	key := "0123456789abcdef"
+	h := HashNationalID(key, "a123-456 789")
+	assert.Len(t, h, 64)
+	assert.Equal(t, h, HashNationalID(key, "A123456789"))
+	assert.NotEqual(t, h, HashNationalID(key, "A123456780"))
+	assert.NotEqual(t, h, HashNationalID("fedcba9876543210", "A123456789"))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig().Validate())
+	assert.ErrorContains(t, Config{Enabled: true, HashKey: "short"}.Validate(), "kyc.hash_key")
+	assert.NoError(t, Config{Enabled: true, HashKey: "0123456789abcdef"}.Validate())
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 單元測試 敏感欄位遮蔽與 request_id 自動帶入
+func TestLogger_RedactAndRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, Config{Level: "info", Format: "json"})
+
+	ctx := WithRequestID(t.Context(), "req-123")
+	logger.InfoContext(ctx, "login", "user", "alice", "password", "secret123")
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "req-123", entry["request_id"])
+	assert.Equal(t, "alice", entry["user"])
+	assert.Equal(t, "[REDACTED]", entry["password"])
+}
+
+// 單元測試 Middleware 沿用上游 X-Request-ID，非法值則重新產生
+func TestMiddleware_RequestID(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(New(&buf, Config{Level: "info", Format: "json"}))
+	defer slog.SetDefault(prev)
+
+	var seen string
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/accounts/1", nil)
+	req.Header.Set(RequestIDHeader, "upstream-id")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, "upstream-id", seen)
+	assert.Equal(t, "upstream-id", rec.Header().Get(RequestIDHeader))
+	assert.Contains(t, buf.String(), `"request_id":"upstream-id"`)
+
+	req = httptest.NewRequest(http.MethodGet, "/accounts/1", nil)
+	req.Header.Set(RequestIDHeader, "bad id\nwith newline")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.NotEqual(t, "bad id\nwith newline", seen)
+	assert.Len(t, seen, 36)
+}
@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yoyo0827/simple-bank-system/internal/route"
+)
+
+// RequestIDHeader 用於傳遞 request ID 的 header
+const RequestIDHeader = "X-Request-ID"
+
+type ctxKey struct{}
+
+// 只接受長度合理且不含特殊字元的外部 request ID，避免 log injection
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// WithRequestID 將 request ID 存入 context
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// RequestID 從 context 取出 request ID，不存在時回傳空字串
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// EnsureRequestID 沿用合法的上游 request ID，否則產生新的
+func EnsureRequestID(id string) string {
+	if validRequestID.MatchString(id) {
+		return id
+	}
+	return uuid.New().String()
+}
+
+// Middleware 沿用上游傳入的 X-Request-ID (或產生新的)，寫回 response header，
+// 並在請求結束後記錄 access log
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := EnsureRequestID(r.Header.Get(RequestIDHeader))
+		w.Header().Set(RequestIDHeader, id)
+		ctx := WithRequestID(r.Context(), id)
+		r = route.Track(r.WithContext(ctx))
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		slog.InfoContext(ctx, "http request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"route", route.Pattern(r),
+			"status", rec.status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"remote_addr", r.RemoteAddr,
+		)
+	})
+}
+
+// statusRecorder 記錄 handler 回傳的 HTTP 狀態碼
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
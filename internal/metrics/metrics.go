@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry 服務專用的 Prometheus registry
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTP 流量
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests by route and status.",
+	}, []string{"method", "route", "status"})
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// 資金異動
+	Transactions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "bank_transactions_total",
+		Help: "Number of committed money movements by type (deposit, withdrawal, transfer).",
+	}, []string{"type"})
+	Volume = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "bank_transaction_volume_total",
+		Help: "Amount of money moved by type and currency.",
+	}, []string{"type", "currency"})
+	InsufficientFunds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "bank_insufficient_funds_total",
+		Help: "Number of operations rejected for insufficient funds by type.",
+	}, []string{"type"})
+
+	// 流量限制
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "bank_rate_limited_total",
+		Help: "Number of requests rejected by rate limiting by route and key (client, account).",
+	}, []string{"route", "key"})
+)
+
+// 交易類型標籤
+const (
+	TypeDeposit    = "deposit"
+	TypeWithdrawal = "withdrawal"
+	TypeTransfer   = "transfer"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests, HTTPDuration,
+		Transactions, Volume, InsufficientFunds,
+		RateLimited,
+	)
+}
+
+// RegisterDB 註冊資料庫連線池狀態 (sql.DB.Stats)
+func RegisterDB(db *sql.DB, name string) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler /metrics 端點
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
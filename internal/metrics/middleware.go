@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/route"
+)
+
+// statusRecorder 記錄 handler 回傳的 HTTP 狀態碼
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Middleware 統計每個路由的請求數與延遲
+// 路由使用 ServeMux 比對到的 pattern (例如 "GET /accounts/{id}")，避免 path 參數造成標籤爆量
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		r = route.Track(r)
+		next.ServeHTTP(rec, r)
+
+		pattern := route.Pattern(r)
+		if pattern == "" {
+			pattern = "unmatched"
+		}
+		HTTPRequests.WithLabelValues(r.Method, pattern, strconv.Itoa(rec.status)).Inc()
+		HTTPDuration.WithLabelValues(r.Method, pattern).Observe(time.Since(start).Seconds())
+	})
+}
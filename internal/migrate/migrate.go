@@ -0,0 +1,237 @@
+// Package migrate 依版本套用與回復 SQL migration，並記錄於 schema_migrations
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// 多個實例同時執行 migration 時，以 advisory lock 排隊
+const lockKey = 72407240
+
+// migration 檔名：<version>_<name>.up.sql / <version>_<name>.down.sql
+var fileName = regexp.MustCompile(`^(\d+)_([A-Za-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration 單一版本的 schema 變更
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status 單一 migration 的套用狀態
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"` // 未套用時為空
+}
+
+// Load 讀取 dir 下的 migration 檔案，依版本排序
+// 每個版本都必須有 up 檔，down 檔可省略 (該版本無法回復)
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		m := fileName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", e.Name())
+		}
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration version %d has conflicting names %q and %q", version, mig.Name, m[2])
+		}
+		body, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Latest 回傳最新的 migration 版本，沒有 migration 時為 0
+func Latest(migrations []Migration) int64 {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// Migrator 對資料庫套用 migration，每個版本在各自的 transaction 中執行
+type Migrator struct {
+	DB         *sql.DB
+	Migrations []Migration
+}
+
+// Up 依序套用所有尚未套用的 migration，回傳本次套用的版本
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	for {
+		var next *Migration
+		err := m.locked(ctx, func(tx *sql.Tx, done map[int64]appliedMigration) error {
+			for i := range m.Migrations {
+				if _, ok := done[m.Migrations[i].Version]; !ok {
+					next = &m.Migrations[i]
+					break
+				}
+			}
+			if next == nil {
+				return nil
+			}
+			if _, err := tx.ExecContext(ctx, next.Up); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", next.Version, next.Name, err)
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, next.Version, next.Name)
+			return err
+		})
+		if err != nil {
+			return applied, err
+		}
+		if next == nil {
+			return applied, nil
+		}
+		applied = append(applied, *next)
+	}
+}
+
+// Down 由最新版本起回復 steps 個已套用的 migration，回傳本次回復的版本
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	known := make(map[int64]*Migration, len(m.Migrations))
+	for i := range m.Migrations {
+		known[m.Migrations[i].Version] = &m.Migrations[i]
+	}
+	var reverted []Migration
+	for range steps {
+		var last *Migration
+		err := m.locked(ctx, func(tx *sql.Tx, done map[int64]appliedMigration) error {
+			var version int64
+			for v := range done {
+				version = max(version, v)
+			}
+			if version == 0 {
+				return nil
+			}
+			last = known[version]
+			if last == nil {
+				return fmt.Errorf("applied migration %d is unknown to this binary", version)
+			}
+			if last.Down == "" {
+				return fmt.Errorf("migration %d_%s cannot be reverted", last.Version, last.Name)
+			}
+			if _, err := tx.ExecContext(ctx, last.Down); err != nil {
+				return fmt.Errorf("revert migration %d_%s: %w", last.Version, last.Name, err)
+			}
+			_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, last.Version)
+			return err
+		})
+		if err != nil {
+			return reverted, err
+		}
+		if last == nil {
+			break
+		}
+		reverted = append(reverted, *last)
+	}
+	return reverted, nil
+}
+
+// Status 回傳每個 migration 的套用狀態 (含資料庫中有、但此版本程式沒有的 migration)
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.locked(ctx, func(tx *sql.Tx, done map[int64]appliedMigration) error {
+		for _, mig := range m.Migrations {
+			st := Status{Version: mig.Version, Name: mig.Name}
+			if a, ok := done[mig.Version]; ok {
+				st.AppliedAt = &a.at
+				delete(done, mig.Version)
+			}
+			statuses = append(statuses, st)
+		}
+		for version, a := range done {
+			statuses = append(statuses, Status{Version: version, Name: a.name, AppliedAt: &a.at})
+		}
+		sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+		return nil
+	})
+	return statuses, err
+}
+
+// 已套用的 migration
+type appliedMigration struct {
+	name string
+	at   time.Time
+}
+
+// locked 在持有 advisory lock 的 transaction 中執行 fn，並帶入已套用的版本
+func (m *Migrator) locked(ctx context.Context, fn func(tx *sql.Tx, done map[int64]appliedMigration) error) error {
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, lockKey); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`); err != nil {
+		return err
+	}
+	rows, err := tx.QueryContext(ctx, `SELECT version, name, applied_at FROM schema_migrations`)
+	if err != nil {
+		return err
+	}
+	done := map[int64]appliedMigration{}
+	for rows.Next() {
+		var version int64
+		var a appliedMigration
+		if err := rows.Scan(&version, &a.name, &a.at); err != nil {
+			rows.Close()
+			return err
+		}
+		done[version] = a
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if err := fn(tx, done); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
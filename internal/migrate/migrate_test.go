@@ -0,0 +1,113 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yoyo0827/simple-bank-system/db"
+)
+
+// 單元測試 Load：依版本排序，up / down 配對
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0002_status.up.sql":   {Data: []byte("ALTER 2")},
+		"m/0001_init.up.sql":     {Data: []byte("CREATE 1")},
+		"m/0001_init.down.sql":   {Data: []byte("DROP 1")},
+		"m/0010_payees.up.sql":   {Data: []byte("CREATE 10")},
+		"m/0010_payees.down.sql": {Data: []byte("DROP 10")},
+	}
+	migrations, err := Load(fsys, "m")
+	require.NoError(t, err)
+	assert.Equal(t, []Migration{
+		{Version: 1, Name: "init", Up: "CREATE 1", Down: "DROP 1"},
+		{Version: 2, Name: "status", Up: "ALTER 2"},
+		{Version: 10, Name: "payees", Up: "CREATE 10", Down: "DROP 10"},
+	}, migrations)
+	assert.Equal(t, int64(10), Latest(migrations))
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	_, err := Load(fstest.MapFS{"m/init.sql": {}}, "m")
+	assert.ErrorContains(t, err, "invalid migration file name")
+
+	_, err = Load(fstest.MapFS{"m/0001_init.down.sql": {Data: []byte("DROP")}}, "m")
+	assert.ErrorContains(t, err, "has no up file")
+
+	_, err = Load(fstest.MapFS{
+		"m/0001_a.up.sql": {Data: []byte("A")},
+		"m/0001_b.up.sql": {Data: []byte("B")},
+	}, "m")
+	assert.ErrorContains(t, err, "conflicting names")
+}
+
+// 內嵌的 migration 必須可載入且皆可回復
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := Load(db.Migrations, "migrations")
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, m := range migrations {
+		assert.Equal(t, int64(i+1), m.Version, "versions must be contiguous")
+		assert.NotEmpty(t, m.Down, "migration %d_%s has no down file", m.Version, m.Name)
+	}
+}
+
+func expectLocked(mock sqlmock.Sqlmock, applied ...int64) {
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version", "name", "applied_at"})
+	for _, v := range applied {
+		rows.AddRow(v, "m", time.Now())
+	}
+	mock.ExpectQuery(`SELECT version, name, applied_at FROM schema_migrations`).WillReturnRows(rows)
+}
+
+// 單元測試 Up：只套用尚未套用的版本，每個版本各自 commit
+func TestUp(t *testing.T) {
+	sqlDB, mock, _ := sqlmock.New()
+	defer sqlDB.Close()
+	m := &Migrator{DB: sqlDB, Migrations: []Migration{
+		{Version: 1, Name: "init", Up: "CREATE 1"},
+		{Version: 2, Name: "status", Up: "ALTER 2"},
+		{Version: 3, Name: "payees", Up: "CREATE 3"},
+	}}
+
+	expectLocked(mock, 1)
+	mock.ExpectExec(`ALTER 2`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO schema_migrations`).WithArgs(int64(2), "status").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectLocked(mock, 1, 2)
+	mock.ExpectExec(`CREATE 3`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO schema_migrations`).WithArgs(int64(3), "payees").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectLocked(mock, 1, 2, 3)
+	mock.ExpectCommit()
+
+	applied, err := m.Up(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, applied, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 Down：沒有 down 檔的版本不可回復
+func TestDown_Irreversible(t *testing.T) {
+	sqlDB, mock, _ := sqlmock.New()
+	defer sqlDB.Close()
+	m := &Migrator{DB: sqlDB, Migrations: []Migration{
+		{Version: 1, Name: "init", Up: "CREATE 1", Down: "DROP 1"},
+		{Version: 2, Name: "status", Up: "ALTER 2"},
+	}}
+
+	expectLocked(mock, 1, 2)
+	mock.ExpectRollback()
+
+	reverted, err := m.Down(context.Background(), 1)
+	assert.ErrorContains(t, err, "cannot be reverted")
+	assert.Empty(t, reverted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
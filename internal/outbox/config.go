@@ -0,0 +1,86 @@
+package outbox
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Config outbox relay 設定
+type Config struct {
+	Enabled         bool          `yaml:"enabled" toml:"enabled" env:"OUTBOX_ENABLED"`
+	Sinks           []string      `yaml:"sinks" toml:"sinks" env:"OUTBOX_SINKS"` // stdout, file, webhook
+	FilePath        string        `yaml:"file_path" toml:"file_path" env:"OUTBOX_FILE_PATH"`
+	WebhookURL      string        `yaml:"webhook_url" toml:"webhook_url" env:"OUTBOX_WEBHOOK_URL"`
+	WebhookTimeout  time.Duration `yaml:"webhook_timeout" toml:"webhook_timeout" env:"OUTBOX_WEBHOOK_TIMEOUT"`
+	PollInterval    time.Duration `yaml:"poll_interval" toml:"poll_interval" env:"OUTBOX_POLL_INTERVAL"`
+	BatchSize       int           `yaml:"batch_size" toml:"batch_size" env:"OUTBOX_BATCH_SIZE"`
+	MaxAttempts     int           `yaml:"max_attempts" toml:"max_attempts" env:"OUTBOX_MAX_ATTEMPTS"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff" toml:"retry_backoff" env:"OUTBOX_RETRY_BACKOFF"`
+	RetryMaxBackoff time.Duration `yaml:"retry_max_backoff" toml:"retry_max_backoff" env:"OUTBOX_RETRY_MAX_BACKOFF"`
+	Lease           time.Duration `yaml:"lease" toml:"lease" env:"OUTBOX_LEASE"`                            // 認領後未記錄結果 (例如 relay 中途停止) 時，經過多久可再次投遞
+	Retention       time.Duration `yaml:"retention" toml:"retention" env:"OUTBOX_RETENTION"`                // 事件與投遞紀錄的保留期間，0 代表不刪除
+	PruneInterval   time.Duration `yaml:"prune_interval" toml:"prune_interval" env:"OUTBOX_PRUNE_INTERVAL"` // 刪除過期事件的間隔
+}
+
+// DefaultConfig outbox 預設設定
+func DefaultConfig() Config {
+	return Config{
+		Sinks:           []string{"stdout"},
+		FilePath:        "events.ndjson",
+		WebhookTimeout:  10 * time.Second,
+		PollInterval:    time.Second,
+		BatchSize:       100,
+		MaxAttempts:     10,
+		RetryBackoff:    time.Second,
+		RetryMaxBackoff: 5 * time.Minute,
+		Lease:           10 * time.Minute,
+		Retention:       30 * 24 * time.Hour,
+		PruneInterval:   time.Hour,
+	}
+}
+
+// Validate 檢查 sink 與重試設定
+// 輪詢與重試設定在停用時仍會檢查，webhook fan-out 也會使用
+func (c Config) Validate() error {
+	var errs []error
+	if c.Enabled {
+		errs = c.validateSinks()
+	}
+	if c.PollInterval <= 0 || c.BatchSize <= 0 || c.MaxAttempts <= 0 {
+		errs = append(errs, errors.New("outbox poll_interval, batch_size and max_attempts must be positive"))
+	}
+	if c.RetryBackoff <= 0 || c.RetryMaxBackoff < c.RetryBackoff {
+		errs = append(errs, errors.New("outbox retry backoff must be positive and max >= initial"))
+	}
+	if c.Lease < c.WebhookTimeout {
+		errs = append(errs, errors.New("outbox lease must be at least the webhook timeout"))
+	}
+	if c.Retention < 0 || (c.Retention > 0 && c.PruneInterval <= 0) {
+		errs = append(errs, errors.New("outbox retention cannot be negative and prune_interval must be positive when retention is set"))
+	}
+	return errors.Join(errs...)
+}
+
+func (c Config) validateSinks() []error {
+	var errs []error
+	if len(c.Sinks) == 0 {
+		errs = append(errs, errors.New("outbox.sinks is required when outbox is enabled"))
+	}
+	for _, s := range c.Sinks {
+		switch s {
+		case SinkStdout:
+		case SinkFile:
+			if c.FilePath == "" {
+				errs = append(errs, errors.New("outbox.file_path is required for the file sink"))
+			}
+		case SinkWebhook:
+			if c.WebhookURL == "" {
+				errs = append(errs, errors.New("outbox.webhook_url is required for the webhook sink"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("outbox.sinks: unknown sink %q", s))
+		}
+	}
+	return errs
+}
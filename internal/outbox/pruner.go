@@ -0,0 +1,31 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+	"github.com/yoyo0827/simple-bank-system/internal/worker"
+)
+
+// Pruner 定期刪除超過保留期限的事件，投遞紀錄與 webhook 投遞紀錄一併刪除
+type Pruner struct {
+	DB               *sql.DB
+	OutboxRepository *repository.OutboxRepository
+	Config           Config
+}
+
+// Run 持續處理直到 ctx 結束
+func (p *Pruner) Run(ctx context.Context) {
+	worker.Poll(ctx, "outbox pruner", p.Config.PruneInterval, p.Config.BatchSize, p.PruneBatch, "retention", p.Config.Retention)
+}
+
+// PruneBatch 刪除一批過期的事件，回傳刪除的筆數
+func (p *Pruner) PruneBatch(ctx context.Context) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "OutboxPruner.PruneBatch")
+	defer func() { tracing.End(span, err) }()
+
+	return p.OutboxRepository.Prune(ctx, p.DB, time.Now().Add(-p.Config.Retention), p.Config.BatchSize)
+}
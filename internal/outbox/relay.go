@@ -0,0 +1,69 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+	"github.com/yoyo0827/simple-bank-system/internal/worker"
+)
+
+// Relay 定期從 outbox 取出尚未送達的事件並投遞到單一 sink
+// 每個 sink 各自記錄投遞狀態，某個 sink 失敗不影響其他 sink
+type Relay struct {
+	DB               *sql.DB
+	OutboxRepository *repository.OutboxRepository
+	Sink             Sink
+	Config           Config
+}
+
+// Register 登記 sink，之後寫入的事件才會為它建立待投遞紀錄，需在 Run 之前呼叫
+func (r *Relay) Register(ctx context.Context) error {
+	return r.OutboxRepository.RegisterSink(ctx, r.DB, r.Sink.Name())
+}
+
+// Run 持續投遞直到 ctx 結束
+func (r *Relay) Run(ctx context.Context) {
+	worker.Poll(ctx, "outbox relay", r.Config.PollInterval, r.Config.BatchSize, r.ProcessBatch, "sink", r.Sink.Name())
+}
+
+// ProcessBatch 投遞一批事件，回傳處理的筆數
+// 認領後即提交，投遞時不持有交易；每筆結果各自寫入，記錄失敗時租約到期後會再次投遞
+func (r *Relay) ProcessBatch(ctx context.Context) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "OutboxRelay.ProcessBatch")
+	defer func() { tracing.End(span, err) }()
+
+	pending, err := r.OutboxRepository.ClaimPending(ctx, r.DB, r.Sink.Name(), r.Config.BatchSize, r.Config.Lease)
+	if err != nil {
+		return 0, err
+	}
+	for _, p := range pending {
+		if err := r.deliver(ctx, p); err != nil {
+			return 0, err
+		}
+	}
+	return len(pending), nil
+}
+
+// 投遞一筆事件並記錄結果
+func (r *Relay) deliver(ctx context.Context, p repository.PendingEvent) error {
+	status, next, lastErr := repository.DeliveryDelivered, time.Now(), ""
+	if derr := r.Sink.Deliver(ctx, p.Event); derr != nil {
+		attempts := p.Attempts + 1
+		lastErr = derr.Error()
+		if attempts >= r.Config.MaxAttempts {
+			status = repository.DeliveryFailed
+			slog.ErrorContext(ctx, "outbox event delivery failed permanently",
+				"sink", r.Sink.Name(), "event_id", p.Event.ID, "attempts", attempts, "error", derr)
+		} else {
+			status = repository.DeliveryRetrying
+			next = time.Now().Add(worker.Backoff(r.Config.RetryBackoff, r.Config.RetryMaxBackoff, attempts))
+			slog.WarnContext(ctx, "outbox event delivery failed, will retry",
+				"sink", r.Sink.Name(), "event_id", p.Event.ID, "attempts", attempts, "retry_at", next, "error", derr)
+		}
+	}
+	return r.OutboxRepository.SaveDelivery(ctx, r.DB, p.Event.ID, r.Sink.Name(), status, next, lastErr)
+}
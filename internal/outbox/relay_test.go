@@ -0,0 +1,103 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+)
+
+// 測試用 sink，依事件 ID 決定是否失敗
+type fakeSink struct {
+	fail      map[int64]bool
+	delivered []int64
+}
+
+func (s *fakeSink) Name() string { return "fake" }
+
+func (s *fakeSink) Deliver(_ context.Context, ev *domain.Event) error {
+	if s.fail[ev.ID] {
+		return errors.New("sink unavailable")
+	}
+	s.delivered = append(s.delivered, ev.ID)
+	return nil
+}
+
+func newRelay(t *testing.T, sink Sink) (*Relay, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	cfg := DefaultConfig()
+	cfg.MaxAttempts = 3
+	return &Relay{DB: db, OutboxRepository: &repository.OutboxRepository{}, Sink: sink, Config: cfg}, mock
+}
+
+func pendingRows() *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{"id", "event_type", "aggregate_id", "request_id", "payload", "occurred_at", "attempts"}).
+		AddRow(1, domain.EventDeposited, "acc1", "", []byte(`{}`), now, 0).
+		AddRow(2, domain.EventWithdrawn, "acc1", "", []byte(`{}`), now, 0).
+		AddRow(3, domain.EventWithdrawn, "acc2", "", []byte(`{}`), now, 2)
+}
+
+// 單元測試 ProcessBatch：成功標記 delivered、失敗排程重試、超過次數標記 failed
+func TestRelay_ProcessBatch(t *testing.T) {
+	sink := &fakeSink{fail: map[int64]bool{2: true, 3: true}}
+	relay, mock := newRelay(t, sink)
+
+	// 認領時延後下次投遞時間 (租約)，不開啟交易
+	mock.ExpectQuery(`UPDATE outbox_deliveries SET next_attempt_at`).
+		WithArgs("fake", relay.Config.BatchSize, relay.Config.Lease.Milliseconds()).
+		WillReturnRows(pendingRows())
+	mock.ExpectExec(`INSERT INTO outbox_deliveries`).
+		WithArgs(int64(1), "fake", repository.DeliveryDelivered, sqlmock.AnyArg(), "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO outbox_deliveries`).
+		WithArgs(int64(2), "fake", repository.DeliveryRetrying, sqlmock.AnyArg(), "sink unavailable").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO outbox_deliveries`).
+		WithArgs(int64(3), "fake", repository.DeliveryFailed, sqlmock.AnyArg(), "sink unavailable").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	n, err := relay.ProcessBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []int64{1}, sink.delivered)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試記錄投遞結果失敗時停止本批，其餘已認領的事件待租約到期後再投遞
+func TestRelay_ProcessBatch_SaveFails(t *testing.T) {
+	sink := &fakeSink{}
+	relay, mock := newRelay(t, sink)
+
+	mock.ExpectQuery(`UPDATE outbox_deliveries SET next_attempt_at`).WillReturnRows(pendingRows())
+	mock.ExpectExec(`INSERT INTO outbox_deliveries`).WillReturnError(errors.New("db down"))
+
+	_, err := relay.ProcessBatch(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, []int64{1}, sink.delivered)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 PruneBatch：刪除保留期限之前的事件，每批最多 BatchSize 筆，仍待投遞或重試的事件不刪除
+func TestPruner_PruneBatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	p := &Pruner{DB: db, OutboxRepository: &repository.OutboxRepository{}, Config: DefaultConfig()}
+
+	mock.ExpectExec(`DELETE FROM outbox_events(.|\n)+NOT EXISTS(.|\n)+FROM outbox_deliveries d(.|\n)+d.status IN \('pending', 'retrying'\)`).
+		WithArgs(sqlmock.AnyArg(), p.Config.BatchSize).
+		WillReturnResult(sqlmock.NewResult(0, 42))
+
+	n, err := p.PruneBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 42, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
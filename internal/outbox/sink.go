@@ -0,0 +1,122 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+// 內建的 sink 名稱
+const (
+	SinkStdout  = "stdout"
+	SinkFile    = "file"
+	SinkWebhook = "webhook"
+)
+
+// Sink 事件的投遞目標，Deliver 回傳 nil 代表已送達
+// 投遞為 at-least-once，下游需以事件 ID 去除重複
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, ev *domain.Event) error
+}
+
+// NewSinks 依設定建立 sink，回傳的 close 函式用於關閉檔案
+func NewSinks(cfg Config) ([]Sink, func() error, error) {
+	var sinks []Sink
+	var closers []io.Closer
+	closeAll := func() error {
+		var err error
+		for _, c := range closers {
+			if cerr := c.Close(); cerr != nil {
+				err = cerr
+			}
+		}
+		return err
+	}
+	for _, name := range cfg.Sinks {
+		switch name {
+		case SinkStdout:
+			sinks = append(sinks, NewWriterSink(SinkStdout, os.Stdout))
+		case SinkFile:
+			f, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+			if err != nil {
+				closeAll()
+				return nil, nil, fmt.Errorf("open outbox file sink: %w", err)
+			}
+			closers = append(closers, f)
+			sinks = append(sinks, NewWriterSink(SinkFile, f))
+		case SinkWebhook:
+			sinks = append(sinks, &WebhookSink{
+				URL:    cfg.WebhookURL,
+				Client: &http.Client{Timeout: cfg.WebhookTimeout},
+			})
+		default:
+			closeAll()
+			return nil, nil, fmt.Errorf("unknown outbox sink %q", name)
+		}
+	}
+	return sinks, closeAll, nil
+}
+
+// WriterSink 將事件以 NDJSON (一行一筆 JSON) 寫入 io.Writer，用於 stdout 與檔案
+type WriterSink struct {
+	name string
+	mu   sync.Mutex
+	w    io.Writer
+}
+
+func NewWriterSink(name string, w io.Writer) *WriterSink {
+	return &WriterSink{name: name, w: w}
+}
+
+func (s *WriterSink) Name() string { return s.name }
+
+func (s *WriterSink) Deliver(_ context.Context, ev *domain.Event) error {
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+// WebhookSink 以 HTTP POST 將事件送到固定的 URL，2xx 視為成功
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func (s *WebhookSink) Name() string { return SinkWebhook }
+
+func (s *WebhookSink) Deliver(ctx context.Context, ev *domain.Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", fmt.Sprint(ev.ID))
+	req.Header.Set("X-Event-Type", ev.Type)
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
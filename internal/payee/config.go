@@ -0,0 +1,57 @@
+package payee
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Config 收款人 (約定轉入帳號) 設定
+// 新增收款人或提高上限後的冷靜期內，單筆與近 24 小時累計上限不超過 CoolingOffLimit (僅能由設定檔指定 payees.cooling_off_limit)
+// 未約定的帳號一律適用 CoolingOffLimit
+type Config struct {
+	CoolingOff      time.Duration   `yaml:"cooling_off" toml:"cooling_off" env:"PAYEES_COOLING_OFF"` // 0 代表不設冷靜期
+	CoolingOffLimit decimal.Decimal `yaml:"cooling_off_limit" toml:"cooling_off_limit"`
+	MaxPerAccount   int             `yaml:"max_per_account" toml:"max_per_account" env:"PAYEES_MAX_PER_ACCOUNT"` // 每個帳號的收款人數量上限
+}
+
+// DefaultConfig 預設冷靜期 24 小時，期間上限 10000
+func DefaultConfig() Config {
+	return Config{
+		CoolingOff:      24 * time.Hour,
+		CoolingOffLimit: decimal.NewFromInt(10000),
+		MaxPerAccount:   100,
+	}
+}
+
+// Validate 檢查冷靜期與上限
+func (c Config) Validate() error {
+	var errs []error
+	if c.CoolingOff < 0 {
+		errs = append(errs, errors.New("payees.cooling_off cannot be negative"))
+	}
+	if c.CoolingOff > 0 && !c.CoolingOffLimit.IsPositive() {
+		errs = append(errs, errors.New("payees.cooling_off_limit must be positive when cooling_off is set"))
+	}
+	if c.MaxPerAccount <= 0 {
+		errs = append(errs, errors.New("payees.max_per_account must be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+// CoolingOffUntil 自 now 起算的冷靜期結束時間
+func (c Config) CoolingOffUntil(now time.Time) time.Time {
+	return now.Add(c.CoolingOff)
+}
+
+// Limit 實際適用的上限，limit 為 0 代表不限；冷靜期內取與 CoolingOffLimit 較小者
+func (c Config) Limit(limit decimal.Decimal, coolingOff bool) decimal.Decimal {
+	if !coolingOff || c.CoolingOff <= 0 {
+		return limit
+	}
+	if limit.IsZero() || c.CoolingOffLimit.LessThan(limit) {
+		return c.CoolingOffLimit
+	}
+	return limit
+}
@@ -0,0 +1,31 @@
+package payee
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// 單元測試 冷靜期內上限不超過 CoolingOffLimit，0 代表不限
+func TestLimit(t *testing.T) {
+	cfg := Config{CoolingOff: time.Hour, CoolingOffLimit: decimal.NewFromInt(1000)}
+	assert.True(t, cfg.Limit(decimal.Zero, false).IsZero())
+	assert.Equal(t, "5000", cfg.Limit(decimal.NewFromInt(5000), false).String())
+	assert.Equal(t, "1000", cfg.Limit(decimal.Zero, true).String())
+	assert.Equal(t, "1000", cfg.Limit(decimal.NewFromInt(5000), true).String())
+	assert.Equal(t, "500", cfg.Limit(decimal.NewFromInt(500), true).String())
+
+	cfg.CoolingOff = 0
+	assert.True(t, cfg.Limit(decimal.Zero, true).IsZero())
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig().Validate())
+
+	err := Config{CoolingOff: time.Hour}.Validate()
+	assert.ErrorContains(t, err, "payees.cooling_off_limit")
+	assert.ErrorContains(t, err, "payees.max_per_account")
+	assert.ErrorContains(t, Config{CoolingOff: -time.Second, MaxPerAccount: 1}.Validate(), "payees.cooling_off")
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 令牌桶儲存位置
+const (
+	BackendMemory   = "memory"   // 行程內，每個實例各自計算
+	BackendPostgres = "postgres" // 多個實例共用
+)
+
+// 限制對象
+const (
+	KeyClient  = "client"  // 依 API key 使用者 (匿名時依來源 IP)
+	KeyAccount = "account" // 依操作的帳號 (路徑的帳號號碼 {id} 或轉帳的 from_account)
+)
+
+// Config rate limit 設定，Rules 僅能於設定檔設定
+type Config struct {
+	Enabled bool   `yaml:"enabled" toml:"enabled" env:"RATELIMIT_ENABLED"`
+	Backend string `yaml:"backend" toml:"backend" env:"RATELIMIT_BACKEND"`
+	Rules   []Rule `yaml:"rules" toml:"rules"`
+}
+
+// Rule 單一路由的限制：每 Per 期間最多 Requests 次，可瞬間使用 Burst 次
+type Rule struct {
+	Route    string        `yaml:"route" toml:"route"` // 路由 pattern，例如 "POST /accounts/transfer"
+	Key      string        `yaml:"key" toml:"key"`     // client / account
+	Requests int           `yaml:"requests" toml:"requests"`
+	Per      time.Duration `yaml:"per" toml:"per"`
+	Burst    int           `yaml:"burst" toml:"burst"` // 0 代表等於 Requests
+}
+
+// Limit 將規則轉為令牌桶參數
+func (r Rule) Limit() Limit {
+	burst := r.Burst
+	if burst == 0 {
+		burst = r.Requests
+	}
+	return Limit{Burst: burst, Interval: r.Per / time.Duration(r.Requests)}
+}
+
+// Idle 所有規則中令牌桶從空到補滿的最長時間，超過此時間未取用的令牌桶與不存在時相同
+func (c Config) Idle() time.Duration {
+	var idle time.Duration
+	for _, r := range c.Rules {
+		l := r.Limit()
+		idle = max(idle, time.Duration(l.Burst)*l.Interval)
+	}
+	return idle
+}
+
+// DefaultConfig 預設限制資金異動與開戶
+func DefaultConfig() Config {
+	return Config{
+		Enabled: true,
+		Backend: BackendMemory,
+		Rules: []Rule{
+			{Route: "POST /accounts/transfer", Key: KeyClient, Requests: 60, Per: time.Minute},
+			{Route: "POST /accounts/transfer", Key: KeyAccount, Requests: 20, Per: time.Minute},
+			{Route: "POST /accounts/{id}/transactions", Key: KeyClient, Requests: 120, Per: time.Minute},
+			{Route: "POST /accounts/{id}/transactions", Key: KeyAccount, Requests: 30, Per: time.Minute},
+			{Route: "POST /accounts", Key: KeyClient, Requests: 20, Per: time.Minute},
+		},
+	}
+}
+
+// Validate 檢查儲存位置與規則，同一路由與限制對象只能有一條規則
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	var errs []error
+	if c.Backend != BackendMemory && c.Backend != BackendPostgres {
+		errs = append(errs, fmt.Errorf("ratelimit.backend must be %q or %q", BackendMemory, BackendPostgres))
+	}
+	seen := map[[2]string]bool{}
+	for i, r := range c.Rules {
+		if _, path, ok := strings.Cut(r.Route, " "); !ok || !strings.HasPrefix(path, "/") {
+			errs = append(errs, fmt.Errorf("ratelimit rule #%d: route must be \"METHOD /path\"", i+1))
+		}
+		if r.Key != KeyClient && r.Key != KeyAccount {
+			errs = append(errs, fmt.Errorf("ratelimit rule #%d: key must be %q or %q", i+1, KeyClient, KeyAccount))
+		}
+		if r.Requests <= 0 || r.Per <= 0 || r.Burst < 0 {
+			errs = append(errs, fmt.Errorf("ratelimit rule #%d: requests and per must be positive", i+1))
+		}
+		key := [2]string{r.Route, r.Key}
+		if seen[key] {
+			errs = append(errs, fmt.Errorf("ratelimit rule #%d: duplicate rule for %q by %s", i+1, r.Route, r.Key))
+		}
+		seen[key] = true
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,157 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/metrics"
+	"github.com/yoyo0827/simple-bank-system/internal/response"
+)
+
+// Limiter 依路由套用令牌桶限制
+type Limiter struct {
+	store Store
+	rules map[string][]Rule
+}
+
+// New 建立 Limiter，未啟用時回傳 nil (Wrap 不做任何限制)
+func New(cfg Config, store Store) *Limiter {
+	if !cfg.Enabled {
+		return nil
+	}
+	l := &Limiter{store: store, rules: map[string][]Rule{}}
+	for _, r := range cfg.Rules {
+		l.rules[r.Route] = append(l.rules[r.Route], r)
+	}
+	return l
+}
+
+// Wrap 為路由套用設定的限制，pattern 需與註冊到 ServeMux 的 pattern 相同
+// 超過限制時回 429 並帶 Retry-After；每個回應都帶 RateLimit-* header (取最嚴格的規則)
+func (l *Limiter) Wrap(pattern string, next http.HandlerFunc) http.HandlerFunc {
+	if l == nil || len(l.rules[pattern]) == 0 {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		tightest, policy, ok := l.Check(r.Context(), pattern, func() string {
+			if id := r.PathValue("id"); id != "" {
+				return id
+			}
+			return fromAccount(r)
+		})
+		if !ok {
+			next(w, r)
+			return
+		}
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(tightest.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+		h.Set("RateLimit-Reset", strconv.Itoa(seconds(tightest.Reset)))
+		h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Requests, seconds(policy.Per)))
+		if !tightest.Allowed {
+			h.Set("Retry-After", strconv.Itoa(RetryAfterSeconds(tightest)))
+			response.WriteError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// Check 依 pattern 的規則各取用一個 token，回傳最嚴格的結果與其規則，沒有套用任何規則時 ok 為 false
+// HTTP 與 gRPC 共用同一組令牌桶；account 回傳操作的帳號號碼，只在有依帳號限制的規則時呼叫
+func (l *Limiter) Check(ctx context.Context, pattern string, account func() string) (tightest Result, policy Rule, ok bool) {
+	if l == nil {
+		return Result{}, Rule{}, false
+	}
+	for _, rule := range l.rules[pattern] {
+		var id string
+		switch rule.Key {
+		case KeyClient:
+			id = client(ctx)
+		case KeyAccount:
+			id = account()
+		}
+		if id == "" {
+			continue
+		}
+		res, err := l.store.Take(ctx, pattern+"|"+rule.Key+"|"+id, rule.Limit())
+		if err != nil {
+			// 無法取得限制狀態時放行，避免儲存異常造成服務中斷
+			slog.WarnContext(ctx, "rate limit store error", "route", pattern, "error", err)
+			continue
+		}
+		if !res.Allowed {
+			metrics.RateLimited.WithLabelValues(pattern, rule.Key).Inc()
+		}
+		if !ok || stricter(res, tightest) {
+			tightest, policy, ok = res, rule, true
+		}
+	}
+	return tightest, policy, ok
+}
+
+// RetryAfterSeconds 被拒絕時建議的重試秒數，至少 1 秒
+func RetryAfterSeconds(res Result) int {
+	return max(seconds(res.RetryAfter), 1)
+}
+
+// 被拒絕的結果優先，其次是剩餘次數較少者
+func stricter(a, b Result) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	return a.Remaining < b.Remaining
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// client 依 API key 使用者 (匿名時依來源 IP) 取得限制對象，無法判斷時回傳空字串 (不套用該規則)
+func client(ctx context.Context) string {
+	if p, ok := auth.FromContext(ctx); ok && p != auth.Anonymous {
+		return "user:" + p.ID
+	}
+	if ip := auth.ClientIP(ctx); ip != "" {
+		return "ip:" + ip
+	}
+	return ""
+}
+
+// fromAccount 讀取轉帳 body 的 from_account，讀取後還原 body 供 handler 解析
+func fromAccount(r *http.Request) string {
+	if r.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{err}))
+	if err != nil {
+		return ""
+	}
+	var req struct {
+		FromAccount string `json:"from_account"`
+	}
+	if json.Unmarshal(body, &req) != nil {
+		return ""
+	}
+	return req.FromAccount
+}
+
+// errReader 讀完已讀取的 body 後回傳原本的讀取錯誤 (例如超過 body 上限)
+type errReader struct{ err error }
+
+func (e errReader) Read([]byte) (int, error) {
+	if e.err == nil {
+		return 0, io.EOF
+	}
+	return 0, e.err
+}
@@ -0,0 +1,161 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+)
+
+// 單元測試 令牌桶：用完後需等待補充，補充不超過容量
+func TestTake(t *testing.T) {
+	l := Limit{Burst: 2, Interval: time.Second}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tokens, res := take(2, start, start, l)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+	tokens, res = take(tokens, start, start, l)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 2*time.Second, res.Reset)
+
+	tokens, res = take(tokens, start, start.Add(500*time.Millisecond), l)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+	_, res = take(tokens, start, start.Add(time.Hour), l)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+}
+
+func transfer(t *testing.T, h http.HandlerFunc, user, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/accounts/transfer", strings.NewReader(body))
+	req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{ID: user, Role: auth.RoleUser}))
+	rec := httptest.NewRecorder()
+	h(rec, req)
+	return rec
+}
+
+// 單元測試 依使用者與轉出帳號限制，超過時回 429 與 Retry-After
+func TestWrap(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.Now = func() time.Time { return now }
+	l := New(Config{Enabled: true, Rules: []Rule{
+		{Route: "POST /accounts/transfer", Key: KeyClient, Requests: 3, Per: time.Minute},
+		{Route: "POST /accounts/transfer", Key: KeyAccount, Requests: 1, Per: time.Minute},
+	}}, store)
+
+	var bodies []string
+	h := l.Wrap("POST /accounts/transfer", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+	})
+
+	rec := transfer(t, h, "alice", `{"from_account":"1","to_account":"2","amount":10}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{`{"from_account":"1","to_account":"2","amount":10}`}, bodies) // body 仍可由 handler 讀取
+	assert.Equal(t, "1", rec.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", rec.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "1;w=60", rec.Header().Get("RateLimit-Policy"))
+
+	// 同一帳號超過限制
+	rec = transfer(t, h, "alice", `{"from_account":"1","to_account":"2","amount":10}`)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+	assert.Len(t, bodies, 1)
+
+	// 其他帳號仍可轉出，直到使用者的限制用完
+	rec = transfer(t, h, "alice", `{"from_account":"3","to_account":"2","amount":10}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	rec = transfer(t, h, "alice", `{"from_account":"4","to_account":"2","amount":10}`)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "20", rec.Header().Get("Retry-After"))
+
+	// 其他使用者不受影響，時間經過後恢復
+	assert.Equal(t, http.StatusOK, transfer(t, h, "bob", `{"from_account":"5","to_account":"2","amount":10}`).Code)
+	now = now.Add(time.Minute)
+	assert.Equal(t, http.StatusOK, transfer(t, h, "alice", `{"from_account":"1","to_account":"2","amount":10}`).Code)
+}
+
+// 單元測試 未啟用或路由沒有規則時不做任何限制
+func TestWrap_NoRules(t *testing.T) {
+	var l *Limiter
+	called := false
+	h := l.Wrap("POST /accounts/transfer", func(http.ResponseWriter, *http.Request) { called = true })
+	h(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/accounts/transfer", nil))
+	assert.True(t, called)
+
+	assert.Nil(t, New(Config{Enabled: false}, NewMemoryStore()))
+}
+
+// 單元測試 PostgresStore 在交易內鎖定令牌桶並寫回剩餘 token
+func TestPostgresStore(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	store := &PostgresStore{DB: db, RateLimitRepository: &repository.RateLimitRepository{}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO rate_limit_buckets .* ON CONFLICT \(key\) DO UPDATE .* RETURNING tokens, updated_at`).
+		WithArgs("k", float64(5), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"tokens", "updated_at"}).AddRow(0.5, time.Now().Add(time.Hour)))
+	mock.ExpectExec(`UPDATE rate_limit_buckets SET tokens = \$2, updated_at = \$3 WHERE key = \$1`).
+		WithArgs("k", 0.5, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	res, err := store.Take(context.Background(), "k", Limit{Burst: 5, Interval: time.Second})
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 規則設定檢查
+func TestValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig().Validate())
+
+	cfg := Config{Enabled: true, Backend: "redis", Rules: []Rule{
+		{Route: "/accounts", Key: KeyClient, Requests: 1, Per: time.Second},
+		{Route: "POST /accounts", Key: "ip", Requests: 0, Per: time.Second},
+		{Route: "POST /accounts", Key: KeyClient, Requests: 1, Per: time.Second},
+		{Route: "POST /accounts", Key: KeyClient, Requests: 1, Per: time.Second},
+	}}
+	err := cfg.Validate()
+	assert.ErrorContains(t, err, "ratelimit.backend")
+	assert.ErrorContains(t, err, "rule #1: route")
+	assert.ErrorContains(t, err, "rule #2: key")
+	assert.ErrorContains(t, err, "rule #2: requests")
+	assert.ErrorContains(t, err, "rule #4: duplicate")
+}
+
+// 單元測試 Pruner 刪除閒置超過最長補滿時間的令牌桶
+func TestPruner_PruneBatch(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	cfg := Config{Enabled: true, Rules: []Rule{
+		{Route: "POST /accounts", Key: KeyClient, Requests: 20, Per: time.Minute},
+		{Route: "POST /accounts/transfer", Key: KeyClient, Requests: 1, Per: time.Minute, Burst: 5},
+	}}
+	assert.Equal(t, 5*time.Minute, cfg.Idle())
+	p := &Pruner{DB: db, RateLimitRepository: &repository.RateLimitRepository{}, Config: cfg}
+	mock.ExpectExec(`DELETE FROM rate_limit_buckets WHERE key IN \(.+ WHERE updated_at < \$1 LIMIT \$2`).
+		WithArgs(sqlmock.AnyArg(), pruneBatchSize).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	n, err := p.PruneBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
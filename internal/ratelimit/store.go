@@ -0,0 +1,161 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+	"github.com/yoyo0827/simple-bank-system/internal/worker"
+)
+
+// Limit 令牌桶參數：容量 Burst，每 Interval 補充一個 token
+type Limit struct {
+	Burst    int
+	Interval time.Duration
+}
+
+// Result 一次取用 token 的結果
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // 令牌桶補滿所需時間
+	RetryAfter time.Duration // 被拒絕時，下一個 token 可用前的等待時間
+}
+
+// Store 保存令牌桶狀態，多個實例共用限制時使用 PostgresStore
+type Store interface {
+	Take(ctx context.Context, key string, l Limit) (Result, error)
+}
+
+// take 依經過時間補充 token 後取用一個，回傳剩餘 token 與結果
+func take(tokens float64, updated, now time.Time, l Limit) (float64, Result) {
+	if elapsed := now.Sub(updated); elapsed > 0 {
+		tokens = math.Min(float64(l.Burst), tokens+float64(elapsed)/float64(l.Interval))
+	}
+	res := Result{Limit: l.Burst}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration((1 - tokens) * float64(l.Interval))
+	}
+	res.Remaining = int(tokens)
+	res.Reset = time.Duration((float64(l.Burst) - tokens) * float64(l.Interval))
+	return tokens, res
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // 補滿的時間，之後可直接刪除
+}
+
+// MemoryStore 行程內的令牌桶，每個服務實例各自計算
+type MemoryStore struct {
+	Now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore 建立行程內的令牌桶
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{Now: time.Now, buckets: map[string]*bucket{}}
+}
+
+// Take 取用一個 token
+func (s *MemoryStore) Take(_ context.Context, key string, l Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.Now()
+	s.sweep(now)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.Burst), updated: now}
+		s.buckets[key] = b
+	}
+	var res Result
+	b.tokens, res = take(b.tokens, b.updated, now, l)
+	if now.After(b.updated) {
+		b.updated = now
+	}
+	b.full = b.updated.Add(res.Reset)
+	return res, nil
+}
+
+// 每分鐘清除已補滿的令牌桶 (與不存在時相同)，避免 key 無限增加
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// 清除已補滿令牌桶的間隔
+const sweepInterval = time.Minute
+
+// PostgresStore 以資料表保存令牌桶，多個服務實例共用限制
+// 每次取用在交易內鎖定該列，避免同時請求重複取用；已補滿的令牌桶由 Pruner 清除
+type PostgresStore struct {
+	DB                  *sql.DB
+	RateLimitRepository *repository.RateLimitRepository
+}
+
+// Take 取用一個 token
+func (s *PostgresStore) Take(ctx context.Context, key string, l Limit) (Result, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return Result{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	tokens, updated, err := s.RateLimitRepository.LockBucket(ctx, tx, key, float64(l.Burst), now)
+	if err != nil {
+		return Result{}, err
+	}
+	// 其他實例時鐘較快時，以較新的時間為準
+	if updated.After(now) {
+		now = updated
+	}
+	tokens, res := take(tokens, updated, now, l)
+	if err := s.RateLimitRepository.SaveBucket(ctx, tx, key, tokens, now); err != nil {
+		return Result{}, err
+	}
+	return res, tx.Commit()
+}
+
+// 每批刪除的令牌桶數
+const pruneBatchSize = 1000
+
+// Pruner 每分鐘刪除 PostgresStore 中已補滿的令牌桶，避免 key 無限增加
+type Pruner struct {
+	DB                  *sql.DB
+	RateLimitRepository *repository.RateLimitRepository
+	Config              Config
+}
+
+// Run 持續處理直到 ctx 結束
+func (p *Pruner) Run(ctx context.Context) {
+	worker.Poll(ctx, "rate limit pruner", sweepInterval, pruneBatchSize, p.PruneBatch)
+}
+
+// PruneBatch 刪除一批閒置超過 Config.Idle 的令牌桶，回傳刪除的筆數
+func (p *Pruner) PruneBatch(ctx context.Context) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "RateLimitPruner.PruneBatch")
+	defer func() { tracing.End(span, err) }()
+
+	return p.RateLimitRepository.DeleteIdle(ctx, p.DB, time.Now().Add(-p.Config.Idle()), pruneBatchSize)
+}
@@ -1,32 +1,143 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/shopspring/decimal"
 	"github.com/yoyo0827/simple-bank-system/internal/domain"
 )
 
 type AccountRepository struct{}
 
-// 建立帳號
-func (r *AccountRepository) FindById(db DBTX, id string) (*domain.Account, error) {
-	query := `SELECT id, name, balance FROM accounts WHERE id = $1`
-	acc := &domain.Account{}
-	err := db.QueryRow(query, id).Scan(&acc.ID, &acc.Name, &acc.Balance)
+const accountColumns = `id, name, balance, product, status, COALESCE(status_reason, ''), held_balance, account_number`
+
+// 以內部 ID 查詢帳號
+func (r *AccountRepository) FindById(ctx context.Context, db DBTX, id string) (_ *domain.Account, err error) {
+	query := `SELECT ` + accountColumns + ` FROM accounts WHERE id = $1`
+	ctx, span := startSpan(ctx, "AccountRepository.FindById", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return scanAccount(db.QueryRowContext(ctx, query, id))
+}
+
+// 在 transaction 內以內部 ID 查詢並鎖定帳號，異動餘額前需先鎖定以取得最新的餘額與保留款項
+func (r *AccountRepository) FindByIdForUpdate(ctx context.Context, db DBTX, id string) (_ *domain.Account, err error) {
+	query := `SELECT ` + accountColumns + ` FROM accounts WHERE id = $1 FOR UPDATE`
+	ctx, span := startSpan(ctx, "AccountRepository.FindByIdForUpdate", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return scanAccount(db.QueryRowContext(ctx, query, id))
+}
+
+// 以對外帳號號碼查詢帳號
+func (r *AccountRepository) FindByNumber(ctx context.Context, db DBTX, number string) (_ *domain.Account, err error) {
+	query := `SELECT ` + accountColumns + ` FROM accounts WHERE account_number = $1`
+	ctx, span := startSpan(ctx, "AccountRepository.FindByNumber", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return scanAccount(db.QueryRowContext(ctx, query, number))
+}
+
+// 以匯入時記錄的舊系統帳號識別查詢帳號
+func (r *AccountRepository) FindByExternalID(ctx context.Context, db DBTX, externalID string) (_ *domain.Account, err error) {
+	query := `SELECT ` + accountColumns + ` FROM accounts WHERE external_id = $1`
+	ctx, span := startSpan(ctx, "AccountRepository.FindByExternalID", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return scanAccount(db.QueryRowContext(ctx, query, externalID))
+}
+
+// 記錄帳號的舊系統帳號識別 (不可重複)
+func (r *AccountRepository) SetExternalID(ctx context.Context, db DBTX, id, externalID string) (err error) {
+	query := `UPDATE accounts SET external_id = $2 WHERE id = $1`
+	ctx, span := startSpan(ctx, "AccountRepository.SetExternalID", query)
+	var rows int64
+	defer func() { endSpan(span, rows, err) }()
+
+	result, err := db.ExecContext(ctx, query, id, externalID)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return acc, nil
+	rows, _ = result.RowsAffected()
+	return nil
 }
 
-// 建立帳號
-func (r *AccountRepository) CreateUser(db DBTX, account *domain.Account) error {
-	query := `INSERT INTO accounts (name, balance) VALUES ($1, $2) RETURNING id`
-	return db.QueryRow(query, account.Name, account.Balance).Scan(&account.ID)
+// 以對外帳號號碼查詢並鎖定帳號直到交易結束
+func (r *AccountRepository) FindByNumberForUpdate(ctx context.Context, db DBTX, number string) (_ *domain.Account, err error) {
+	query := `SELECT ` + accountColumns + ` FROM accounts WHERE account_number = $1 FOR UPDATE`
+	ctx, span := startSpan(ctx, "AccountRepository.FindByNumberForUpdate", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return scanAccount(db.QueryRowContext(ctx, query, number))
+}
+
+// 建立帳號，帳號號碼重複時不新增並回傳 sql.ErrNoRows，由呼叫端重新產生號碼
+func (r *AccountRepository) CreateUser(ctx context.Context, db DBTX, account *domain.Account) (err error) {
+	query := `INSERT INTO accounts (name, balance, product, account_number) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (account_number) DO NOTHING RETURNING id`
+	ctx, span := startSpan(ctx, "AccountRepository.CreateUser", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query, account.Name, account.Balance, account.Product, account.Number).Scan(&account.ID)
 }
 
 // 更新帳號餘額
-func (r *AccountRepository) UpdateBalance(db DBTX, id string, balance decimal.Decimal) error {
+func (r *AccountRepository) UpdateBalance(ctx context.Context, db DBTX, id string, balance decimal.Decimal) (err error) {
 	query := `UPDATE accounts SET balance = $1 WHERE id = $2`
-	_, err := db.Exec(query, balance, id)
-	return err
+	ctx, span := startSpan(ctx, "AccountRepository.UpdateBalance", query)
+	var rows int64
+	defer func() { endSpan(span, rows, err) }()
+
+	result, err := db.ExecContext(ctx, query, balance, id)
+	if err != nil {
+		return err
+	}
+	rows, _ = result.RowsAffected()
+	return nil
+}
+
+// 以增量更新帳號的保留款項，delta 為負數時釋放
+func (r *AccountRepository) AddHeldBalance(ctx context.Context, db DBTX, id string, delta decimal.Decimal) (err error) {
+	query := `UPDATE accounts SET held_balance = held_balance + $1 WHERE id = $2`
+	ctx, span := startSpan(ctx, "AccountRepository.AddHeldBalance", query)
+	var rows int64
+	defer func() { endSpan(span, rows, err) }()
+
+	result, err := db.ExecContext(ctx, query, delta, id)
+	if err != nil {
+		return err
+	}
+	rows, _ = result.RowsAffected()
+	return nil
+}
+
+// 更新帳號狀態 (凍結 / 解除凍結)
+func (r *AccountRepository) UpdateStatus(ctx context.Context, db DBTX, id, status, reason string) (err error) {
+	query := `UPDATE accounts SET status = $1, status_reason = NULLIF($2, ''), updated_at = NOW() WHERE id = $3`
+	ctx, span := startSpan(ctx, "AccountRepository.UpdateStatus", query)
+	var rows int64
+	defer func() { endSpan(span, rows, err) }()
+
+	result, err := db.ExecContext(ctx, query, status, reason, id)
+	if err != nil {
+		return err
+	}
+	rows, _ = result.RowsAffected()
+	return nil
+}
+
+func scanAccount(row scanner) (*domain.Account, error) {
+	acc := &domain.Account{}
+	if err := row.Scan(&acc.ID, &acc.Name, &acc.Balance, &acc.Product, &acc.Status, &acc.StatusReason, &acc.HeldBalance, &acc.Number); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// 單筆查詢成功時影響 1 筆，否則 0 筆
+func rowCount(err error) int64 {
+	if err != nil {
+		return 0
+	}
+	return 1
 }
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+// GenesisHash hash chain 第一筆事件的 prev_hash
+var GenesisHash = strings.Repeat("0", 64)
+
+// 序列化所有稽核寫入，確保 hash chain 不會分岔。
+// 此鎖持有到交易結束，AccountService 於取得後才寫入 outbox 事件，
+// 因此 outbox_events.id 的提交順序與 id 大小一致，SSE 以 Last-Event-ID 補齊事件時依賴這點
+// (否則較小 id 的事件可能在較大 id 送出後才提交而被略過)。移除或分片此鎖前須先改以明確的事件序號補齊。
+const auditLockKey = 7_340_033
+
+type AuditRepository struct{}
+
+// AuditFilter 查詢稽核紀錄的條件，空值代表不限制
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	Actor      string
+	Action     string
+	From       time.Time
+	To         time.Time
+	AfterID    int64
+	Limit      int
+}
+
+// 取得最後一筆事件的 hash，並鎖定稽核寫入直到交易結束
+func (r *AuditRepository) LockLastHash(ctx context.Context, db DBTX) (_ string, err error) {
+	query := `SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1`
+	ctx, span := startSpan(ctx, "AuditRepository.LockLastHash", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	if _, err = db.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, auditLockKey); err != nil {
+		return "", err
+	}
+	var hash string
+	err = db.QueryRowContext(ctx, query).Scan(&hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return GenesisHash, nil
+	}
+	return hash, err
+}
+
+// 寫入稽核事件
+func (r *AuditRepository) Insert(ctx context.Context, db DBTX, ev *domain.AuditEvent) (err error) {
+	query := `INSERT INTO audit_events (action, entity_type, entity_id, actor, source_ip, request_id, before_state, after_state, prev_hash, hash, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9, $10, $11) RETURNING id`
+	ctx, span := startSpan(ctx, "AuditRepository.Insert", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query,
+		ev.Action, ev.EntityType, ev.EntityID, ev.Actor, ev.SourceIP, ev.RequestID,
+		nullJSON(ev.Before), nullJSON(ev.After), ev.PrevHash, ev.Hash, ev.CreatedAt,
+	).Scan(&ev.ID)
+}
+
+// 依條件查詢稽核事件 (依 id 遞增)
+func (r *AuditRepository) Find(ctx context.Context, db DBTX, f AuditFilter) (_ []*domain.AuditEvent, err error) {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(cond string, v any) {
+		args = append(args, v)
+		conds = append(conds, strings.Replace(cond, "?", "$"+strconv.Itoa(len(args)), 1))
+	}
+	if f.EntityType != "" {
+		add("entity_type = ?", f.EntityType)
+	}
+	if f.EntityID != "" {
+		add("entity_id = ?", f.EntityID)
+	}
+	if f.Actor != "" {
+		add("actor = ?", f.Actor)
+	}
+	if f.Action != "" {
+		add("action = ?", f.Action)
+	}
+	if !f.From.IsZero() {
+		add("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		add("created_at < ?", f.To)
+	}
+	if f.AfterID > 0 {
+		add("id > ?", f.AfterID)
+	}
+	query := `SELECT id, action, entity_type, entity_id, actor, COALESCE(source_ip, ''), COALESCE(request_id, ''),
+		before_state, after_state, prev_hash, hash, created_at FROM audit_events`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY id"
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+
+	ctx, span := startSpan(ctx, "AuditRepository.Find", query)
+	var events []*domain.AuditEvent
+	defer func() { endSpan(span, int64(len(events)), err) }()
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		ev := &domain.AuditEvent{}
+		var before, after []byte
+		if err := rows.Scan(&ev.ID, &ev.Action, &ev.EntityType, &ev.EntityID, &ev.Actor, &ev.SourceIP, &ev.RequestID,
+			&before, &after, &ev.PrevHash, &ev.Hash, &ev.CreatedAt); err != nil {
+			return nil, err
+		}
+		ev.Before, ev.After = before, after
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
+
+// JSON 為空時寫入 NULL
+func nullJSON(b []byte) any {
+	if len(b) == 0 {
+		return nil
+	}
+	return string(b)
+}
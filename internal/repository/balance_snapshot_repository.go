@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+type BalanceSnapshotRepository struct{}
+
+// 查詢帳號在 at 之前 (含) 最近一筆快照，沒有快照時回傳 sql.ErrNoRows
+func (r *BalanceSnapshotRepository) FindLatest(ctx context.Context, db DBTX, accountID string, at time.Time) (_ *domain.BalanceSnapshot, err error) {
+	query := `SELECT account_id, as_of, balance FROM balance_snapshots WHERE account_id = $1 AND as_of <= $2 ORDER BY as_of DESC LIMIT 1`
+	ctx, span := startSpan(ctx, "BalanceSnapshotRepository.FindLatest", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	snap := &domain.BalanceSnapshot{}
+	if err := db.QueryRowContext(ctx, query, accountID, at).Scan(&snap.AccountID, &snap.AsOf, &snap.Balance); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// 為 id 大於 afterID 的下一批帳號建立 asOf 的快照 (前一筆快照加上其後至 asOf 的交易)，期間沒有交易的帳號略過
+// 回傳本批最後一個帳號 ID (沒有帳號時為 0) 與建立的筆數，同一時間點重複建立時略過
+func (r *BalanceSnapshotRepository) SnapshotBatch(ctx context.Context, db DBTX, afterID int64, limit int, asOf time.Time) (_ int64, _ int, err error) {
+	query := `WITH batch AS (
+			SELECT id FROM accounts WHERE id > $1 ORDER BY id LIMIT $2
+		), inserted AS (
+			INSERT INTO balance_snapshots (account_id, as_of, balance)
+			SELECT a.id, $3, COALESCE(s.balance, 0) + c.net
+			FROM batch a
+			LEFT JOIN LATERAL (
+				SELECT as_of, balance FROM balance_snapshots WHERE account_id = a.id AND as_of <= $3 ORDER BY as_of DESC LIMIT 1
+			) s ON TRUE
+			CROSS JOIN LATERAL (
+				SELECT COUNT(*) AS n, COALESCE(SUM(CASE WHEN type = 2 THEN amount ELSE -amount END), 0) AS net FROM transactions
+				WHERE account_id = a.id AND created_at > COALESCE(s.as_of, '-infinity') AND created_at <= $3
+			) c
+			WHERE c.n > 0
+			ON CONFLICT (account_id, as_of) DO NOTHING
+			RETURNING 1
+		)
+		SELECT (SELECT MAX(id) FROM batch), (SELECT COUNT(*) FROM inserted)`
+	ctx, span := startSpan(ctx, "BalanceSnapshotRepository.SnapshotBatch", query)
+	var n int
+	defer func() { endSpan(span, int64(n), err) }()
+
+	var lastID sql.NullInt64
+	if err := db.QueryRowContext(ctx, query, afterID, limit, asOf).Scan(&lastID, &n); err != nil {
+		return 0, 0, err
+	}
+	return lastID.Int64, n, nil
+}
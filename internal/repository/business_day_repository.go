@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+type BusinessDayRepository struct{}
+
+// 取得結帳的排他鎖 (transaction 結束時釋放)，等待進行中寫入交易的 transaction 提交
+// 寫入交易時由 transactions trigger 取得同一把鎖的共享鎖
+func (r *BusinessDayRepository) LockClose(ctx context.Context, db DBTX) (err error) {
+	query := `SELECT pg_advisory_xact_lock(hashtext('business_day_close'))`
+	ctx, span := startSpan(ctx, "BusinessDayRepository.LockClose", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	_, err = db.ExecContext(ctx, query)
+	return err
+}
+
+// 已結帳的最後一個營業日，尚未結帳時為空字串
+func (r *BusinessDayRepository) ClosedThrough(ctx context.Context, db DBTX) (_ string, err error) {
+	query := `SELECT COALESCE(to_char(MAX(business_date), 'YYYY-MM-DD'), '') FROM business_day_closes`
+	ctx, span := startSpan(ctx, "BusinessDayRepository.ClosedThrough", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	var date string
+	err = db.QueryRowContext(ctx, query).Scan(&date)
+	return date, err
+}
+
+// 寫入結帳紀錄
+func (r *BusinessDayRepository) InsertClose(ctx context.Context, db DBTX, date, actor string) (_ time.Time, err error) {
+	query := `INSERT INTO business_day_closes (business_date, closed_by) VALUES ($1, $2) RETURNING closed_at`
+	ctx, span := startSpan(ctx, "BusinessDayRepository.InsertClose", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	var closedAt time.Time
+	err = db.QueryRowContext(ctx, query, date, actor).Scan(&closedAt)
+	return closedAt, err
+}
+
+// 為營業日結束前已開立的帳號寫入日終快照，回傳寫入的筆數
+// 期初餘額沿用前一營業日的快照，沒有快照時 (首次結帳或新帳號) 加總之前的交易
+func (r *BusinessDayRepository) InsertEODBalances(ctx context.Context, db DBTX, date, timeZone string) (_ int64, err error) {
+	query := `INSERT INTO eod_balances (business_date, account_id, opening_balance, total_debits, total_credits, closing_balance, transaction_count)
+		SELECT $1::date, a.id, o.balance, d.debits, d.credits, o.balance + d.credits - d.debits, d.count
+		FROM accounts a
+		LEFT JOIN eod_balances p ON p.account_id = a.id AND p.business_date = $1::date - 1
+		CROSS JOIN LATERAL (
+			SELECT COALESCE(p.closing_balance, (
+				SELECT COALESCE(SUM(CASE WHEN type = 2 THEN amount ELSE -amount END), 0)
+				FROM transactions WHERE account_id = a.id AND business_date < $1::date
+			)) AS balance
+		) o
+		CROSS JOIN LATERAL (
+			SELECT COUNT(*) AS count,
+				COALESCE(SUM(amount) FILTER (WHERE type = 1), 0) AS debits,
+				COALESCE(SUM(amount) FILTER (WHERE type = 2), 0) AS credits
+			FROM transactions WHERE account_id = a.id AND business_date = $1::date
+		) d
+		WHERE (a.created_at::timestamptz AT TIME ZONE $2)::date <= $1::date`
+	ctx, span := startSpan(ctx, "BusinessDayRepository.InsertEODBalances", query)
+	var rows int64
+	defer func() { endSpan(span, rows, err) }()
+
+	res, err := db.ExecContext(ctx, query, date, timeZone)
+	if err != nil {
+		return 0, err
+	}
+	rows, err = res.RowsAffected()
+	return rows, err
+}
+
+// 查詢營業日的日終快照
+func (r *BusinessDayRepository) FindEODBalances(ctx context.Context, db DBTX, date string) (_ []*domain.EODBalance, err error) {
+	query := `SELECT to_char(e.business_date, 'YYYY-MM-DD'), e.account_id, a.account_number, e.opening_balance, e.total_debits,
+			e.total_credits, e.closing_balance, e.transaction_count
+		FROM eod_balances e JOIN accounts a ON a.id = e.account_id
+		WHERE e.business_date = $1 ORDER BY e.account_id`
+	ctx, span := startSpan(ctx, "BusinessDayRepository.FindEODBalances", query)
+	var balances []*domain.EODBalance
+	defer func() { endSpan(span, int64(len(balances)), err) }()
+
+	rows, err := db.QueryContext(ctx, query, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		b := &domain.EODBalance{}
+		if err := rows.Scan(&b.BusinessDate, &b.AccountID, &b.AccountNumber, &b.OpeningBalance, &b.TotalDebits,
+			&b.TotalCredits, &b.ClosingBalance, &b.TransactionCount); err != nil {
+			return nil, err
+		}
+		balances = append(balances, b)
+	}
+	return balances, rows.Err()
+}
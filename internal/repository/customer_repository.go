@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+type CustomerRepository struct{}
+
+// 客戶以 public_id (隨機 UUID) 對外識別，流水號 id 僅用於關聯
+const customerColumns = `public_id, legal_name, to_char(date_of_birth, 'YYYY-MM-DD'), national_id_hash, COALESCE(email, ''), COALESCE(phone, ''),
+	COALESCE(address, ''), kyc_status, COALESCE(kyc_note, ''), created_at, updated_at`
+
+const holderQuery = `SELECT h.account_id, a.account_number, c.public_id, c.legal_name, h.role, c.kyc_status, h.created_at
+	FROM account_holders h JOIN customers c ON c.id = h.customer_id JOIN accounts a ON a.id = h.account_id`
+
+// 建立客戶，身分證號重複時回傳 unique violation (以 IsUniqueViolation 判斷)
+func (r *CustomerRepository) Insert(ctx context.Context, db DBTX, c *domain.Customer) (err error) {
+	query := `INSERT INTO customers (legal_name, date_of_birth, national_id_hash, email, phone, address, kyc_status)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), $7) RETURNING public_id, created_at, updated_at`
+	ctx, span := startSpan(ctx, "CustomerRepository.Insert", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query, c.LegalName, c.DateOfBirth, c.NationalIDHash, c.Email, c.Phone, c.Address, c.KYCStatus).
+		Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+}
+
+// 依對外 ID 查詢客戶，找不到時回傳 sql.ErrNoRows
+func (r *CustomerRepository) FindById(ctx context.Context, db DBTX, id string) (_ *domain.Customer, err error) {
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE public_id = $1`
+	ctx, span := startSpan(ctx, "CustomerRepository.FindById", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	c := &domain.Customer{}
+	err = db.QueryRowContext(ctx, query, id).Scan(&c.ID, &c.LegalName, &c.DateOfBirth, &c.NationalIDHash, &c.Email, &c.Phone,
+		&c.Address, &c.KYCStatus, &c.KYCNote, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// 更新 KYC 狀態與備註
+func (r *CustomerRepository) UpdateKYC(ctx context.Context, db DBTX, c *domain.Customer) (err error) {
+	query := `UPDATE customers SET kyc_status = $2, kyc_note = NULLIF($3, ''), updated_at = NOW() WHERE public_id = $1 RETURNING updated_at`
+	ctx, span := startSpan(ctx, "CustomerRepository.UpdateKYC", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query, c.ID, c.KYCStatus, c.KYCNote).Scan(&c.UpdatedAt)
+}
+
+// 新增帳號持有人，重複加入時回傳 unique violation
+func (r *CustomerRepository) AddHolder(ctx context.Context, db DBTX, h *domain.AccountHolder) (err error) {
+	query := `INSERT INTO account_holders (account_id, customer_id, role)
+		SELECT $1, id, $3 FROM customers WHERE public_id = $2 RETURNING created_at`
+	ctx, span := startSpan(ctx, "CustomerRepository.AddHolder", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query, h.AccountID, h.CustomerID, h.Role).Scan(&h.CreatedAt)
+}
+
+// 查詢帳號的持有人 (主要持有人優先)
+func (r *CustomerRepository) FindHolders(ctx context.Context, db DBTX, accountID string) (_ []*domain.AccountHolder, err error) {
+	query := holderQuery + ` WHERE h.account_id = $1 ORDER BY h.role <> 'primary', h.created_at`
+	ctx, span := startSpan(ctx, "CustomerRepository.FindHolders", query)
+	var holders []*domain.AccountHolder
+	defer func() { endSpan(span, int64(len(holders)), err) }()
+
+	holders, err = r.findHolders(ctx, db, query, accountID)
+	return holders, err
+}
+
+// 查詢客戶持有的帳號
+func (r *CustomerRepository) FindAccounts(ctx context.Context, db DBTX, customerID string) (_ []*domain.AccountHolder, err error) {
+	query := holderQuery + ` WHERE c.public_id = $1 ORDER BY h.account_id`
+	ctx, span := startSpan(ctx, "CustomerRepository.FindAccounts", query)
+	var holders []*domain.AccountHolder
+	defer func() { endSpan(span, int64(len(holders)), err) }()
+
+	holders, err = r.findHolders(ctx, db, query, customerID)
+	return holders, err
+}
+
+func (r *CustomerRepository) findHolders(ctx context.Context, db DBTX, query string, arg any) ([]*domain.AccountHolder, error) {
+	rows, err := db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var holders []*domain.AccountHolder
+	for rows.Next() {
+		h := &domain.AccountHolder{}
+		if err := rows.Scan(&h.AccountID, &h.AccountNumber, &h.CustomerID, &h.LegalName, &h.Role, &h.KYCStatus, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		holders = append(holders, h)
+	}
+	return holders, rows.Err()
+}
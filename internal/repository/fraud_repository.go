@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+type FraudRepository struct{}
+
+// FraudDecisionFilter 查詢風控決策的條件，零值代表不限
+type FraudDecisionFilter struct {
+	AccountNumber string // 轉出帳號號碼
+	Decision      string
+	AfterID       int64
+	Limit         int
+}
+
+// 寫入風控決策
+func (r *FraudRepository) InsertDecision(ctx context.Context, db DBTX, d *domain.FraudDecision) (err error) {
+	query := `INSERT INTO fraud_decisions (from_account_id, to_account_id, amount, decision, rule, reason, hits, pending_transfer_id, ref_id, request_id)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, NULLIF($8, '')::BIGINT, NULLIF($9, ''), NULLIF($10, ''))
+		RETURNING id, created_at`
+	ctx, span := startSpan(ctx, "FraudRepository.InsertDecision", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query, d.FromAccountID, d.ToAccountID, d.Amount, d.Decision, d.Rule, d.Reason,
+		[]byte(d.Hits), d.PendingTransferID, d.RefID, d.RequestID).Scan(&d.ID, &d.CreatedAt)
+}
+
+// 查詢風控決策 (依時間順序)
+func (r *FraudRepository) FindDecisions(ctx context.Context, db DBTX, f FraudDecisionFilter) (_ []*domain.FraudDecision, err error) {
+	var args []any
+	conds := []string{"TRUE"}
+	add := func(cond string, v any) {
+		args = append(args, v)
+		conds = append(conds, strings.Replace(cond, "?", "$"+strconv.Itoa(len(args)), 1))
+	}
+	if f.AccountNumber != "" {
+		add("from_account_id = (SELECT id FROM accounts WHERE account_number = ?)", f.AccountNumber)
+	}
+	if f.Decision != "" {
+		add("decision = ?", f.Decision)
+	}
+	if f.AfterID > 0 {
+		add("id > ?", f.AfterID)
+	}
+	query := `SELECT id, from_account_id, to_account_id,
+			(SELECT account_number FROM accounts WHERE accounts.id = from_account_id), (SELECT account_number FROM accounts WHERE accounts.id = to_account_id),
+			amount, decision, COALESCE(rule, ''), COALESCE(reason, ''), hits,
+			COALESCE(pending_transfer_id::TEXT, ''), COALESCE(ref_id, ''), COALESCE(request_id, ''), created_at
+		FROM fraud_decisions WHERE ` + strings.Join(conds, " AND ") + ` ORDER BY id`
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+	ctx, span := startSpan(ctx, "FraudRepository.FindDecisions", query)
+	var decisions []*domain.FraudDecision
+	defer func() { endSpan(span, int64(len(decisions)), err) }()
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		d := &domain.FraudDecision{}
+		var hits []byte
+		if err := rows.Scan(&d.ID, &d.FromAccountID, &d.ToAccountID, &d.FromAccount, &d.ToAccount, &d.Amount, &d.Decision, &d.Rule, &d.Reason, &hits,
+			&d.PendingTransferID, &d.RefID, &d.RequestID, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		d.Hits = hits
+		decisions = append(decisions, d)
+	}
+	return decisions, rows.Err()
+}
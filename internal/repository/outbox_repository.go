@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+// outbox 投遞狀態
+const (
+	DeliveryPending   = "pending"
+	DeliveryRetrying  = "retrying"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+type OutboxRepository struct{}
+
+// PendingEvent 待投遞的事件與已嘗試次數
+type PendingEvent struct {
+	Event    *domain.Event
+	Attempts int
+}
+
+// 寫入 outbox 事件 (需與業務資料在同一個 transaction)，並為每個已登記的 sink 建立待投遞紀錄
+func (r *OutboxRepository) Insert(ctx context.Context, db DBTX, ev *domain.Event) (err error) {
+	query := `WITH e AS (
+			INSERT INTO outbox_events (event_type, aggregate_id, request_id, payload, occurred_at)
+			VALUES ($1, $2, NULLIF($3, ''), $4, $5) RETURNING id
+		), d AS (
+			INSERT INTO outbox_deliveries (event_id, sink, status, next_attempt_at)
+			SELECT e.id, s.name, 'pending', NOW() FROM e CROSS JOIN outbox_sinks s
+		)
+		SELECT id FROM e`
+	ctx, span := startSpan(ctx, "OutboxRepository.Insert", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query, ev.Type, ev.AggregateID, ev.RequestID, string(ev.Data), ev.OccurredAt).Scan(&ev.ID)
+}
+
+// 查詢帳號相關 (含轉帳轉入) 且 ID 大於 afterID 的事件
+// 只有 ID 依提交順序遞增時才不會漏掉事件，見 auditLockKey
+func (r *OutboxRepository) FindByAccount(ctx context.Context, db DBTX, accountID string, afterID int64, limit int) (_ []*domain.Event, err error) {
+	query := `SELECT id, event_type, aggregate_id, COALESCE(request_id, ''), payload, occurred_at
+		FROM outbox_events
+		WHERE id > $2 AND (aggregate_id = $1 OR payload->>'to_account_number' = $1)
+		ORDER BY id
+		LIMIT $3`
+	ctx, span := startSpan(ctx, "OutboxRepository.FindByAccount", query)
+	var events []*domain.Event
+	defer func() { endSpan(span, int64(len(events)), err) }()
+
+	rows, err := db.QueryContext(ctx, query, accountID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		ev := &domain.Event{}
+		var payload []byte
+		if err := rows.Scan(&ev.ID, &ev.Type, &ev.AggregateID, &ev.RequestID, &payload, &ev.OccurredAt); err != nil {
+			return nil, err
+		}
+		ev.Data = payload
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
+
+// 登記 sink，之後寫入的事件才會為它建立待投遞紀錄
+func (r *OutboxRepository) RegisterSink(ctx context.Context, db DBTX, sink string) (err error) {
+	query := `INSERT INTO outbox_sinks (name) VALUES ($1) ON CONFLICT DO NOTHING`
+	ctx, span := startSpan(ctx, "OutboxRepository.RegisterSink", query)
+	var rows int64
+	defer func() { endSpan(span, rows, err) }()
+
+	result, err := db.ExecContext(ctx, query, sink)
+	if err != nil {
+		return err
+	}
+	rows, _ = result.RowsAffected()
+	return nil
+}
+
+// 認領指定 sink 已到投遞時間的待投遞紀錄 (尚未投遞，或重試時間已到)，並將下次投遞時間延後 lease
+// 認領後即提交，投遞期間不持有交易與鎖；relay 中途停止而未記錄結果時，租約到期後會再次投遞
+// 只認領投遞紀錄，不同 sink 互不影響；使用 SKIP LOCKED，同一個 sink 的多個 relay 不會重複取得相同事件
+func (r *OutboxRepository) ClaimPending(ctx context.Context, db DBTX, sink string, limit int, lease time.Duration) (_ []PendingEvent, err error) {
+	query := `WITH claimed AS (
+			UPDATE outbox_deliveries SET next_attempt_at = NOW() + $3 * INTERVAL '1 millisecond'
+			WHERE (event_id, sink) IN (
+				SELECT event_id, sink FROM outbox_deliveries
+				WHERE sink = $1 AND status IN ('pending', 'retrying') AND next_attempt_at <= NOW()
+				ORDER BY event_id
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING event_id, attempts
+		)
+		SELECT e.id, e.event_type, e.aggregate_id, COALESCE(e.request_id, ''), e.payload, e.occurred_at, d.attempts
+		FROM claimed d
+		JOIN outbox_events e ON e.id = d.event_id
+		ORDER BY e.id`
+	ctx, span := startSpan(ctx, "OutboxRepository.ClaimPending", query)
+	var events []PendingEvent
+	defer func() { endSpan(span, int64(len(events)), err) }()
+
+	rows, err := db.QueryContext(ctx, query, sink, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		ev := &domain.Event{}
+		var payload []byte
+		var attempts int
+		if err := rows.Scan(&ev.ID, &ev.Type, &ev.AggregateID, &ev.RequestID, &payload, &ev.OccurredAt, &attempts); err != nil {
+			return nil, err
+		}
+		ev.Data = payload
+		events = append(events, PendingEvent{Event: ev, Attempts: attempts})
+	}
+	return events, rows.Err()
+}
+
+// 記錄投遞結果；status 為 retrying 時於 nextAttempt 後重試
+func (r *OutboxRepository) SaveDelivery(ctx context.Context, db DBTX, eventID int64, sink, status string, nextAttempt time.Time, lastErr string) (err error) {
+	query := `INSERT INTO outbox_deliveries (event_id, sink, status, attempts, next_attempt_at, last_error, delivered_at)
+		VALUES ($1, $2, $3, 1, $4, NULLIF($5, ''), CASE WHEN $3 = 'delivered' THEN NOW() END)
+		ON CONFLICT (event_id, sink) DO UPDATE SET
+			status = EXCLUDED.status,
+			attempts = outbox_deliveries.attempts + 1,
+			next_attempt_at = EXCLUDED.next_attempt_at,
+			last_error = EXCLUDED.last_error,
+			delivered_at = EXCLUDED.delivered_at`
+	ctx, span := startSpan(ctx, "OutboxRepository.SaveDelivery", query)
+	var rows int64
+	defer func() { endSpan(span, rows, err) }()
+
+	result, err := db.ExecContext(ctx, query, eventID, sink, status, nextAttempt, lastErr)
+	if err != nil {
+		return err
+	}
+	rows, _ = result.RowsAffected()
+	return nil
+}
+
+// 刪除 before 之前發生的事件 (連同投遞紀錄)，每次最多 limit 筆，回傳刪除的筆數
+// 仍有 sink 等待投遞或重試的事件保留到投遞完成 (或標記 failed) 為止
+func (r *OutboxRepository) Prune(ctx context.Context, db DBTX, before time.Time, limit int) (_ int, err error) {
+	query := `DELETE FROM outbox_events WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE occurred_at < $1
+				AND NOT EXISTS (
+					SELECT 1 FROM outbox_deliveries d
+					WHERE d.event_id = outbox_events.id AND d.status IN ('pending', 'retrying')
+				)
+			ORDER BY id LIMIT $2
+		)`
+	ctx, span := startSpan(ctx, "OutboxRepository.Prune", query)
+	var rows int64
+	defer func() { endSpan(span, rows, err) }()
+
+	result, err := db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	rows, _ = result.RowsAffected()
+	return int(rows), nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+type PayeeRepository struct{}
+
+const payeeQuery = `SELECT p.id, p.account_id, p.payee_account_id, a.account_number, p.nickname, p.transfer_limit, p.daily_limit,
+	p.cooling_off_until, p.created_at, p.updated_at
+	FROM payees p JOIN accounts a ON a.id = p.payee_account_id`
+
+// 新增收款人，同一帳號的收款帳號或暱稱重複時回傳 unique violation
+func (r *PayeeRepository) Insert(ctx context.Context, db DBTX, p *domain.Payee) (err error) {
+	query := `INSERT INTO payees (account_id, payee_account_id, nickname, transfer_limit, daily_limit, cooling_off_until)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at`
+	ctx, span := startSpan(ctx, "PayeeRepository.Insert", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query, p.AccountID, p.PayeeAccountID, p.Nickname, p.TransferLimit, p.DailyLimit, p.CoolingOffUntil).
+		Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+}
+
+// 查詢帳號的收款人，不屬於該帳號或找不到時回傳 sql.ErrNoRows
+func (r *PayeeRepository) FindById(ctx context.Context, db DBTX, accountID, id string) (_ *domain.Payee, err error) {
+	query := payeeQuery + ` WHERE p.account_id = $1 AND p.id = $2`
+	ctx, span := startSpan(ctx, "PayeeRepository.FindById", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return scanPayee(db.QueryRowContext(ctx, query, accountID, id))
+}
+
+// 依收款帳號查詢收款人，未約定時回傳 sql.ErrNoRows
+func (r *PayeeRepository) FindByPayeeAccount(ctx context.Context, db DBTX, accountID, payeeAccountID string) (_ *domain.Payee, err error) {
+	query := payeeQuery + ` WHERE p.account_id = $1 AND p.payee_account_id = $2`
+	ctx, span := startSpan(ctx, "PayeeRepository.FindByPayeeAccount", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return scanPayee(db.QueryRowContext(ctx, query, accountID, payeeAccountID))
+}
+
+// 查詢帳號的所有收款人 (依暱稱排序)
+func (r *PayeeRepository) FindByAccount(ctx context.Context, db DBTX, accountID string) (_ []*domain.Payee, err error) {
+	query := payeeQuery + ` WHERE p.account_id = $1 ORDER BY p.nickname`
+	ctx, span := startSpan(ctx, "PayeeRepository.FindByAccount", query)
+	var payees []*domain.Payee
+	defer func() { endSpan(span, int64(len(payees)), err) }()
+
+	rows, err := db.QueryContext(ctx, query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		p, err := scanPayee(rows)
+		if err != nil {
+			return nil, err
+		}
+		payees = append(payees, p)
+	}
+	return payees, rows.Err()
+}
+
+// 帳號的收款人數量
+func (r *PayeeRepository) Count(ctx context.Context, db DBTX, accountID string) (_ int, err error) {
+	query := `SELECT COUNT(*) FROM payees WHERE account_id = $1`
+	ctx, span := startSpan(ctx, "PayeeRepository.Count", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	var n int
+	err = db.QueryRowContext(ctx, query, accountID).Scan(&n)
+	return n, err
+}
+
+// 更新暱稱、上限與冷靜期，找不到時回傳 sql.ErrNoRows
+func (r *PayeeRepository) Update(ctx context.Context, db DBTX, p *domain.Payee) (err error) {
+	query := `UPDATE payees SET nickname = $3, transfer_limit = $4, daily_limit = $5, cooling_off_until = $6, updated_at = NOW()
+		WHERE account_id = $1 AND id = $2 RETURNING updated_at`
+	ctx, span := startSpan(ctx, "PayeeRepository.Update", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query, p.AccountID, p.ID, p.Nickname, p.TransferLimit, p.DailyLimit, p.CoolingOffUntil).
+		Scan(&p.UpdatedAt)
+}
+
+// 刪除收款人，找不到時回傳 sql.ErrNoRows
+func (r *PayeeRepository) Delete(ctx context.Context, db DBTX, accountID, id string) (err error) {
+	query := `DELETE FROM payees WHERE account_id = $1 AND id = $2`
+	ctx, span := startSpan(ctx, "PayeeRepository.Delete", query)
+	var rows int64
+	defer func() { endSpan(span, rows, err) }()
+
+	result, err := db.ExecContext(ctx, query, accountID, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ = result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanPayee(row scanner) (*domain.Payee, error) {
+	p := &domain.Payee{}
+	if err := row.Scan(&p.ID, &p.AccountID, &p.PayeeAccountID, &p.AccountNumber, &p.Nickname, &p.TransferLimit, &p.DailyLimit,
+		&p.CoolingOffUntil, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
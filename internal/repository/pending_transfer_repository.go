@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+type PendingTransferRepository struct{}
+
+// PendingTransferFilter 查詢待處理轉帳的條件，零值代表不限
+type PendingTransferFilter struct {
+	Status        string
+	AccountNumber string // 轉出或轉入帳號號碼
+	AfterID       int64
+	Limit         int
+}
+
+const pendingTransferColumns = `id, from_account_id, to_account_id,
+	(SELECT account_number FROM accounts WHERE accounts.id = from_account_id), (SELECT account_number FROM accounts WHERE accounts.id = to_account_id), amount, reason, status, requested_by,
+	COALESCE(request_id, ''), COALESCE(decided_by, ''), COALESCE(decision_note, ''), COALESCE(ref_id, ''), held_amount, expires_at, created_at, decided_at`
+
+// 建立待處理轉帳
+func (r *PendingTransferRepository) Insert(ctx context.Context, db DBTX, pt *domain.PendingTransfer) (err error) {
+	query := `INSERT INTO pending_transfers (from_account_id, to_account_id, amount, reason, status, requested_by, request_id, held_amount, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9) RETURNING id, created_at`
+	ctx, span := startSpan(ctx, "PendingTransferRepository.Insert", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query, pt.FromAccountID, pt.ToAccountID, pt.Amount, pt.Reason, pt.Status, pt.RequestedBy, pt.RequestID, pt.HeldAmount, pt.ExpiresAt).
+		Scan(&pt.ID, &pt.CreatedAt)
+}
+
+// 鎖定待處理轉帳 (需在交易內呼叫)，找不到時回傳 sql.ErrNoRows
+func (r *PendingTransferRepository) FindByIdForUpdate(ctx context.Context, db DBTX, id string) (_ *domain.PendingTransfer, err error) {
+	query := `SELECT ` + pendingTransferColumns + ` FROM pending_transfers WHERE id = $1 FOR UPDATE`
+	ctx, span := startSpan(ctx, "PendingTransferRepository.FindByIdForUpdate", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return scanPendingTransfer(db.QueryRowContext(ctx, query, id))
+}
+
+// 查詢單筆待處理轉帳，找不到時回傳 sql.ErrNoRows
+func (r *PendingTransferRepository) FindById(ctx context.Context, db DBTX, id string) (_ *domain.PendingTransfer, err error) {
+	query := `SELECT ` + pendingTransferColumns + ` FROM pending_transfers WHERE id = $1`
+	ctx, span := startSpan(ctx, "PendingTransferRepository.FindById", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return scanPendingTransfer(db.QueryRowContext(ctx, query, id))
+}
+
+// 鎖定已逾期仍未處理的轉帳 (需在交易內呼叫)，略過其他交易已鎖定的資料
+func (r *PendingTransferRepository) ClaimExpired(ctx context.Context, db DBTX, now time.Time) (_ []*domain.PendingTransfer, err error) {
+	query := `SELECT ` + pendingTransferColumns + ` FROM pending_transfers
+		WHERE status = $1 AND expires_at <= $2 ORDER BY id FOR UPDATE SKIP LOCKED`
+	ctx, span := startSpan(ctx, "PendingTransferRepository.ClaimExpired", query)
+	var transfers []*domain.PendingTransfer
+	defer func() { endSpan(span, int64(len(transfers)), err) }()
+
+	rows, err := db.QueryContext(ctx, query, domain.PendingTransferPending, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		pt, err := scanPendingTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, pt)
+	}
+	return transfers, rows.Err()
+}
+
+// 查詢待處理轉帳 (依建立順序)
+func (r *PendingTransferRepository) Find(ctx context.Context, db DBTX, f PendingTransferFilter) (_ []*domain.PendingTransfer, err error) {
+	var args []any
+	conds := []string{"TRUE"}
+	add := func(cond string, v any) {
+		args = append(args, v)
+		conds = append(conds, strings.ReplaceAll(cond, "?", "$"+strconv.Itoa(len(args))))
+	}
+	if f.Status != "" {
+		add("status = ?", f.Status)
+	}
+	if f.AccountNumber != "" {
+		add("EXISTS (SELECT 1 FROM accounts WHERE accounts.account_number = ? AND accounts.id IN (from_account_id, to_account_id))", f.AccountNumber)
+	}
+	if f.AfterID > 0 {
+		add("id > ?", f.AfterID)
+	}
+	query := `SELECT ` + pendingTransferColumns + ` FROM pending_transfers WHERE ` + strings.Join(conds, " AND ") + ` ORDER BY id`
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+	ctx, span := startSpan(ctx, "PendingTransferRepository.Find", query)
+	var transfers []*domain.PendingTransfer
+	defer func() { endSpan(span, int64(len(transfers)), err) }()
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		pt, err := scanPendingTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, pt)
+	}
+	return transfers, rows.Err()
+}
+
+// 記錄處理結果 (狀態、處理者、備註與交易 ref_id)
+func (r *PendingTransferRepository) Decide(ctx context.Context, db DBTX, pt *domain.PendingTransfer) (err error) {
+	query := `UPDATE pending_transfers
+		SET status = $2, decided_by = $3, decision_note = NULLIF($4, ''), ref_id = NULLIF($5, ''), decided_at = NOW()
+		WHERE id = $1 RETURNING decided_at`
+	ctx, span := startSpan(ctx, "PendingTransferRepository.Decide", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query, pt.ID, pt.Status, pt.DecidedBy, pt.DecisionNote, pt.RefID).Scan(&pt.DecidedAt)
+}
+
+// 寫入一筆處理紀錄
+func (r *PendingTransferRepository) InsertAction(ctx context.Context, db DBTX, a *domain.PendingTransferAction) (err error) {
+	query := `INSERT INTO pending_transfer_history (pending_transfer_id, action, actor, note, request_id)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, '')) RETURNING id, created_at`
+	ctx, span := startSpan(ctx, "PendingTransferRepository.InsertAction", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query, a.PendingTransferID, a.Action, a.Actor, a.Note, a.RequestID).Scan(&a.ID, &a.CreatedAt)
+}
+
+// 查詢待處理轉帳的處理紀錄 (依時間順序)
+func (r *PendingTransferRepository) FindActions(ctx context.Context, db DBTX, id string) (_ []*domain.PendingTransferAction, err error) {
+	query := `SELECT id, pending_transfer_id, action, actor, COALESCE(note, ''), COALESCE(request_id, ''), created_at
+		FROM pending_transfer_history WHERE pending_transfer_id = $1 ORDER BY id`
+	ctx, span := startSpan(ctx, "PendingTransferRepository.FindActions", query)
+	var actions []*domain.PendingTransferAction
+	defer func() { endSpan(span, int64(len(actions)), err) }()
+
+	rows, err := db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		a := &domain.PendingTransferAction{}
+		if err := rows.Scan(&a.ID, &a.PendingTransferID, &a.Action, &a.Actor, &a.Note, &a.RequestID, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		actions = append(actions, a)
+	}
+	return actions, rows.Err()
+}
+
+func scanPendingTransfer(row scanner) (*domain.PendingTransfer, error) {
+	pt := &domain.PendingTransfer{}
+	if err := row.Scan(&pt.ID, &pt.FromAccountID, &pt.ToAccountID, &pt.FromAccount, &pt.ToAccount, &pt.Amount, &pt.Reason, &pt.Status, &pt.RequestedBy,
+		&pt.RequestID, &pt.DecidedBy, &pt.DecisionNote, &pt.RefID, &pt.HeldAmount, &pt.ExpiresAt, &pt.CreatedAt, &pt.DecidedAt); err != nil {
+		return nil, err
+	}
+	return pt, nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type RateLimitRepository struct{}
+
+// 鎖定令牌桶並回傳目前狀態，不存在時以 tokens 建立 (需在交易內呼叫)
+func (r *RateLimitRepository) LockBucket(ctx context.Context, db DBTX, key string, tokens float64, now time.Time) (_ float64, updated time.Time, err error) {
+	query := `INSERT INTO rate_limit_buckets (key, tokens, updated_at) VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET key = EXCLUDED.key
+		RETURNING tokens, updated_at`
+	ctx, span := startSpan(ctx, "RateLimitRepository.LockBucket", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	err = db.QueryRowContext(ctx, query, key, tokens, now).Scan(&tokens, &updated)
+	return tokens, updated, err
+}
+
+// 更新令牌桶剩餘 token
+func (r *RateLimitRepository) SaveBucket(ctx context.Context, db DBTX, key string, tokens float64, updated time.Time) (err error) {
+	query := `UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3 WHERE key = $1`
+	ctx, span := startSpan(ctx, "RateLimitRepository.SaveBucket", query)
+	var rows int64
+	defer func() { endSpan(span, rows, err) }()
+
+	result, err := db.ExecContext(ctx, query, key, tokens, updated)
+	if err != nil {
+		return err
+	}
+	rows, _ = result.RowsAffected()
+	return nil
+}
+
+// 刪除 before 之前就未再取用的令牌桶 (已補滿，與不存在時相同)，回傳刪除的筆數
+func (r *RateLimitRepository) DeleteIdle(ctx context.Context, db DBTX, before time.Time, limit int) (_ int, err error) {
+	query := `DELETE FROM rate_limit_buckets WHERE key IN (
+			SELECT key FROM rate_limit_buckets WHERE updated_at < $1 LIMIT $2 FOR UPDATE SKIP LOCKED
+		)`
+	ctx, span := startSpan(ctx, "RateLimitRepository.DeleteIdle", query)
+	var rows int64
+	defer func() { endSpan(span, rows, err) }()
+
+	result, err := db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	rows, _ = result.RowsAffected()
+	return int(rows), nil
+}
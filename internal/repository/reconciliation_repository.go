@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+type ReconciliationRepository struct{}
+
+// 帳號總數
+func (r *ReconciliationRepository) CountAccounts(ctx context.Context, db DBTX) (_ int, err error) {
+	query := `SELECT COUNT(*) FROM accounts`
+	ctx, span := startSpan(ctx, "ReconciliationRepository.CountAccounts", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	var n int
+	err = db.QueryRowContext(ctx, query).Scan(&n)
+	return n, err
+}
+
+// 找出餘額與交易紀錄加總 (存款 - 提款) 不符的帳號
+func (r *ReconciliationRepository) FindMismatches(ctx context.Context, db DBTX) (_ []domain.ReconciliationMismatch, err error) {
+	query := `SELECT a.account_number, a.name, a.balance, COALESCE(l.ledger, 0)
+		FROM accounts a
+		LEFT JOIN (
+			SELECT account_id, SUM(CASE WHEN type = 2 THEN amount ELSE -amount END) AS ledger
+			FROM transactions GROUP BY account_id
+		) l ON l.account_id = a.id
+		WHERE a.balance <> COALESCE(l.ledger, 0)
+		ORDER BY a.id`
+	ctx, span := startSpan(ctx, "ReconciliationRepository.FindMismatches", query)
+	var mismatches []domain.ReconciliationMismatch
+	defer func() { endSpan(span, int64(len(mismatches)), err) }()
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m domain.ReconciliationMismatch
+		if err := rows.Scan(&m.AccountNumber, &m.Name, &m.Balance, &m.LedgerBalance); err != nil {
+			return nil, err
+		}
+		m.Difference = m.Balance.Sub(m.LedgerBalance)
+		mismatches = append(mismatches, m)
+	}
+	return mismatches, rows.Err()
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/gl"
+)
+
+type ReportRepository struct{}
+
+// GLTotal 總帳科目在期間內的借貸方合計
+type GLTotal struct {
+	Key     string // gl.Key*
+	Debits  decimal.Decimal
+	Credits decimal.Decimal
+	Entries int
+}
+
+// 依總帳科目加總營業日 [from, through] 的交易，from 為空代表自第一筆交易起
+// 每筆交易記入帳號所屬科目 (提款為借方、存款為貸方)；存提款 (非轉帳、非手續費) 另以現金為對方科目
+// feeAccount、interestAccount 為帳號號碼
+func (r *ReportRepository) GLTotals(ctx context.Context, db DBTX, from, through, feeAccount, interestAccount string) (_ []GLTotal, err error) {
+	query := `WITH t AS (
+			SELECT a.account_number, tx.type, tx.kind, tx.amount
+			FROM transactions tx JOIN accounts a ON a.id = tx.account_id
+			WHERE tx.business_date >= COALESCE(NULLIF($1, '')::date, '-infinity'::date) AND tx.business_date <= $2::date
+		), entries AS (
+			SELECT CASE account_number WHEN $3 THEN $6::text WHEN $4 THEN $7 ELSE $8 END AS gl, type, amount FROM t
+			UNION ALL
+			SELECT $5::text, 3 - type, amount FROM t
+			WHERE kind NOT IN ($9, $10, $11)
+		)
+		SELECT gl, COALESCE(SUM(amount) FILTER (WHERE type = 1), 0), COALESCE(SUM(amount) FILTER (WHERE type = 2), 0), COUNT(*)
+		FROM entries GROUP BY gl ORDER BY gl`
+	ctx, span := startSpan(ctx, "ReportRepository.GLTotals", query)
+	var totals []GLTotal
+	defer func() { endSpan(span, int64(len(totals)), err) }()
+
+	rows, err := db.QueryContext(ctx, query, from, through, feeAccount, interestAccount,
+		gl.KeyCash, gl.KeyFeeRevenue, gl.KeyInterestExpense, gl.KeyCustomerDeposits,
+		domain.TxKindTransferOut, domain.TxKindTransferIn, domain.TxKindFee)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t GLTotal
+		if err := rows.Scan(&t.Key, &t.Debits, &t.Credits, &t.Entries); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
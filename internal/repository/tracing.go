@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+)
+
+// 為每個 repository 查詢建立 client span，記錄 SQL 語句
+func startSpan(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNamePostgreSQL, semconv.DBQueryText(query)),
+	)
+}
+
+// 結束查詢 span，記錄影響筆數；查無資料不視為錯誤
+func endSpan(span trace.Span, rows int64, err error) {
+	if err == nil || errors.Is(err, sql.ErrNoRows) {
+		span.SetAttributes(attribute.Int64("db.rows_affected", rows))
+		span.End()
+		return
+	}
+	tracing.End(span, err)
+}
@@ -1,33 +1,162 @@
 package repository
 
 import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
 	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/fraud"
 )
 
 type TransactionRepository struct{}
 
 // 寫入交易紀錄
-func (r *TransactionRepository) InsertTransactions(db DBTX, accountID string, tx *domain.Transaction) error {
-	query := `INSERT INTO transactions (account_id, type, amount, ref_id, description) VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	return db.QueryRow(query, accountID, tx.Type, tx.Amount, tx.RefID, tx.Description).Scan(&tx.ID)
+func (r *TransactionRepository) InsertTransactions(ctx context.Context, db DBTX, accountID string, tx *domain.Transaction) (err error) {
+	query := `INSERT INTO transactions (account_id, type, kind, amount, ref_id, description, request_id, business_date) VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8) RETURNING id`
+	ctx, span := startSpan(ctx, "TransactionRepository.InsertTransactions", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query, accountID, tx.Type, tx.Kind, tx.Amount, tx.RefID, tx.Description, tx.RequestID, tx.BusinessDate).Scan(&tx.ID)
+}
+
+// TransactionFilter 查詢交易紀錄的條件，零值代表不限
+type TransactionFilter struct {
+	Type    int       // 1=提款, 2=存款
+	From    time.Time // 含
+	To      time.Time // 不含
+	AfterID int64
+	Limit   int
 }
 
-// 根據 ID 查詢交易紀錄
-func (r *TransactionRepository) FindById(db DBTX, id string) ([]*domain.Transaction, error) {
-	query := `SELECT t.id, a.name, t.type, t.amount,t.ref_id,COALESCE(t.description, ''),t.created_at FROM transactions t JOIN accounts a ON t.account_id = a.id WHERE t.account_id = $1`
-	rows, err := db.Query(query, id)
+// 根據帳號 ID 查詢交易紀錄 (依交易順序)
+func (r *TransactionRepository) FindById(ctx context.Context, db DBTX, id string, f TransactionFilter) (_ []*domain.Transaction, err error) {
+	args := []any{id}
+	conds := []string{"t.account_id = $1"}
+	add := func(cond string, v any) {
+		args = append(args, v)
+		conds = append(conds, strings.Replace(cond, "?", "$"+strconv.Itoa(len(args)), 1))
+	}
+	if f.Type != 0 {
+		add("t.type = ?", f.Type)
+	}
+	if !f.From.IsZero() {
+		add("t.created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		add("t.created_at < ?", f.To)
+	}
+	if f.AfterID > 0 {
+		add("t.id > ?", f.AfterID)
+	}
+	query := `SELECT t.id, a.name, t.type, t.kind, t.amount,t.ref_id,COALESCE(t.description, ''),COALESCE(t.request_id, ''),to_char(t.business_date, 'YYYY-MM-DD'),t.created_at FROM transactions t JOIN accounts a ON t.account_id = a.id WHERE ` +
+		strings.Join(conds, " AND ") + ` ORDER BY t.id`
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+	ctx, span := startSpan(ctx, "TransactionRepository.FindById", query)
+	var transactions []*domain.Transaction
+	defer func() { endSpan(span, int64(len(transactions)), err) }()
+
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var transactions []*domain.Transaction
 	for rows.Next() {
 		tx := &domain.Transaction{}
-		if err := rows.Scan(&tx.ID, &tx.Name, &tx.Type, &tx.Amount, &tx.RefID, &tx.Description, &tx.CreatedAt); err != nil {
+		if err := rows.Scan(&tx.ID, &tx.Name, &tx.Type, &tx.Kind, &tx.Amount, &tx.RefID, &tx.Description, &tx.RequestID, &tx.BusinessDate, &tx.CreatedAt); err != nil {
 			return nil, err
 		}
 		transactions = append(transactions, tx)
 	}
-	return transactions, nil
+	return transactions, rows.Err()
+}
+
+// 帳號自 since 起 (含) 的餘額淨變動 (存款 - 提款)
+func (r *TransactionRepository) NetChangeSince(ctx context.Context, db DBTX, id string, since time.Time) (_ decimal.Decimal, err error) {
+	query := `SELECT COALESCE(SUM(CASE WHEN type = 2 THEN amount ELSE -amount END), 0) FROM transactions
+		WHERE account_id = $1 AND created_at >= $2`
+	ctx, span := startSpan(ctx, "TransactionRepository.NetChangeSince", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	var net decimal.Decimal
+	err = db.QueryRowContext(ctx, query, id, since).Scan(&net)
+	return net, err
+}
+
+// 帳號在 (after, until] 期間的餘額淨變動 (存款 - 提款)
+func (r *TransactionRepository) NetChangeBetween(ctx context.Context, db DBTX, id string, after, until time.Time) (_ decimal.Decimal, err error) {
+	query := `SELECT COALESCE(SUM(CASE WHEN type = 2 THEN amount ELSE -amount END), 0) FROM transactions
+		WHERE account_id = $1 AND created_at > $2 AND created_at <= $3`
+	ctx, span := startSpan(ctx, "TransactionRepository.NetChangeBetween", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	var net decimal.Decimal
+	err = db.QueryRowContext(ctx, query, id, after, until).Scan(&net)
+	return net, err
+}
+
+// 帳號自 since 起 (含) 嘗試轉出的金額 (不含手續費)，依時間排序
+// 包含已執行的轉帳、尚未執行或未核准的待處理轉帳 (核准後以執行的轉帳計入) 與風控拒絕的轉帳
+func (r *TransactionRepository) TransferAmountsSince(ctx context.Context, db DBTX, id string, since time.Time) (_ []decimal.Decimal, err error) {
+	query := `SELECT amount FROM (
+			SELECT amount, created_at FROM transactions
+			WHERE account_id = $1 AND kind = $2 AND created_at >= $3
+			UNION ALL
+			SELECT amount, created_at FROM pending_transfers
+			WHERE from_account_id = $1 AND status <> $4 AND created_at >= $3
+			UNION ALL
+			SELECT amount, created_at FROM fraud_decisions
+			WHERE from_account_id = $1 AND decision = $5 AND created_at >= $3
+		) attempts ORDER BY created_at`
+	ctx, span := startSpan(ctx, "TransactionRepository.TransferAmountsSince", query)
+	var amounts []decimal.Decimal
+	defer func() { endSpan(span, int64(len(amounts)), err) }()
+
+	rows, err := db.QueryContext(ctx, query, id, domain.TxKindTransferOut, since, domain.PendingTransferApproved, fraud.Block)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var amount decimal.Decimal
+		if err := rows.Scan(&amount); err != nil {
+			return nil, err
+		}
+		amounts = append(amounts, amount)
+	}
+	return amounts, rows.Err()
+}
+
+// 帳號是否曾轉帳給 toID (以轉出與轉入紀錄的 ref_id 配對)
+func (r *TransactionRepository) HasTransferredTo(ctx context.Context, db DBTX, fromID, toID string) (_ bool, err error) {
+	query := `SELECT EXISTS (
+		SELECT 1 FROM transactions o JOIN transactions i ON i.ref_id = o.ref_id
+		WHERE o.account_id = $1 AND o.kind = $3 AND i.account_id = $2 AND i.kind = $4)`
+	ctx, span := startSpan(ctx, "TransactionRepository.HasTransferredTo", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	var exists bool
+	err = db.QueryRowContext(ctx, query, fromID, toID, domain.TxKindTransferOut, domain.TxKindTransferIn).Scan(&exists)
+	return exists, err
+}
+
+// since 之後 fromID 轉帳給 toID 的總金額 (不含手續費)
+func (r *TransactionRepository) TransferredToSince(ctx context.Context, db DBTX, fromID, toID string, since time.Time) (_ decimal.Decimal, err error) {
+	query := `SELECT COALESCE(SUM(o.amount), 0)
+		FROM transactions o JOIN transactions i ON i.ref_id = o.ref_id
+		WHERE o.account_id = $1 AND o.kind = $3 AND o.created_at >= $5
+			AND i.account_id = $2 AND i.kind = $4`
+	ctx, span := startSpan(ctx, "TransactionRepository.TransferredToSince", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	var total decimal.Decimal
+	err = db.QueryRowContext(ctx, query, fromID, toID, domain.TxKindTransferOut, domain.TxKindTransferIn, since).Scan(&total)
+	return total, err
 }
@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+type WebhookRepository struct{}
+
+// DeliveryFilter 查詢投遞紀錄的條件
+type DeliveryFilter struct {
+	SubscriptionID string
+	Status         string
+	AfterID        int64
+	Limit          int
+}
+
+// DueDelivery 待投遞的 webhook 與目標訂閱、事件內容
+type DueDelivery struct {
+	Delivery *domain.WebhookDelivery
+	URL      string
+	Secret   string
+	Event    *domain.Event
+}
+
+const subscriptionColumns = `id, url, event_types, COALESCE(account_number, ''), secret, active, created_at, updated_at`
+
+// 建立訂閱
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, db DBTX, sub *domain.WebhookSubscription) (err error) {
+	query := `INSERT INTO webhook_subscriptions (url, event_types, account_number, secret, active)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5) RETURNING id, created_at, updated_at`
+	ctx, span := startSpan(ctx, "WebhookRepository.CreateSubscription", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query, sub.URL, pq.Array(sub.EventTypes), sub.AccountNumber, sub.Secret, sub.Active).
+		Scan(&sub.ID, &sub.CreatedAt, &sub.UpdatedAt)
+}
+
+// 查詢訂閱 (含 secret)
+func (r *WebhookRepository) FindSubscription(ctx context.Context, db DBTX, id string) (_ *domain.WebhookSubscription, err error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1`
+	ctx, span := startSpan(ctx, "WebhookRepository.FindSubscription", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return scanSubscription(db.QueryRowContext(ctx, query, id))
+}
+
+// 查詢所有訂閱
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context, db DBTX) (_ []*domain.WebhookSubscription, err error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions ORDER BY id`
+	ctx, span := startSpan(ctx, "WebhookRepository.ListSubscriptions", query)
+	var subs []*domain.WebhookSubscription
+	defer func() { endSpan(span, int64(len(subs)), err) }()
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+// 更新訂閱，找不到時回傳 sql.ErrNoRows
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, db DBTX, sub *domain.WebhookSubscription) (err error) {
+	query := `UPDATE webhook_subscriptions
+		SET url = $2, event_types = $3, account_number = NULLIF($4, ''), secret = $5, active = $6, updated_at = NOW()
+		WHERE id = $1 RETURNING updated_at`
+	ctx, span := startSpan(ctx, "WebhookRepository.UpdateSubscription", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return db.QueryRowContext(ctx, query, sub.ID, sub.URL, pq.Array(sub.EventTypes), sub.AccountNumber, sub.Secret, sub.Active).
+		Scan(&sub.UpdatedAt)
+}
+
+// 刪除訂閱 (投遞紀錄一併刪除)，找不到時回傳 sql.ErrNoRows
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, db DBTX, id string) (err error) {
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1`
+	ctx, span := startSpan(ctx, "WebhookRepository.DeleteSubscription", query)
+	var rows int64
+	defer func() { endSpan(span, rows, err) }()
+
+	result, err := db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ = result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// 為符合條件的訂閱建立投遞紀錄
+// 只會投遞訂閱建立之後發生的事件，重複呼叫不會重複建立
+func (r *WebhookRepository) EnqueueDeliveries(ctx context.Context, db DBTX, ev *domain.Event) (err error) {
+	query := `INSERT INTO webhook_deliveries (subscription_id, event_id, status, next_attempt_at)
+		SELECT s.id, $1, 'pending', NOW() FROM webhook_subscriptions s
+		WHERE s.active
+			AND s.created_at <= $2
+			AND (cardinality(s.event_types) = 0 OR $3 = ANY(s.event_types))
+			AND (s.account_number IS NULL OR s.account_number = ANY($4))
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`
+	ctx, span := startSpan(ctx, "WebhookRepository.EnqueueDeliveries", query)
+	var rows int64
+	defer func() { endSpan(span, rows, err) }()
+
+	result, err := db.ExecContext(ctx, query, ev.ID, ev.OccurredAt, ev.Type, pq.Array(ev.AccountNumbers()))
+	if err != nil {
+		return err
+	}
+	rows, _ = result.RowsAffected()
+	return nil
+}
+
+// 認領已到投遞時間的紀錄：將 next_attempt_at 延後 lease 作為租約，讓呼叫端不必在投遞期間持有 transaction
+// 使用 SKIP LOCKED 讓多個 dispatcher 可同時執行，租約到期前其他 dispatcher 不會再認領
+func (r *WebhookRepository) ClaimDue(ctx context.Context, db DBTX, limit int, lease time.Duration) (_ []DueDelivery, err error) {
+	query := `WITH claimed AS (
+			UPDATE webhook_deliveries SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+			WHERE id IN (
+				SELECT d.id FROM webhook_deliveries d
+				JOIN webhook_subscriptions s ON s.id = d.subscription_id
+				WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND s.active
+				ORDER BY d.id
+				LIMIT $1
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT d.id, d.subscription_id, d.event_id, d.status, d.attempts, d.next_attempt_at,
+			COALESCE(d.response_status, 0), COALESCE(d.last_error, ''), d.created_at, d.delivered_at,
+			s.url, s.secret,
+			e.event_type, e.aggregate_id, COALESCE(e.request_id, ''), e.payload, e.occurred_at
+		FROM claimed d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		JOIN outbox_events e ON e.id = d.event_id
+		ORDER BY d.id`
+	ctx, span := startSpan(ctx, "WebhookRepository.ClaimDue", query)
+	var due []DueDelivery
+	defer func() { endSpan(span, int64(len(due)), err) }()
+
+	rows, err := db.QueryContext(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		d := &domain.WebhookDelivery{}
+		ev := &domain.Event{}
+		var item DueDelivery
+		var payload []byte
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.Status, &d.Attempts, &d.NextAttemptAt,
+			&d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt,
+			&item.URL, &item.Secret,
+			&ev.Type, &ev.AggregateID, &ev.RequestID, &payload, &ev.OccurredAt); err != nil {
+			return nil, err
+		}
+		ev.ID, ev.Data = d.EventID, payload
+		d.EventType = ev.Type
+		item.Delivery, item.Event = d, ev
+		due = append(due, item)
+	}
+	return due, rows.Err()
+}
+
+// 記錄一次投遞嘗試的結果
+func (r *WebhookRepository) SaveAttempt(ctx context.Context, db DBTX, id, status string, nextAttempt time.Time, responseStatus int, lastErr string) (err error) {
+	query := `UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, next_attempt_at = $3,
+			response_status = NULLIF($4, 0), last_error = NULLIF($5, ''),
+			delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() END
+		WHERE id = $1`
+	ctx, span := startSpan(ctx, "WebhookRepository.SaveAttempt", query)
+	var rows int64
+	defer func() { endSpan(span, rows, err) }()
+
+	result, err := db.ExecContext(ctx, query, id, status, nextAttempt, responseStatus, lastErr)
+	if err != nil {
+		return err
+	}
+	rows, _ = result.RowsAffected()
+	return nil
+}
+
+// 查詢投遞紀錄
+func (r *WebhookRepository) FindDeliveries(ctx context.Context, db DBTX, f DeliveryFilter) (_ []*domain.WebhookDelivery, err error) {
+	args := []any{f.SubscriptionID}
+	conds := []string{"d.subscription_id = $1"}
+	if f.Status != "" {
+		args = append(args, f.Status)
+		conds = append(conds, "d.status = $"+strconv.Itoa(len(args)))
+	}
+	if f.AfterID > 0 {
+		args = append(args, f.AfterID)
+		conds = append(conds, "d.id > $"+strconv.Itoa(len(args)))
+	}
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries d JOIN outbox_events e ON e.id = d.event_id
+		WHERE ` + strings.Join(conds, " AND ") + ` ORDER BY d.id`
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+	ctx, span := startSpan(ctx, "WebhookRepository.FindDeliveries", query)
+	var deliveries []*domain.WebhookDelivery
+	defer func() { endSpan(span, int64(len(deliveries)), err) }()
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// 查詢並鎖定投遞紀錄，找不到時回傳 sql.ErrNoRows
+func (r *WebhookRepository) FindDeliveryForUpdate(ctx context.Context, db DBTX, id string) (_ *domain.WebhookDelivery, err error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries d JOIN outbox_events e ON e.id = d.event_id
+		WHERE d.id = $1 FOR UPDATE OF d`
+	ctx, span := startSpan(ctx, "WebhookRepository.FindDeliveryForUpdate", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return scanDelivery(db.QueryRowContext(ctx, query, id))
+}
+
+// 重新排入投遞 (重置嘗試次數)，找不到時回傳 sql.ErrNoRows
+func (r *WebhookRepository) Redeliver(ctx context.Context, db DBTX, id string) (_ *domain.WebhookDelivery, err error) {
+	query := `WITH d AS (
+			UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = NOW(), delivered_at = NULL
+			WHERE id = $1 RETURNING *
+		)
+		SELECT ` + deliveryColumns + ` FROM d JOIN outbox_events e ON e.id = d.event_id`
+	ctx, span := startSpan(ctx, "WebhookRepository.Redeliver", query)
+	defer func() { endSpan(span, rowCount(err), err) }()
+
+	return scanDelivery(db.QueryRowContext(ctx, query, id))
+}
+
+const deliveryColumns = `d.id, d.subscription_id, d.event_id, e.event_type, d.status, d.attempts, d.next_attempt_at,
+	COALESCE(d.response_status, 0), COALESCE(d.last_error, ''), d.created_at, d.delivered_at`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSubscription(row scanner) (*domain.WebhookSubscription, error) {
+	sub := &domain.WebhookSubscription{}
+	var eventTypes pq.StringArray
+	if err := row.Scan(&sub.ID, &sub.URL, &eventTypes, &sub.AccountNumber, &sub.Secret, &sub.Active, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
+		return nil, err
+	}
+	sub.EventTypes = []string(eventTypes)
+	return sub, nil
+}
+
+func scanDelivery(row scanner) (*domain.WebhookDelivery, error) {
+	d := &domain.WebhookDelivery{}
+	if err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,6 @@
+package request
+
+// AccountImportQueryRequest 匯入帳號的 query string 參數
+type AccountImportQueryRequest struct {
+	DryRun bool `json:"dry_run"` // 只驗證並回報錯誤，不建立帳號
+}
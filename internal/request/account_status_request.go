@@ -0,0 +1,6 @@
+package request
+
+// FreezeAccountRequest 凍結帳號
+type FreezeAccountRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
@@ -0,0 +1,15 @@
+package request
+
+import "time"
+
+// AuditQueryRequest 查詢稽核紀錄的 query string 參數
+type AuditQueryRequest struct {
+	EntityType string    `json:"entity_type" validate:"omitempty,max=50"`
+	EntityID   string    `json:"entity_id" validate:"omitempty,max=50"`
+	Actor      string    `json:"actor" validate:"omitempty,max=100"`
+	Action     string    `json:"action" validate:"omitempty,max=50"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	AfterID    int64     `json:"after_id" validate:"gte=0"`
+	Limit      int       `json:"limit" validate:"gte=0,lte=500"`
+}
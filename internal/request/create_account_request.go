@@ -1,6 +1,10 @@
 package request
 
+import "github.com/shopspring/decimal"
+
 type CreateAccountRequest struct {
-	Name    string  `json:"name"`
-	Balance float64 `json:"balance"`
+	Name        string          `json:"name" validate:"required_without=CustomerIDs,max=100"` // 指定客戶時預設為主要持有人的姓名
+	Balance     decimal.Decimal `json:"balance" validate:"dnonnegative,dscale=2,dmaxabs"`
+	Product     string          `json:"product" validate:"omitempty,max=50"`                     // 預設 standard
+	CustomerIDs []string        `json:"customer_ids" validate:"max=5,unique,dive,required,uuid"` // 第一位為主要持有人，其餘為聯名持有人
 }
@@ -0,0 +1,22 @@
+package request
+
+// CreateCustomerRequest 建立客戶
+type CreateCustomerRequest struct {
+	LegalName   string `json:"legal_name" validate:"required,max=100"`
+	DateOfBirth string `json:"date_of_birth" validate:"required,datetime=2006-01-02"`
+	NationalID  string `json:"national_id" validate:"required,max=50"` // 僅保存雜湊
+	Email       string `json:"email" validate:"omitempty,email,max=255"`
+	Phone       string `json:"phone" validate:"omitempty,max=30"`
+	Address     string `json:"address" validate:"omitempty,max=500"`
+}
+
+// KYCStatusRequest 更新客戶的 KYC 狀態
+type KYCStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=pending verified rejected"`
+	Note   string `json:"note" validate:"max=255"`
+}
+
+// AddAccountHolderRequest 新增聯名帳號持有人
+type AddAccountHolderRequest struct {
+	CustomerID string `json:"customer_id" validate:"required,uuid"`
+}
@@ -0,0 +1,9 @@
+package request
+
+import "github.com/shopspring/decimal"
+
+// FeePreviewRequest 試算手續費的 query string 參數
+type FeePreviewRequest struct {
+	Operation string          `json:"operation" validate:"required,oneof=withdrawal transfer"`
+	Amount    decimal.Decimal `json:"amount" validate:"dpositive,dscale=2,dmaxabs"`
+}
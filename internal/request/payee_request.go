@@ -0,0 +1,18 @@
+package request
+
+import "github.com/shopspring/decimal"
+
+// CreatePayeeRequest 新增收款人，上限為 0 或省略代表不限
+type CreatePayeeRequest struct {
+	Nickname      string          `json:"nickname" validate:"required,max=50"`
+	AccountNumber string          `json:"account_number" validate:"required,account_number"`
+	TransferLimit decimal.Decimal `json:"transfer_limit" validate:"dnonnegative,dscale=2,dmaxabs"`
+	DailyLimit    decimal.Decimal `json:"daily_limit" validate:"dnonnegative,dscale=2,dmaxabs"`
+}
+
+// UpdatePayeeRequest 更新收款人暱稱與上限 (收款帳號不可變更)
+type UpdatePayeeRequest struct {
+	Nickname      string          `json:"nickname" validate:"required,max=50"`
+	TransferLimit decimal.Decimal `json:"transfer_limit" validate:"dnonnegative,dscale=2,dmaxabs"`
+	DailyLimit    decimal.Decimal `json:"daily_limit" validate:"dnonnegative,dscale=2,dmaxabs"`
+}
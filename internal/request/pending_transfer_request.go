@@ -0,0 +1,27 @@
+package request
+
+// PendingTransferQueryRequest 查詢待處理轉帳的 query string 參數
+type PendingTransferQueryRequest struct {
+	Status        string `json:"status" validate:"omitempty,oneof=pending approved rejected expired"`
+	AccountNumber string `json:"account_number" validate:"omitempty,account_number"`
+	AfterID       int64  `json:"after_id" validate:"gte=0"`
+	Limit         int    `json:"limit" validate:"gte=0,lte=500"`
+}
+
+// ApproveTransferRequest 核准待處理轉帳
+type ApproveTransferRequest struct {
+	Note string `json:"note" validate:"max=255"`
+}
+
+// RejectTransferRequest 拒絕待處理轉帳
+type RejectTransferRequest struct {
+	Note string `json:"note" validate:"required,max=255"`
+}
+
+// FraudDecisionQueryRequest 查詢風控決策的 query string 參數
+type FraudDecisionQueryRequest struct {
+	AccountNumber string `json:"account_number" validate:"omitempty,account_number"`
+	Decision      string `json:"decision" validate:"omitempty,oneof=allow review block"`
+	AfterID       int64  `json:"after_id" validate:"gte=0"`
+	Limit         int    `json:"limit" validate:"gte=0,lte=500"`
+}
@@ -0,0 +1,84 @@
+package request
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	decimalType = reflect.TypeOf(decimal.Decimal{})
+)
+
+// DecodeQuery 將 query string 依 json tag 填入 struct 後執行驗證
+// 支援 string、整數、布林、RFC 3339 時間與金額 (decimal) 欄位，未知參數會被拒絕
+func (v *Validator) DecodeQuery(values url.Values, dst any) error {
+	rv := reflect.ValueOf(dst).Elem()
+	t := rv.Type()
+	known := make(map[string]bool, t.NumField())
+	var errs ValidationErrors
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.SplitN(t.Field(i).Tag.Get("json"), ",", 2)[0]
+		known[name] = true
+		raw := values.Get(name)
+		if raw == "" {
+			continue
+		}
+		if err := setField(rv.Field(i), raw); err != nil {
+			errs = append(errs, FieldError{Field: name, Message: err.Error()})
+		}
+	}
+	for name := range values {
+		if !known[name] {
+			return fmt.Errorf("unknown query parameter %q", name)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return v.Validate(dst)
+}
+
+func setField(f reflect.Value, raw string) error {
+	if f.Type() == timeType {
+		ts, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return fmt.Errorf("must be an RFC 3339 timestamp")
+		}
+		f.Set(reflect.ValueOf(ts))
+		return nil
+	}
+	if f.Type() == decimalType {
+		d, err := decimal.NewFromString(raw)
+		if err != nil {
+			return fmt.Errorf("must be a decimal number")
+		}
+		f.Set(reflect.ValueOf(d))
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		f.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("must be true or false")
+		}
+		f.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported parameter type %s", f.Type())
+	}
+	return nil
+}
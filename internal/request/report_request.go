@@ -0,0 +1,6 @@
+package request
+
+// ReportQueryRequest 總帳報表的 query string 參數
+type ReportQueryRequest struct {
+	Date string `json:"date" validate:"omitempty,datetime=2006-01-02"` // 營業日，省略時為目前營業日
+}
@@ -0,0 +1,23 @@
+package request
+
+import "time"
+
+// TransactionQueryRequest 查詢交易紀錄的 query string 參數
+type TransactionQueryRequest struct {
+	Type    string    `json:"type" validate:"omitempty,oneof=deposit withdrawal"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	AfterID int64     `json:"after_id" validate:"gte=0"`
+	Limit   int       `json:"limit" validate:"gte=0,lte=500"`
+}
+
+// StatementQueryRequest 產生對帳單的 query string 參數
+type StatementQueryRequest struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// BalanceQueryRequest 查詢歷史餘額的 query string 參數
+type BalanceQueryRequest struct {
+	At time.Time `json:"at"`
+}
@@ -3,5 +3,5 @@ package request
 import "github.com/shopspring/decimal"
 
 type TransactionRequest struct {
-	Amount decimal.Decimal `json:"amount"`
+	Amount decimal.Decimal `json:"amount" validate:"dnonzero,dscale=2,dmaxabs"`
 }
@@ -1,9 +1,28 @@
 package request
 
-import "github.com/shopspring/decimal"
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
 
+// TransferRequest 以帳號號碼指定轉出與轉入帳號，轉入帳號亦可改以轉出帳號的收款人 ID 指定
 type TransferRequest struct {
-	FromID string          `json:"from_id"`
-	ToID   string          `json:"to_id"`
-	Amount decimal.Decimal `json:"amount"`
+	FromAccount string          `json:"from_account" validate:"required,account_number"`
+	ToAccount   string          `json:"to_account" validate:"omitempty,account_number"`
+	PayeeID     string          `json:"payee_id" validate:"omitempty,id"`
+	Amount      decimal.Decimal `json:"amount" validate:"dpositive,dscale=2,dmaxabs"`
+}
+
+// 轉入帳號與收款人需擇一指定，且轉出與轉入帳號不可相同
+func validateTransfer(sl validator.StructLevel) {
+	req := sl.Current().Interface().(TransferRequest)
+	switch {
+	case req.ToAccount == "" && req.PayeeID == "":
+		sl.ReportError(req.ToAccount, "to_account", "ToAccount", "required_without", "payee_id")
+	case req.ToAccount != "" && req.PayeeID != "":
+		sl.ReportError(req.PayeeID, "payee_id", "PayeeID", "excluded_with", "to_account")
+	}
+	if req.FromAccount != "" && req.FromAccount == req.ToAccount {
+		sl.ReportError(req.ToAccount, "to_account", "ToAccount", "distinct", "from_account")
+	}
 }
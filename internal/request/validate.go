@@ -0,0 +1,196 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+	"github.com/yoyo0827/simple-bank-system/internal/accountno"
+)
+
+// MaxAmount 為單筆金額上限 (對應 NUMERIC(15,2) 的整數位數)
+var MaxAmount = decimal.RequireFromString("9999999999999.99")
+
+// Validator 依 struct tag 驗證請求內容，帳號號碼依建立時指定的格式 (含檢查碼) 檢查
+type Validator struct {
+	validate       *validator.Validate
+	accountNumbers accountno.Config
+}
+
+// NewValidator 建立驗證器，account_number tag 與 ValidateAccountNumber 依 accountNumbers 檢查
+func NewValidator(accountNumbers accountno.Config) *Validator {
+	return &Validator{validate: newValidator(accountNumbers), accountNumbers: accountNumbers}
+}
+
+// FieldError 單一欄位的驗證錯誤
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors 一次回傳所有欄位的驗證錯誤
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Field+": "+fe.Message)
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Decode 解析 JSON body (拒絕未知欄位) 後執行驗證
+// JSON 格式錯誤回傳一般 error，驗證失敗回傳 ValidationErrors
+func (v *Validator) Decode(r io.Reader, dst any) error {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("request body must contain a single JSON object")
+	}
+	return v.Validate(dst)
+}
+
+// Validate 依據 struct tag 驗證請求內容
+func (v *Validator) Validate(dst any) error {
+	err := v.validate.Struct(dst)
+	if err == nil {
+		return nil
+	}
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+	out := make(ValidationErrors, 0, len(verrs))
+	for _, fe := range verrs {
+		out = append(out, FieldError{Field: fe.Field(), Message: message(fe)})
+	}
+	return out
+}
+
+// ValidateAccountNumber 驗證不在請求 body 中的帳號號碼 (例如路徑參數)，檢查碼錯誤時不必查詢資料庫
+func (v *Validator) ValidateAccountNumber(field, number string) error {
+	if !v.accountNumbers.Valid(number) {
+		return ValidationErrors{{Field: field, Message: accountNumberMessage}}
+	}
+	return nil
+}
+
+// ValidateVar 以 validate tag 驗證不在請求 body 中的值 (例如路徑參數)
+func (v *Validator) ValidateVar(field, value, tag string) error {
+	err := v.validate.Var(value, tag)
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+	out := make(ValidationErrors, 0, len(verrs))
+	for _, fe := range verrs {
+		out = append(out, FieldError{Field: field, Message: message(fe)})
+	}
+	return out
+}
+
+func newValidator(accountNumbers accountno.Config) *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	// 錯誤訊息使用 JSON 欄位名稱
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	v.RegisterValidation("dnonzero", func(fl validator.FieldLevel) bool {
+		d, ok := fl.Field().Interface().(decimal.Decimal)
+		return ok && !d.IsZero()
+	})
+	v.RegisterValidation("dpositive", func(fl validator.FieldLevel) bool {
+		d, ok := fl.Field().Interface().(decimal.Decimal)
+		return ok && d.IsPositive()
+	})
+	v.RegisterValidation("dnonnegative", func(fl validator.FieldLevel) bool {
+		d, ok := fl.Field().Interface().(decimal.Decimal)
+		return ok && !d.IsNegative()
+	})
+	v.RegisterValidation("dscale", func(fl validator.FieldLevel) bool {
+		d, ok := fl.Field().Interface().(decimal.Decimal)
+		if !ok {
+			return false
+		}
+		var scale int32
+		if _, err := fmt.Sscan(fl.Param(), &scale); err != nil {
+			return false
+		}
+		return d.Equal(d.Truncate(scale))
+	})
+	v.RegisterValidation("dmaxabs", func(fl validator.FieldLevel) bool {
+		d, ok := fl.Field().Interface().(decimal.Decimal)
+		return ok && d.Abs().Cmp(MaxAmount) <= 0
+	})
+	// 資料庫流水號：僅接受可放入 BIGINT 的正整數 (不含正負號與小數點)
+	v.RegisterValidation("id", func(fl validator.FieldLevel) bool {
+		s := fl.Field().String()
+		if s == "" || s[0] < '1' || s[0] > '9' {
+			return false
+		}
+		_, err := strconv.ParseInt(s, 10, 64)
+		return err == nil
+	})
+	v.RegisterValidation("account_number", func(fl validator.FieldLevel) bool {
+		return accountNumbers.Valid(fl.Field().String())
+	})
+	v.RegisterStructValidation(validateTransfer, TransferRequest{})
+	return v
+}
+
+const accountNumberMessage = "is not a valid account number"
+
+// 將 validator 的錯誤轉為可讀訊息
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_without":
+		return "is required"
+	case "max":
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("must contain at most %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s characters", fe.Param())
+	case "id":
+		return "must be a positive integer"
+	case "uuid":
+		return "must be a valid UUID"
+	case "unique":
+		return "must not contain duplicates"
+	case "email":
+		return "must be a valid email address"
+	case "datetime":
+		return "must be a date in YYYY-MM-DD format"
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "account_number":
+		return accountNumberMessage
+	case "distinct":
+		return "must be different from " + fe.Param()
+	case "excluded_with":
+		return "cannot be used together with " + fe.Param()
+	case "dnonzero":
+		return "cannot be zero"
+	case "dpositive":
+		return "must be greater than zero"
+	case "dnonnegative":
+		return "cannot be negative"
+	case "dscale":
+		return fmt.Sprintf("must have at most %s decimal places", fe.Param())
+	case "dmaxabs":
+		return "must not exceed " + MaxAmount.String() + " in magnitude"
+	}
+	return "failed on " + fe.Tag() + " validation"
+}
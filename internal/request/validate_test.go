@@ -0,0 +1,131 @@
+package request
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/accountno"
+)
+
+var v = NewValidator(accountno.DefaultConfig())
+
+// 單元測試 建立帳號請求驗證
+func TestDecode_CreateAccount(t *testing.T) {
+	var req CreateAccountRequest
+	err := v.Decode(strings.NewReader(`{"name":"Alice","balance":"100.50"}`), &req)
+	assert.NoError(t, err)
+	assert.Equal(t, "100.5", req.Balance.String())
+
+	// 名稱為空 + 小數位數過多，應一次回傳所有錯誤
+	err = v.Decode(strings.NewReader(`{"name":"","balance":1.234}`), &req)
+	assert.Equal(t, ValidationErrors{
+		{Field: "name", Message: "is required"},
+		{Field: "balance", Message: "must have at most 2 decimal places"},
+	}, err)
+
+	// 指定客戶時可省略名稱
+	var joint CreateAccountRequest
+	err = v.Decode(strings.NewReader(`{"customer_ids":["0b6f4c8e-5c1a-4e0e-9d7a-6a1f3e2b7c05","0b6f4c8e-5c1a-4e0e-9d7a-6a1f3e2b7c06"]}`), &joint)
+	assert.NoError(t, err)
+	err = v.Decode(strings.NewReader(`{"customer_ids":["0b6f4c8e-5c1a-4e0e-9d7a-6a1f3e2b7c05","0b6f4c8e-5c1a-4e0e-9d7a-6a1f3e2b7c05"]}`), &CreateAccountRequest{})
+	assert.Equal(t, ValidationErrors{{Field: "customer_ids", Message: "must not contain duplicates"}}, err)
+	// 客戶 ID 為 UUID，不接受流水號
+	err = v.Decode(strings.NewReader(`{"customer_ids":["5"]}`), &CreateAccountRequest{})
+	assert.Equal(t, ValidationErrors{{Field: "customer_ids[0]", Message: "must be a valid UUID"}}, err)
+
+	// 名稱超過 VARCHAR(100)
+	err = v.Decode(strings.NewReader(`{"name":"`+strings.Repeat("a", 101)+`","balance":0}`), &req)
+	assert.IsType(t, ValidationErrors{}, err)
+}
+
+// 單元測試 拒絕未知欄位
+func TestDecode_UnknownField(t *testing.T) {
+	var req TransactionRequest
+	err := v.Decode(strings.NewReader(`{"amount":10,"currency":"TWD"}`), &req)
+	assert.NotErrorAs(t, err, new(ValidationErrors))
+	assert.Contains(t, err.Error(), "unknown field")
+}
+
+// 單元測試 交易金額驗證
+func TestDecode_Transaction(t *testing.T) {
+	cases := map[string]string{
+		`{"amount":0}`:              "cannot be zero",
+		`{"amount":99999999999999}`: "must not exceed 9999999999999.99 in magnitude",
+		`{"amount":"-0.001"}`:       "must have at most 2 decimal places",
+	}
+	for body, msg := range cases {
+		var req TransactionRequest
+		err := v.Decode(strings.NewReader(body), &req)
+		verrs, ok := err.(ValidationErrors)
+		if assert.True(t, ok, body) {
+			assert.Equal(t, msg, verrs[0].Message, body)
+		}
+	}
+}
+
+// 單元測試 轉帳請求驗證
+func TestDecode_Transfer(t *testing.T) {
+	var req TransferRequest
+	err := v.Decode(strings.NewReader(`{"from_account":"100000000016","to_account":"100000000024","amount":10}`), &req)
+	assert.NoError(t, err)
+
+	err = v.Decode(strings.NewReader(`{"from_account":"100000000016","to_account":"100000000016","amount":10}`), &req)
+	assert.Equal(t, ValidationErrors{{Field: "to_account", Message: "must be different from from_account"}}, err)
+
+	// 檢查碼錯誤 (最後一位打錯)
+	err = v.Decode(strings.NewReader(`{"from_account":"100000000017","to_account":"100000000024","amount":10}`), &req)
+	assert.Equal(t, ValidationErrors{{Field: "from_account", Message: "is not a valid account number"}}, err)
+
+	err = v.Decode(strings.NewReader(`{"from_account":"abc","to_account":"","amount":-5}`), &req)
+	verrs, ok := err.(ValidationErrors)
+	assert.True(t, ok)
+	assert.Len(t, verrs, 3)
+
+	// 以收款人 ID 指定轉入帳號，與 to_account 擇一
+	var byPayee TransferRequest
+	assert.NoError(t, v.Decode(strings.NewReader(`{"from_account":"100000000016","payee_id":"7","amount":10}`), &byPayee))
+	var both TransferRequest
+	err = v.Decode(strings.NewReader(`{"from_account":"100000000016","to_account":"100000000024","payee_id":"7","amount":10}`), &both)
+	assert.Equal(t, ValidationErrors{{Field: "payee_id", Message: "cannot be used together with to_account"}}, err)
+	// 收款人 ID 必須為正整數，負數、小數與前導 0 皆不接受
+	for _, id := range []string{"-1", "1.5", "+7", "07", "0", "99999999999999999999"} {
+		var bad TransferRequest
+		err = v.Decode(strings.NewReader(`{"from_account":"100000000016","payee_id":"`+id+`","amount":10}`), &bad)
+		assert.Equal(t, ValidationErrors{{Field: "payee_id", Message: "must be a positive integer"}}, err, id)
+	}
+
+	assert.NoError(t, v.ValidateAccountNumber("id", "100000000032"))
+	assert.Equal(t, ValidationErrors{{Field: "id", Message: "is not a valid account number"}}, v.ValidateAccountNumber("id", "1"))
+
+	// 依建立驗證器時的設定檢查，不同前綴的號碼不通過
+	prefixed := NewValidator(accountno.Config{Scheme: accountno.SchemeLuhn, Prefix: "9", Length: 12})
+	assert.Error(t, prefixed.ValidateAccountNumber("id", "100000000032"))
+	err = prefixed.Decode(strings.NewReader(`{"from_account":"100000000016","to_account":"100000000024","amount":10}`), &req)
+	assert.Len(t, err, 2)
+}
+
+// 單元測試 query string 解析 (金額與列舉)
+func TestDecodeQuery_FeePreview(t *testing.T) {
+	var req FeePreviewRequest
+	err := v.DecodeQuery(url.Values{"operation": {"transfer"}, "amount": {"150.25"}}, &req)
+	assert.NoError(t, err)
+	assert.Equal(t, "150.25", req.Amount.String())
+
+	err = v.DecodeQuery(url.Values{"operation": {"deposit"}, "amount": {"abc"}}, &req)
+	assert.Equal(t, ValidationErrors{{Field: "amount", Message: "must be a decimal number"}}, err)
+
+	err = v.DecodeQuery(url.Values{"operation": {"deposit"}, "amount": {"10"}}, &req)
+	assert.Equal(t, ValidationErrors{{Field: "operation", Message: "must be one of withdrawal, transfer"}}, err)
+}
+
+// 單元測試 query string 解析 (布林)
+func TestDecodeQuery_Bool(t *testing.T) {
+	var req AccountImportQueryRequest
+	assert.NoError(t, v.DecodeQuery(url.Values{"dry_run": {"true"}}, &req))
+	assert.True(t, req.DryRun)
+
+	err := v.DecodeQuery(url.Values{"dry_run": {"maybe"}}, &req)
+	assert.Equal(t, ValidationErrors{{Field: "dry_run", Message: "must be true or false"}}, err)
+}
@@ -0,0 +1,17 @@
+package request
+
+// WebhookSubscriptionRequest 建立或更新 webhook 訂閱
+type WebhookSubscriptionRequest struct {
+	URL           string   `json:"url" validate:"required,http_url,max=2048"`
+	EventTypes    []string `json:"event_types" validate:"dive,oneof=AccountCreated Deposited Withdrawn TransferCompleted"`
+	AccountNumber string   `json:"account_number" validate:"omitempty,account_number"` // 只接收此帳號的事件
+	Secret        string   `json:"secret" validate:"omitempty,min=16,max=128"`         // 未指定時自動產生 (更新時沿用原本的)
+	Active        *bool    `json:"active"`                                             // 未指定時為 true
+}
+
+// WebhookDeliveryQueryRequest 查詢投遞紀錄的 query string 參數
+type WebhookDeliveryQueryRequest struct {
+	Status  string `json:"status" validate:"omitempty,oneof=pending delivered dead"`
+	AfterID int64  `json:"after_id" validate:"gte=0"`
+	Limit   int    `json:"limit" validate:"gte=0,lte=500"`
+}
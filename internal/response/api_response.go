@@ -9,6 +9,7 @@ type ApiResponse struct {
 	Status string      `json:"status"`
 	Data   interface{} `json:"data,omitempty"`
 	Error  string      `json:"error,omitempty"`
+	Errors interface{} `json:"errors,omitempty"`
 }
 
 // 成功
@@ -30,3 +31,25 @@ func WriteError(w http.ResponseWriter, statusCode int, errMsg string) {
 		Error:  errMsg,
 	})
 }
+
+// 錯誤並附帶資料 (例如各項檢查結果)
+func WriteErrorData(w http.ResponseWriter, statusCode int, errMsg string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(ApiResponse{
+		Status: "error",
+		Data:   data,
+		Error:  errMsg,
+	})
+}
+
+// 驗證錯誤 (422)，一次回傳所有欄位錯誤
+func WriteValidationError(w http.ResponseWriter, errs interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	_ = json.NewEncoder(w).Encode(ApiResponse{
+		Status: "error",
+		Error:  "validation failed",
+		Errors: errs,
+	})
+}
@@ -0,0 +1,43 @@
+package route
+
+import (
+	"context"
+	"net/http"
+)
+
+// ServeMux 只在傳給路由 handler 的 *http.Request 副本上設定 Pattern，
+// 外層 middleware (以及中間以 r.WithContext 傳遞副本的 middleware) 看不到。
+// 因此由外層 middleware 以 Track 在 context 放入可寫入的 holder，路由的 handler 以 Record 填入 pattern。
+
+type holder struct {
+	pattern string
+}
+
+type ctxKey struct{}
+
+// Track 確保請求的 context 帶有 holder，外層已建立時沿用同一個
+// middleware 應將回傳的請求往下傳，並在 next.ServeHTTP 之後以 Pattern 取得路由
+func Track(r *http.Request) *http.Request {
+	if _, ok := r.Context().Value(ctxKey{}).(*holder); ok {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), ctxKey{}, &holder{}))
+}
+
+// Record 包裝路由的 handler，將 ServeMux 比對到的 pattern 寫入外層的 holder
+func Record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h, ok := r.Context().Value(ctxKey{}).(*holder); ok {
+			h.pattern = r.Pattern
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Pattern 路由比對到的 pattern (例如 "GET /accounts/{id}")，未比對到路由時為空字串
+func Pattern(r *http.Request) string {
+	if h, ok := r.Context().Value(ctxKey{}).(*holder); ok {
+		return h.pattern
+	}
+	return r.Pattern
+}
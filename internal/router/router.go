@@ -5,20 +5,101 @@ import (
 
 	httpSwagger "github.com/swaggo/http-swagger"
 	"github.com/yoyo0827/simple-bank-system/internal/api"
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/config"
+	"github.com/yoyo0827/simple-bank-system/internal/metrics"
+	"github.com/yoyo0827/simple-bank-system/internal/ratelimit"
+	"github.com/yoyo0827/simple-bank-system/internal/route"
 )
 
-func NewRouter(handler *api.ApiHandler) *http.ServeMux {
+// Handlers 所有路由使用的 handler
+type Handlers struct {
+	Account        *api.ApiHandler
+	Health         *api.HealthHandler
+	Audit          *api.AuditHandler
+	Webhook        *api.WebhookHandler
+	Stream         *api.StreamHandler
+	Reconciliation *api.ReconciliationHandler
+	Transfer       *api.PendingTransferHandler
+	Customer       *api.CustomerHandler
+	Payee          *api.PayeeHandler
+	BusinessDay    *api.BusinessDayHandler
+	Report         *api.ReportHandler
+	Limiter        *ratelimit.Limiter // nil 代表不限制
+}
+
+func NewRouter(h Handlers, features config.FeatureConfig) *http.ServeMux {
 	mux := http.NewServeMux()
+	// 所有路由皆記錄比對到的 pattern，供外層的 tracing、access log 與 metrics 使用
+	register := func(pattern string, handler http.Handler) {
+		mux.Handle(pattern, route.Record(handler))
+	}
+	// 需認證的路由，依 pattern 套用 rate limit
+	handle := func(pattern, role string, fn http.HandlerFunc) {
+		register(pattern, auth.Require(role, h.Limiter.Wrap(pattern, fn)))
+	}
+
+	// 健康檢查
+	register("GET /healthz", http.HandlerFunc(h.Health.Liveness))
+	register("GET /readyz", http.HandlerFunc(h.Health.Readiness))
+	register("GET /version", http.HandlerFunc(h.Health.Version))
 
 	// 路由定義
-	mux.HandleFunc("POST /accounts", handler.CreateAccount)
-	mux.HandleFunc("GET /accounts/{id}", handler.FindAccount)
-	mux.HandleFunc("POST /accounts/{id}/transactions", handler.CreateTransaction)
-	mux.HandleFunc("POST /accounts/transfer", handler.CreateTransfer)
-	mux.HandleFunc("GET /accounts/{id}/transactions", handler.FindTransactionDetail)
+	handle("POST /accounts", auth.RoleUser, h.Account.CreateAccount)
+	handle("GET /accounts/{id}", auth.RoleUser, h.Account.FindAccount)
+	handle("POST /accounts/{id}/transactions", auth.RoleUser, h.Account.CreateTransaction)
+	handle("POST /accounts/transfer", auth.RoleUser, h.Account.CreateTransfer)
+	handle("GET /accounts/{id}/transactions", auth.RoleUser, h.Account.FindTransactionDetail)
+	handle("GET /accounts/{id}/fees/preview", auth.RoleUser, h.Account.PreviewFee)
+	handle("GET /accounts/{id}/statement", auth.RoleUser, h.Account.Statement)
+	handle("GET /accounts/{id}/balance", auth.RoleUser, h.Account.BalanceAt)
+	handle("GET /accounts/{id}/events", auth.RoleUser, h.Stream.StreamAccountEvents)
+	handle("GET /accounts/{id}/payees", auth.RoleUser, h.Payee.FindPayees)
+	handle("POST /accounts/{id}/payees", auth.RoleUser, h.Payee.AddPayee)
+	handle("PUT /accounts/{id}/payees/{payee_id}", auth.RoleUser, h.Payee.UpdatePayee)
+	handle("DELETE /accounts/{id}/payees/{payee_id}", auth.RoleUser, h.Payee.DeletePayee)
+	handle("POST /pending-transfers/{id}/approve", auth.RoleUser, h.Transfer.ApprovePendingTransfer)
+	handle("POST /pending-transfers/{id}/reject", auth.RoleUser, h.Transfer.RejectPendingTransfer)
+
+	// 總帳報表
+	handle("GET /reports/trial-balance", auth.RoleAdmin, h.Report.TrialBalance)
+	handle("GET /reports/gl-summary", auth.RoleAdmin, h.Report.GLSummary)
+
+	// 管理功能
+	handle("POST /admin/accounts/import", auth.RoleAdmin, h.Account.ImportAccounts)
+	handle("POST /admin/accounts/{id}/freeze", auth.RoleAdmin, h.Account.FreezeAccount)
+	handle("POST /admin/accounts/{id}/unfreeze", auth.RoleAdmin, h.Account.UnfreezeAccount)
+	handle("POST /admin/customers", auth.RoleAdmin, h.Customer.CreateCustomer)
+	handle("GET /admin/customers/{id}", auth.RoleAdmin, h.Customer.FindCustomer)
+	handle("POST /admin/customers/{id}/kyc", auth.RoleAdmin, h.Customer.SetKYCStatus)
+	handle("GET /admin/accounts/{id}/holders", auth.RoleAdmin, h.Customer.FindAccountHolders)
+	handle("POST /admin/accounts/{id}/holders", auth.RoleAdmin, h.Customer.AddAccountHolder)
+	handle("GET /admin/reconciliation", auth.RoleAdmin, h.Reconciliation.Reconcile)
+	handle("GET /admin/business-days", auth.RoleAdmin, h.BusinessDay.BusinessDayStatus)
+	handle("POST /admin/business-days/{date}/close", auth.RoleAdmin, h.BusinessDay.CloseBusinessDate)
+	handle("GET /admin/business-days/{date}/balances", auth.RoleAdmin, h.BusinessDay.FindEODBalances)
+	handle("GET /admin/pending-transfers", auth.RoleAdmin, h.Transfer.FindPendingTransfers)
+	handle("GET /admin/pending-transfers/{id}", auth.RoleAdmin, h.Transfer.FindPendingTransfer)
+	handle("GET /admin/fraud/decisions", auth.RoleAdmin, h.Transfer.FindFraudDecisions)
+	handle("GET /admin/audit", auth.RoleAdmin, h.Audit.FindAuditEvents)
+	handle("GET /admin/audit/verify", auth.RoleAdmin, h.Audit.VerifyAuditChain)
+	handle("POST /admin/webhooks", auth.RoleAdmin, h.Webhook.CreateWebhook)
+	handle("GET /admin/webhooks", auth.RoleAdmin, h.Webhook.ListWebhooks)
+	handle("GET /admin/webhooks/{id}", auth.RoleAdmin, h.Webhook.FindWebhook)
+	handle("PUT /admin/webhooks/{id}", auth.RoleAdmin, h.Webhook.UpdateWebhook)
+	handle("DELETE /admin/webhooks/{id}", auth.RoleAdmin, h.Webhook.DeleteWebhook)
+	handle("GET /admin/webhooks/{id}/deliveries", auth.RoleAdmin, h.Webhook.FindWebhookDeliveries)
+	handle("POST /admin/webhooks/deliveries/{id}/redeliver", auth.RoleAdmin, h.Webhook.RedeliverWebhook)
+
+	// Prometheus metrics
+	if features.Metrics {
+		register("GET /metrics", metrics.Handler())
+	}
 
 	// Swagger UI
-	mux.Handle("/swagger/", httpSwagger.WrapHandler)
+	if features.Swagger {
+		register("/swagger/", httpSwagger.WrapHandler)
+	}
 
 	return mux
 }
@@ -0,0 +1,189 @@
+// Package seed 產生示範與壓測用的帳號與交易紀錄
+// 相同的 seed 會產生相同的帳號與交易順序，資料一律透過 AccountService 寫入，
+// 因此稽核紀錄、outbox 事件與帳務核對皆與正式流量一致
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/shopspring/decimal"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/fee"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+)
+
+// 操作類型
+const (
+	OpDeposit  = "deposit"
+	OpWithdraw = "withdraw"
+	OpTransfer = "transfer"
+)
+
+var (
+	firstNames = []string{"Alice", "Bob", "Carol", "David", "Emma", "Frank", "Grace", "Henry", "Ivy", "Jack", "Kevin", "Lily"}
+	lastNames  = []string{"Chen", "Lin", "Huang", "Chang", "Lee", "Wang", "Wu", "Liu", "Tsai", "Yang"}
+)
+
+// ErrKYCEnabled 啟用 KYC 時開戶需指定已驗證的客戶，seeder 無法建立帳號
+var ErrKYCEnabled = errors.New("seeding requires kyc to be disabled")
+
+// Options 產生資料的參數
+type Options struct {
+	Accounts     int         // 帳號數
+	Transactions int         // 每個帳號平均的交易筆數
+	Seed         uint64      // 亂數種子
+	Fees         *fee.Engine // 提款與轉帳的手續費 (nil 代表不收費)，計入追蹤的餘額
+}
+
+// Plan 依 Options 產生的資料，帳號與操作皆以索引表示
+type Plan struct {
+	Accounts []Account
+	Ops      []Op
+}
+
+// Account 待建立的帳號
+type Account struct {
+	Name    string
+	Balance decimal.Decimal
+}
+
+// Op 單筆交易，To 僅用於轉帳
+type Op struct {
+	Kind    string
+	Account int
+	To      int
+	Amount  decimal.Decimal
+}
+
+// Result 寫入結果
+// 覆核、風控與收款人上限會讓實際執行的結果與 Plan 不同：暫緩執行的轉帳記於 Held，
+// 被拒絕的轉帳記於 Blocked，因保留款項而可用餘額不足的提款與轉帳記於 Skipped
+type Result struct {
+	Seed           uint64   `json:"seed"`
+	AccountNumbers []string `json:"account_numbers"`
+	Transactions   int      `json:"transactions"`
+	Held           int      `json:"held"`
+	Blocked        int      `json:"blocked"`
+	Skipped        int      `json:"skipped"`
+}
+
+// NewPlan 產生固定的帳號與交易順序，並追蹤餘額 (含手續費) 確保提款與轉帳不會透支
+func NewPlan(opts Options) *Plan {
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x5eed))
+	plan := &Plan{Accounts: make([]Account, opts.Accounts)}
+	balances := make([]decimal.Decimal, opts.Accounts)
+	for i := range plan.Accounts {
+		name := fmt.Sprintf("%s %s %d", firstNames[rng.IntN(len(firstNames))], lastNames[rng.IntN(len(lastNames))], i+1)
+		balances[i] = cents(rng, 0, 500000)
+		plan.Accounts[i] = Account{Name: name, Balance: balances[i]}
+	}
+	if opts.Accounts == 0 {
+		return plan
+	}
+
+	for range opts.Accounts * opts.Transactions {
+		op := Op{Account: rng.IntN(opts.Accounts)}
+		switch n := rng.IntN(100); {
+		case n < 30:
+			op.Kind = OpWithdraw
+		case n < 55 && opts.Accounts > 1:
+			op.Kind = OpTransfer
+			op.To = (op.Account + 1 + rng.IntN(opts.Accounts-1)) % opts.Accounts
+		default:
+			op.Kind = OpDeposit
+		}
+		amount := cents(rng, 1, 50000)
+		op.Amount = amount
+		var charge decimal.Decimal
+		if op.Kind != OpDeposit {
+			// 餘額不足時改為提領 / 轉出扣除手續費後的部分餘額，不足以支付手續費時改為存款
+			charge = quote(opts.Fees, op.Kind, op.Amount)
+			if balance := balances[op.Account]; op.Amount.Add(charge).GreaterThan(balance) {
+				op.Amount = balance.Sub(quote(opts.Fees, op.Kind, decimal.Min(op.Amount, balance)))
+				charge = quote(opts.Fees, op.Kind, op.Amount)
+				if !op.Amount.IsPositive() || op.Amount.Add(charge).GreaterThan(balance) {
+					op.Kind, op.Amount, charge = OpDeposit, amount, decimal.Zero
+				}
+			}
+		}
+		switch op.Kind {
+		case OpDeposit:
+			balances[op.Account] = balances[op.Account].Add(op.Amount)
+		case OpWithdraw:
+			balances[op.Account] = balances[op.Account].Sub(op.Amount).Sub(charge)
+		case OpTransfer:
+			balances[op.Account] = balances[op.Account].Sub(op.Amount).Sub(charge)
+			balances[op.To] = balances[op.To].Add(op.Amount)
+		}
+		plan.Ops = append(plan.Ops, op)
+	}
+	return plan
+}
+
+// 新帳號 (預設產品) 的手續費
+func quote(fees *fee.Engine, kind string, amount decimal.Decimal) decimal.Decimal {
+	op := fee.OpTransfer
+	if kind == OpWithdraw {
+		op = fee.OpWithdrawal
+	}
+	return fees.Quote(op, domain.ProductStandard, amount).Fee
+}
+
+// 產生 [lo, hi] 分之間的金額
+func cents(rng *rand.Rand, lo, hi int64) decimal.Decimal {
+	return decimal.New(lo+rng.Int64N(hi-lo+1), -2)
+}
+
+// Seeder 透過 AccountService 寫入 Plan
+type Seeder struct {
+	AccountService *service.AccountService
+}
+
+// Run 依序建立帳號並執行交易，啟用 KYC 時回傳 ErrKYCEnabled
+func (s *Seeder) Run(ctx context.Context, plan *Plan) (*Result, error) {
+	res := &Result{}
+	if s.AccountService.KYC.Enabled {
+		return res, ErrKYCEnabled
+	}
+	ids := make([]string, len(plan.Accounts))
+	for i, a := range plan.Accounts {
+		acc, err := s.AccountService.CreateAccount(ctx, &request.CreateAccountRequest{Name: a.Name, Balance: a.Balance})
+		if err != nil {
+			return res, fmt.Errorf("create account %d: %w", i+1, err)
+		}
+		ids[i] = acc.ID
+		res.AccountNumbers = append(res.AccountNumbers, acc.Number)
+	}
+	for i, op := range plan.Ops {
+		id := ids[op.Account]
+		var err error
+		var t *domain.TransferResult
+		switch op.Kind {
+		case OpDeposit:
+			_, err = s.AccountService.CreateTransaction(ctx, id, &request.TransactionRequest{Amount: op.Amount})
+		case OpWithdraw:
+			_, err = s.AccountService.CreateTransaction(ctx, id, &request.TransactionRequest{Amount: op.Amount.Neg()})
+		case OpTransfer:
+			t, err = s.AccountService.Transfer(ctx, &request.TransferRequest{
+				FromAccount: res.AccountNumbers[op.Account], ToAccount: res.AccountNumbers[op.To], Amount: op.Amount,
+			})
+		}
+		switch {
+		case errors.Is(err, service.ErrTransferBlocked), errors.Is(err, service.ErrPayeeLimitExceeded):
+			res.Blocked++
+		case errors.Is(err, service.ErrInsufficientFunds):
+			res.Skipped++
+		case err != nil:
+			return res, fmt.Errorf("op %d (%s): %w", i+1, op.Kind, err)
+		case t != nil && t.Status != domain.TransferStatusCompleted:
+			res.Held++
+		default:
+			res.Transactions++
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,88 @@
+package seed
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/fee"
+	"github.com/yoyo0827/simple-bank-system/internal/kyc"
+	"github.com/yoyo0827/simple-bank-system/internal/service"
+)
+
+// 單元測試 NewPlan：相同 seed 產生相同資料，不同 seed 產生不同資料
+func TestNewPlan_Deterministic(t *testing.T) {
+	opts := Options{Accounts: 20, Transactions: 10, Seed: 42}
+	a, b := NewPlan(opts), NewPlan(opts)
+	assert.Equal(t, a, b)
+	assert.Len(t, a.Accounts, 20)
+	assert.Len(t, a.Ops, 200)
+
+	opts.Seed = 43
+	assert.NotEqual(t, a, NewPlan(opts))
+}
+
+// 單元測試 NewPlan：依序執行後任何帳號餘額都不會為負，轉帳不會轉給自己
+func TestNewPlan_NeverOverdraws(t *testing.T) {
+	plan := NewPlan(Options{Accounts: 5, Transactions: 200, Seed: 7})
+	balances := make([]decimal.Decimal, len(plan.Accounts))
+	for i, a := range plan.Accounts {
+		balances[i] = a.Balance
+	}
+	for _, op := range plan.Ops {
+		assert.True(t, op.Amount.IsPositive())
+		switch op.Kind {
+		case OpDeposit:
+			balances[op.Account] = balances[op.Account].Add(op.Amount)
+		case OpWithdraw:
+			balances[op.Account] = balances[op.Account].Sub(op.Amount)
+		case OpTransfer:
+			assert.NotEqual(t, op.Account, op.To)
+			balances[op.Account] = balances[op.Account].Sub(op.Amount)
+			balances[op.To] = balances[op.To].Add(op.Amount)
+		}
+		assert.False(t, balances[op.Account].IsNegative())
+	}
+}
+
+func TestNewPlan_SingleAccountHasNoTransfers(t *testing.T) {
+	for _, op := range NewPlan(Options{Accounts: 1, Transactions: 100, Seed: 1}).Ops {
+		assert.NotEqual(t, OpTransfer, op.Kind)
+	}
+}
+
+// 單元測試 NewPlan：啟用手續費時，扣除手續費後的餘額也不會為負
+func TestNewPlan_Fees(t *testing.T) {
+	fees := fee.NewEngine(fee.Config{Enabled: true, RevenueAccount: "999", Schedules: []fee.Schedule{
+		{Name: "withdrawal", Operation: fee.OpWithdrawal, Flat: decimal.NewFromInt(15)},
+		{Name: "transfer", Operation: fee.OpTransfer, Percent: decimal.NewFromInt(1), Min: decimal.NewFromInt(10)},
+	}})
+	plan := NewPlan(Options{Accounts: 5, Transactions: 200, Seed: 7, Fees: fees})
+	balances := make([]decimal.Decimal, len(plan.Accounts))
+	for i, a := range plan.Accounts {
+		balances[i] = a.Balance
+	}
+	for _, op := range plan.Ops {
+		assert.True(t, op.Amount.IsPositive())
+		switch op.Kind {
+		case OpDeposit:
+			balances[op.Account] = balances[op.Account].Add(op.Amount)
+		case OpWithdraw:
+			balances[op.Account] = balances[op.Account].Sub(op.Amount).Sub(fees.Quote(fee.OpWithdrawal, domain.ProductStandard, op.Amount).Fee)
+		case OpTransfer:
+			balances[op.Account] = balances[op.Account].Sub(op.Amount).Sub(fees.Quote(fee.OpTransfer, domain.ProductStandard, op.Amount).Fee)
+			balances[op.To] = balances[op.To].Add(op.Amount)
+		}
+		assert.False(t, balances[op.Account].IsNegative())
+	}
+}
+
+// 單元測試 Seeder：啟用 KYC 時不建立任何帳號
+func TestSeeder_RefusesWithKYC(t *testing.T) {
+	s := &Seeder{AccountService: &service.AccountService{KYC: kyc.Config{Enabled: true}}}
+	res, err := s.Run(context.Background(), NewPlan(Options{Accounts: 2, Transactions: 1, Seed: 1}))
+	assert.ErrorIs(t, err, ErrKYCEnabled)
+	assert.Empty(t, res.AccountNumbers)
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/config"
+)
+
+// Server 包裝 http.Server，提供逾時設定、body 大小限制、TLS 與 graceful shutdown
+type Server struct {
+	cfg        config.ServerConfig
+	httpServer *http.Server
+	onDrain    []func()
+}
+
+func New(cfg config.ServerConfig, handler http.Handler) *Server {
+	return &Server{
+		cfg: cfg,
+		httpServer: &http.Server{
+			Addr:              cfg.Addr(),
+			Handler:           limitBody(handler, cfg.MaxBodyBytes),
+			ReadTimeout:       cfg.ReadTimeout,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+			MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		},
+	}
+}
+
+// OnDrain 註冊關閉開始時要執行的函式 (例如讓 readiness 失敗)
+func (s *Server) OnDrain(fn func()) {
+	s.onDrain = append(s.onDrain, fn)
+}
+
+// Run 啟動 server，直到 ctx 結束 (收到 SIGTERM) 後停止接收新連線，
+// 並等待進行中的請求 (例如轉帳) 完成後才返回
+func (s *Server) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.listen()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	// 先讓 readiness 失敗，等待 load balancer 停止導入流量後才停止接收新連線
+	for _, fn := range s.onDrain {
+		fn()
+	}
+	if s.cfg.DrainDelay > 0 {
+		slog.Info("draining before shutdown", "delay", s.cfg.DrainDelay.String())
+		time.Sleep(s.cfg.DrainDelay)
+	}
+
+	slog.Info("shutting down server, draining in-flight requests")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; err != nil {
+		return err
+	}
+	slog.Info("server stopped")
+	return nil
+}
+
+func (s *Server) listen() error {
+	var err error
+	if s.cfg.TLSCertFile != "" {
+		reloader, rerr := newCertReloader(s.cfg.TLSCertFile, s.cfg.TLSKeyFile)
+		if rerr != nil {
+			return rerr
+		}
+		s.httpServer.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: reloader.GetCertificate,
+		}
+		slog.Info("server running", "addr", s.cfg.Addr(), "tls", true)
+		err = s.httpServer.ListenAndServeTLS("", "")
+	} else {
+		slog.Info("server running", "addr", s.cfg.Addr(), "tls", false)
+		err = s.httpServer.ListenAndServe()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// 限制 request body 大小
+func limitBody(next http.Handler, maxBytes int64) http.Handler {
+	if maxBytes <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/yoyo0827/simple-bank-system/internal/csvimport"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrImportInvalid 匯入檔有驗證失敗的列，沒有建立任何帳號
+var ErrImportInvalid = errors.New("import file has invalid rows")
+
+// ErrImportIncomplete 驗證通過後開戶中途失敗，之前的批次已建立
+var ErrImportIncomplete = errors.New("account import incomplete")
+
+// 匯入結果列出的錯誤上限，避免整個檔案格式錯誤時回應過大
+const maxImportErrors = 1000
+
+// 匯入時每個 transaction 建立的帳號數，批次之間釋放稽核紀錄的鎖
+const importBatchSize = 500
+
+// 從 CSV 匯入帳號與期初餘額
+// 先將整個檔案寫入暫存檔並逐列驗證 (含客戶與 KYC)，此時不開啟 transaction；有驗證失敗的列時回傳 ErrImportInvalid
+// 全部通過且非 dryRun 時再由暫存檔讀取，每 importBatchSize 筆一個 transaction 開戶，不會在等待上傳時持有鎖
+// 有 external_id 的列會記錄於帳號，中途失敗後重新匯入同一檔案時略過已建立的列
+// 標題列錯誤回傳 csvimport.ErrInvalidHeader，開戶中途失敗回傳 ErrImportIncomplete (結果列出已建立的帳號)
+func (s *AccountService) ImportAccounts(ctx context.Context, r io.Reader, dryRun bool) (_ *domain.AccountImport, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.ImportAccounts")
+	defer func() { tracing.End(span, err) }()
+
+	spool, err := os.CreateTemp("", "account-import-*.csv")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}()
+
+	v := request.NewValidator(s.AccountNumbers)
+	result, err := s.validateImport(ctx, v, io.TeeReader(r, spool), dryRun)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("bank.import_rows", result.Rows), attribute.Int("bank.import_invalid", result.Invalid))
+	if dryRun {
+		return result, nil
+	}
+	if result.Invalid > 0 {
+		return result, ErrImportInvalid
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	rows, err := csvimport.NewReader(spool, v)
+	if err != nil {
+		return nil, err
+	}
+	for done := false; !done; {
+		if done, err = s.importBatch(ctx, rows, result); err != nil {
+			return result, fmt.Errorf("%w after %d accounts: %w", ErrImportIncomplete, len(result.Accounts), err)
+		}
+	}
+	result.Committed = true
+	slog.InfoContext(ctx, "accounts imported",
+		"accounts", len(result.Accounts),
+		"total_opening_balance", result.TotalOpeningBalance.String(),
+	)
+	return result, nil
+}
+
+// 逐列驗證匯入檔，客戶與 KYC 以 s.DB 查詢
+func (s *AccountService) validateImport(ctx context.Context, v *request.Validator, r io.Reader, dryRun bool) (*domain.AccountImport, error) {
+	rows, err := csvimport.NewReader(r, v)
+	if err != nil {
+		return nil, err
+	}
+	result := &domain.AccountImport{DryRun: dryRun, Errors: []domain.AccountImportError{}, Accounts: []domain.ImportedAccount{}, Skipped: []domain.ImportedAccount{}}
+	for {
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Rows++
+		if len(row.Errors) == 0 {
+			_, err := s.newHolders(ctx, s.DB, row.Request.CustomerIDs)
+			if errors.Is(err, ErrCustomerNotFound) || errors.Is(err, ErrKYCNotVerified) {
+				row.Errors = request.ValidationErrors{{Field: csvimport.ColumnCustomerIDs, Message: err.Error()}}
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		if len(row.Errors) > 0 {
+			result.Invalid++
+			addImportErrors(result, row)
+			continue
+		}
+		result.Valid++
+		result.TotalOpeningBalance = result.TotalOpeningBalance.Add(row.Request.Balance)
+	}
+}
+
+// 在一個 transaction 內建立最多 importBatchSize 個帳號，提交後才加入結果並發佈事件
+// 讀完檔案時 done 為 true
+func (s *AccountService) importBatch(ctx context.Context, rows *csvimport.Reader, result *domain.AccountImport) (done bool, err error) {
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer transaction.Rollback()
+
+	accounts := make([]domain.ImportedAccount, 0, importBatchSize)
+	var skipped []domain.ImportedAccount
+	events := make([]*domain.Event, 0, importBatchSize)
+	for range importBatchSize {
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			done = true
+			break
+		}
+		if err != nil {
+			return false, err
+		}
+		// 先前匯入已建立的列 (例如上次匯入中途失敗前已提交的批次)
+		if row.ExternalID != "" {
+			acc, err := s.AccountRepository.FindByExternalID(ctx, transaction, row.ExternalID)
+			if err == nil {
+				skipped = append(skipped, importedAccount(row, acc))
+				continue
+			}
+			if !errors.Is(err, sql.ErrNoRows) {
+				return false, fmt.Errorf("line %d: %w", row.Line, err)
+			}
+		}
+		acc, ev, err := s.openAccount(ctx, transaction, row.Request)
+		if err != nil {
+			return false, fmt.Errorf("line %d: %w", row.Line, err)
+		}
+		if row.ExternalID != "" {
+			if err := s.AccountRepository.SetExternalID(ctx, transaction, acc.ID, row.ExternalID); err != nil {
+				return false, fmt.Errorf("line %d: %w", row.Line, err)
+			}
+		}
+		events = append(events, ev)
+		accounts = append(accounts, importedAccount(row, acc))
+	}
+	if err := transaction.Commit(); err != nil {
+		return false, err
+	}
+	result.Accounts = append(result.Accounts, accounts...)
+	result.Skipped = append(result.Skipped, skipped...)
+	for _, ev := range events {
+		s.publish(ev)
+	}
+	return done, nil
+}
+
+func importedAccount(row *csvimport.Row, acc *domain.Account) domain.ImportedAccount {
+	return domain.ImportedAccount{
+		Line:          row.Line,
+		ExternalID:    row.ExternalID,
+		AccountNumber: acc.Number,
+		Name:          acc.Name,
+		Balance:       acc.Balance,
+	}
+}
+
+// 記錄一列的錯誤，超過上限時只標記 ErrorsTruncated
+func addImportErrors(result *domain.AccountImport, row *csvimport.Row) {
+	for _, fe := range row.Errors {
+		if len(result.Errors) >= maxImportErrors {
+			result.ErrorsTruncated = true
+			return
+		}
+		result.Errors = append(result.Errors, domain.AccountImportError{
+			Line:       row.Line,
+			ExternalID: row.ExternalID,
+			Field:      fe.Field,
+			Message:    fe.Message,
+		})
+	}
+}
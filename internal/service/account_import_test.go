@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/accountno"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+)
+
+// 單元測試 匯入帳號：在同一個 transaction 內開戶，期初餘額記為存款
+func TestImportAccounts(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{},
+		AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}, CustomerRepository: &repository.CustomerRepository{},
+		AccountNumbers: accountno.DefaultConfig()}
+	today := time.Now().UTC().Format("2006-01-02")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM accounts WHERE external_id = \$1`).WithArgs("L-1").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`INSERT INTO accounts`).
+		WithArgs("Alice", "100.5", domain.ProductStandard, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	mock.ExpectQuery(`INSERT INTO transactions`).
+		WithArgs("1", 2, domain.TxKindOpening, "100.5", sqlmock.AnyArg(), "Opening balance", "", today).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectAuditEntry(mock, domain.AuditAccountCreated, "account", "1", "")
+	expectEvent(mock, domain.EventAccountCreated, sqlmock.AnyArg(), "") // 帳號號碼為亂數產生
+	mock.ExpectExec(`UPDATE accounts SET external_id`).WithArgs("1", "L-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	// 餘額為 0 時不寫入交易紀錄
+	mock.ExpectQuery(`FROM accounts WHERE external_id = \$1`).WithArgs("L-2").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`INSERT INTO accounts`).
+		WithArgs("Bob", "0", "savings", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+	expectAuditEntry(mock, domain.AuditAccountCreated, "account", "2", "")
+	expectEvent(mock, domain.EventAccountCreated, sqlmock.AnyArg(), "") // 帳號號碼為亂數產生
+	mock.ExpectExec(`UPDATE accounts SET external_id`).WithArgs("2", "L-2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := svc.ImportAccounts(context.Background(), strings.NewReader("external_id,name,balance,product\nL-1,Alice,100.50,\nL-2,Bob,0,savings\n"), false)
+	assert.NoError(t, err)
+	assert.True(t, result.Committed)
+	assert.Equal(t, 2, result.Rows)
+	assert.Equal(t, 2, result.Valid)
+	assert.Equal(t, "100.5", result.TotalOpeningBalance.String())
+	assert.Empty(t, result.Errors)
+	if assert.Len(t, result.Accounts, 2) {
+		assert.Equal(t, 2, result.Accounts[0].Line)
+		assert.Equal(t, "L-1", result.Accounts[0].ExternalID)
+		assert.True(t, svc.AccountNumbers.Valid(result.Accounts[0].AccountNumber))
+		assert.Equal(t, "L-2", result.Accounts[1].ExternalID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 dry-run 驗證所有列 (含客戶) 並回報錯誤，不開啟 transaction
+func TestImportAccounts_DryRun(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{},
+		AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}, CustomerRepository: &repository.CustomerRepository{},
+		AccountNumbers: accountno.DefaultConfig()}
+	const unknownID = "0b6f4c8e-5c1a-4e0e-9d7a-6a1f3e2b7c09"
+
+	mock.ExpectQuery(`SELECT (.+) FROM customers WHERE public_id = \$1`).
+		WithArgs(aliceID).
+		WillReturnRows(customerRows(aliceID, "Alice Wang", domain.KYCVerified))
+	mock.ExpectQuery(`SELECT (.+) FROM customers WHERE public_id = \$1`).
+		WithArgs(unknownID).
+		WillReturnError(sql.ErrNoRows)
+
+	data := "name,balance,customer_ids\n,100," + aliceID + "\nBob,abc,\nCarol,10," + unknownID + "\n"
+	result, err := svc.ImportAccounts(context.Background(), strings.NewReader(data), true)
+	assert.NoError(t, err)
+	assert.False(t, result.Committed)
+	assert.Equal(t, 3, result.Rows)
+	assert.Equal(t, 1, result.Valid)
+	assert.Equal(t, 2, result.Invalid)
+	assert.Equal(t, "100", result.TotalOpeningBalance.String())
+	assert.Equal(t, []domain.AccountImportError{
+		{Line: 3, Field: "balance", Message: "must be a decimal number"},
+		{Line: 4, Field: "customer_ids", Message: "customer not found: " + unknownID},
+	}, result.Errors)
+	assert.Empty(t, result.Accounts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 任何一列驗證失敗時不開啟 transaction，其餘的列繼續驗證
+func TestImportAccounts_InvalidRow(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{},
+		AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}, CustomerRepository: &repository.CustomerRepository{},
+		AccountNumbers: accountno.DefaultConfig()}
+
+	result, err := svc.ImportAccounts(context.Background(), strings.NewReader("name,balance\nAlice,0\n,5\nCarol,1.234\n"), false)
+	assert.ErrorIs(t, err, ErrImportInvalid)
+	assert.False(t, result.Committed)
+	assert.Equal(t, 1, result.Valid)
+	assert.Equal(t, 2, result.Invalid)
+	assert.Empty(t, result.Accounts)
+	assert.Equal(t, []domain.AccountImportError{
+		{Line: 3, Field: "name", Message: "is required"},
+		{Line: 4, Field: "balance", Message: "must have at most 2 decimal places"},
+	}, result.Errors)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 驗證通過後開戶失敗 (營業日已結帳) 時回滾該批次並回傳 ErrImportIncomplete
+func TestImportAccounts_Incomplete(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{},
+		AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}, CustomerRepository: &repository.CustomerRepository{},
+		AccountNumbers: accountno.DefaultConfig()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO accounts`).
+		WithArgs("Alice", "10", domain.ProductStandard, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	mock.ExpectQuery(`INSERT INTO transactions`).
+		WillReturnError(&pq.Error{Code: "BD001", Message: "business date is closed"})
+	mock.ExpectRollback()
+
+	result, err := svc.ImportAccounts(context.Background(), strings.NewReader("name,balance\nAlice,10\n"), false)
+	assert.ErrorIs(t, err, ErrImportIncomplete)
+	assert.ErrorIs(t, err, ErrBusinessDateClosed)
+	assert.False(t, result.Committed)
+	assert.Empty(t, result.Accounts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 開戶中途失敗後重新匯入同一檔案：先前批次已建立的列 (以 external_id 辨識) 略過，不重複開戶
+func TestImportAccounts_Retry(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{},
+		AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}, CustomerRepository: &repository.CustomerRepository{},
+		AccountNumbers: accountno.DefaultConfig()}
+	data := "external_id,name,balance\nL-1,Alice,10\nL-2,Bob,0\n"
+
+	// 第一次匯入：L-2 開戶失敗，本批次回滾
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM accounts WHERE external_id = \$1`).WithArgs("L-1").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`INSERT INTO accounts`).WithArgs("Alice", "10", domain.ProductStandard, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	mock.ExpectQuery(`INSERT INTO transactions`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectAuditEntry(mock, domain.AuditAccountCreated, "account", "1", "")
+	expectEvent(mock, domain.EventAccountCreated, sqlmock.AnyArg(), "")
+	mock.ExpectExec(`UPDATE accounts SET external_id`).WithArgs("1", "L-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`FROM accounts WHERE external_id = \$1`).WithArgs("L-2").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`INSERT INTO accounts`).WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	result, err := svc.ImportAccounts(context.Background(), strings.NewReader(data), false)
+	assert.ErrorIs(t, err, ErrImportIncomplete)
+	assert.Empty(t, result.Accounts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 重新匯入：L-1 已由先前提交的批次建立 (例如大型檔案的前一批)，略過並回傳原帳號，只建立 L-2
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM accounts WHERE external_id = \$1`).WithArgs("L-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).
+			AddRow("1", "Alice", "10", domain.ProductStandard, domain.AccountActive, "", "0", "100000000016"))
+	mock.ExpectQuery(`FROM accounts WHERE external_id = \$1`).WithArgs("L-2").WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`INSERT INTO accounts`).WithArgs("Bob", "0", domain.ProductStandard, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("2"))
+	expectAuditEntry(mock, domain.AuditAccountCreated, "account", "2", "")
+	expectEvent(mock, domain.EventAccountCreated, sqlmock.AnyArg(), "")
+	mock.ExpectExec(`UPDATE accounts SET external_id`).WithArgs("2", "L-2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err = svc.ImportAccounts(context.Background(), strings.NewReader(data), false)
+	assert.NoError(t, err)
+	assert.True(t, result.Committed)
+	assert.Equal(t, []domain.ImportedAccount{
+		{Line: 2, ExternalID: "L-1", AccountNumber: "100000000016", Name: "Alice", Balance: decimal.RequireFromString("10")},
+	}, result.Skipped)
+	if assert.Len(t, result.Accounts, 1) {
+		assert.Equal(t, "L-2", result.Accounts[0].ExternalID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
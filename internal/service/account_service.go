@@ -1,50 +1,180 @@
 package service
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"github.com/yoyo0827/simple-bank-system/internal/accountno"
+	"github.com/yoyo0827/simple-bank-system/internal/approval"
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/businessday"
 	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/fee"
+	"github.com/yoyo0827/simple-bank-system/internal/fraud"
+	"github.com/yoyo0827/simple-bank-system/internal/kyc"
+	"github.com/yoyo0827/simple-bank-system/internal/logging"
+	"github.com/yoyo0827/simple-bank-system/internal/metrics"
+	"github.com/yoyo0827/simple-bank-system/internal/payee"
 	"github.com/yoyo0827/simple-bank-system/internal/repository"
 	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrInsufficientFunds 餘額不足
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// ErrAccountFrozen 帳號已凍結
+var ErrAccountFrozen = errors.New("account is frozen")
+
+// ErrTransferBlocked 風控規則拒絕轉帳
+var ErrTransferBlocked = errors.New("transfer blocked")
+
+// ErrFeeRevenueAccountMissing 設定的手續費收入帳號不存在 (設定錯誤，與客戶的帳號無關)
+var ErrFeeRevenueAccountMissing = errors.New("fee revenue account not found")
+
+// 帳號號碼重複時重新產生的次數上限
+const accountNumberAttempts = 5
+
 type AccountService struct {
-	DB                    *sql.DB
-	AccountRepository     *repository.AccountRepository
-	TransactionRepository *repository.TransactionRepository
+	DB                        *sql.DB
+	AccountRepository         *repository.AccountRepository
+	TransactionRepository     *repository.TransactionRepository
+	AuditRepository           *repository.AuditRepository
+	OutboxRepository          *repository.OutboxRepository
+	PendingTransferRepository *repository.PendingTransferRepository
+	FraudRepository           *repository.FraudRepository
+	CustomerRepository        *repository.CustomerRepository
+	PayeeRepository           *repository.PayeeRepository
+	BalanceSnapshotRepository *repository.BalanceSnapshotRepository
+	Fees                      *fee.Engine        // 手續費 (未設定時不收費)
+	Fraud                     *fraud.Engine      // 轉帳風控規則 (未設定時一律放行)
+	Approval                  approval.Config    // 大額轉帳覆核、待處理轉帳的保留款項與逾期設定
+	KYC                       kyc.Config         // 啟用時開戶與轉出需持有人已通過身分驗證
+	AccountNumbers            accountno.Config   // 新帳號的對外號碼格式
+	Payees                    payee.Config       // 轉給收款人時的冷靜期上限
+	BusinessDay               businessday.Config // 交易所屬營業日的時區
+	Publisher                 EventPublisher     // 提交後直接發佈事件 (未設定時由 LISTEN/NOTIFY 發佈)
+	Currency                  string             // 用於 metrics 的幣別標籤
+}
+
+// EventPublisher 在交易提交後接收事件，例如行程內的事件串流
+type EventPublisher interface {
+	Publish(ev *domain.Event)
 }
 
 // 查詢帳號
-func (s *AccountService) FindAccount(id string) (*domain.Account, error) {
-	acc, err := s.AccountRepository.FindById(s.DB, id)
+func (s *AccountService) FindAccount(ctx context.Context, id string) (_ *domain.Account, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.FindAccount")
+	defer func() { tracing.End(span, err) }()
+
+	acc, err := s.AccountRepository.FindById(ctx, s.DB, id)
 	if err != nil {
 		return nil, err
 	}
 	return acc, nil
 }
 
-// 建立帳號
-func (s *AccountService) CreateAccount(name string, balance float64) (*domain.Account, error) {
-	if balance < 0 {
-		return nil, errors.New("balance cannot be negative")
+// 以對外帳號號碼查詢帳號
+func (s *AccountService) FindAccountByNumber(ctx context.Context, number string) (_ *domain.Account, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.FindAccountByNumber")
+	defer func() { tracing.End(span, err) }()
+
+	return s.AccountRepository.FindByNumber(ctx, s.DB, number)
+}
+
+// 建立帳號，指定客戶時第一位為主要持有人、其餘為聯名持有人
+func (s *AccountService) CreateAccount(ctx context.Context, req *request.CreateAccountRequest) (_ *domain.Account, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.CreateAccount")
+	defer func() { tracing.End(span, err) }()
+
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	acc, ev, err := s.openAccount(ctx, transaction, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	s.publish(ev)
+	return acc, nil
+}
+
+// 在進行中的 transaction 內開戶：建立帳號與持有人、記錄期初餘額存款、稽核紀錄與 outbox 事件
+// 回傳的事件需在提交後發佈
+func (s *AccountService) openAccount(ctx context.Context, db repository.DBTX, req *request.CreateAccountRequest) (*domain.Account, *domain.Event, error) {
+	balance := req.Balance
+	if balance.IsNegative() {
+		return nil, nil, errors.New("balance cannot be negative")
 	}
 	acc := &domain.Account{
-		Name:    name,
-		Balance: decimal.NewFromFloat(balance), // float64 -> decimal
+		Name:    req.Name,
+		Balance: balance,
+		Product: req.Product,
+		Status:  domain.AccountActive,
+	}
+	if acc.Product == "" {
+		acc.Product = domain.ProductStandard
+	}
+	holders, err := s.newHolders(ctx, db, req.CustomerIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if acc.Name == "" && len(holders) > 0 {
+		acc.Name = holders[0].LegalName
+	}
+	if err := s.insertAccount(ctx, db, acc); err != nil {
+		return nil, nil, err
 	}
-	err := s.AccountRepository.CreateUser(s.DB, acc)
-	return acc, err
+	for _, h := range holders {
+		h.AccountID, h.AccountNumber = acc.ID, acc.Number
+		if err := s.CustomerRepository.AddHolder(ctx, db, h); err != nil {
+			return nil, nil, err
+		}
+	}
+	// 初始餘額記為一筆存款，讓餘額與交易紀錄加總一致
+	if balance.IsPositive() {
+		tx := s.newTransaction(ctx, acc.Name, 2, domain.TxKindOpening, balance, uuid.New().String(), "Opening balance")
+		if err := s.insertTransaction(ctx, db, acc.ID, tx); err != nil {
+			return nil, nil, err
+		}
+	}
+	// 寫入稽核紀錄
+	if err := s.audit(ctx, db, domain.AuditAccountCreated, acc.ID, nil, acc); err != nil {
+		return nil, nil, err
+	}
+	// 寫入 outbox 事件
+	ev, err := s.recordEvent(ctx, db, domain.EventAccountCreated, acc.Number, domain.AccountCreatedData{
+		AccountNumber: acc.Number,
+		Name:          acc.Name,
+		Balance:       acc.Balance,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return acc, ev, nil
 }
 
 // 交易
-func (s *AccountService) CreateTransaction(id string, req *request.TransactionRequest) (string, error) {
+func (s *AccountService) CreateTransaction(ctx context.Context, id string, req *request.TransactionRequest) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.CreateTransaction")
+	defer func() { tracing.End(span, err) }()
+
 	// 交易安全，使用 transaction
-	transaction, err := s.DB.Begin()
+	transaction, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return "", err
 	}
@@ -54,57 +184,98 @@ func (s *AccountService) CreateTransaction(id string, req *request.TransactionRe
 			panic(p)
 		}
 	}()
-	// 查詢帳號
-	acc, err := s.AccountRepository.FindById(s.DB, id)
+	defer transaction.Rollback()
+
+	// 查詢並鎖定帳號，餘額與保留款項以鎖定後的值計算
+	acc, err := s.AccountRepository.FindByIdForUpdate(ctx, transaction, id)
 	if err != nil {
 		return "", err
 	}
+	if err := checkActive(acc); err != nil {
+		return "", err
+	}
 	// 定義交易類型 1=提款, 2=存款
 	var txType int
+	var kind string
+	metricType, eventType := metrics.TypeDeposit, domain.EventDeposited
 	if req.Amount.IsNegative() {
-		txType = 1
+		txType, kind = 1, domain.TxKindWithdrawal
+		metricType, eventType = metrics.TypeWithdrawal, domain.EventWithdrawn
 	} else {
-		txType = 2
+		txType, kind = 2, domain.TxKindDeposit
 	}
 
-	// 更新帳號餘額
-	newBalance := acc.Balance.Add(req.Amount)
-	if newBalance.IsNegative() {
-		return "", errors.New("insufficient funds, cannot withdraw more than the current balance")
+	// 提款需另收手續費
+	var charge decimal.Decimal
+	if txType == 1 {
+		charge = s.quoteFee(acc, fee.OpWithdrawal, req.Amount.Abs()).Fee
+	}
+	// 更新帳號餘額，待處理轉帳保留的款項不可提領
+	newBalance := acc.Balance.Add(req.Amount).Sub(charge)
+	if newBalance.LessThan(acc.HeldBalance) {
+		metrics.InsufficientFunds.WithLabelValues(metricType).Inc()
+		return "", fmt.Errorf("%w, cannot withdraw more than the available balance", ErrInsufficientFunds)
 	}
-	if err := s.AccountRepository.UpdateBalance(transaction, id, newBalance); err != nil {
+	if err := s.AccountRepository.UpdateBalance(ctx, transaction, id, newBalance); err != nil {
 		return "", err
 	}
 	// 寫入交易紀錄
 	refID := uuid.New().String()
-	tx := newTransaction(acc.Name, txType, req.Amount, refID, "")
-	if err := s.TransactionRepository.InsertTransactions(transaction, acc.ID, tx); err != nil {
-		return "", errors.New("failed to insert transaction record: " + err.Error())
-	}
-	// 印出交易紀錄 log
-	log.Printf(
-		"[Transaction] ref_id=%s | acc=%s | amount=%s | at=%s",
-		refID, acc.ID, req.Amount.String(), time.Now().Format(time.RFC3339),
-	)
+	span.SetAttributes(attribute.String("bank.ref_id", refID))
+	tx := s.newTransaction(ctx, acc.Name, txType, kind, req.Amount, refID, "")
+	if err := s.insertTransaction(ctx, transaction, acc.ID, tx); err != nil {
+		return "", fmt.Errorf("failed to insert transaction record: %w", err)
+	}
+	if err := s.chargeFee(ctx, transaction, acc, fee.OpWithdrawal, charge, refID); err != nil {
+		return "", err
+	}
+	// 寫入稽核紀錄
+	after := *acc
+	after.Balance = newBalance
+	if err := s.audit(ctx, transaction, domain.AuditAccountBalanceChanged, acc.ID, acc, &after); err != nil {
+		return "", err
+	}
+	// 寫入 outbox 事件
+	ev, err := s.recordEvent(ctx, transaction, eventType, acc.Number, domain.BalanceChangedData{
+		AccountNumber: acc.Number,
+		RefID:         refID,
+		Amount:        req.Amount.Abs(),
+		Balance:       newBalance,
+	})
+	if err != nil {
+		return "", err
+	}
 	// 提交交易
 	if err := transaction.Commit(); err != nil {
 		return "", err
 	}
+	slog.InfoContext(ctx, "transaction committed",
+		"ref_id", refID,
+		"account_id", acc.ID,
+		"type", metricType,
+		"amount", req.Amount.String(),
+		"fee", charge.String(),
+	)
+	s.publish(ev)
+	s.recordMetrics(metricType, req.Amount.Abs())
 	return refID, nil
 }
 
-// 轉帳
-func (s *AccountService) Transfer(req *request.TransferRequest) (string, error) {
+// 轉帳，風控規則要求審核或金額超過覆核門檻時暫緩執行並建立待處理轉帳
+func (s *AccountService) Transfer(ctx context.Context, req *request.TransferRequest) (_ *domain.TransferResult, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.Transfer")
+	defer func() { tracing.End(span, err) }()
+
 	// 驗證轉帳金額
 	amount := req.Amount
 	if err := validateAmount(amount); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// 交易安全，使用 transaction
-	transaction, err := s.DB.Begin()
+	transaction, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer func() {
 		if p := recover(); p != nil {
@@ -114,57 +285,678 @@ func (s *AccountService) Transfer(req *request.TransferRequest) (string, error)
 	}()
 	defer transaction.Rollback()
 
-	// 查詢雙方帳號
-	fromAcc, err := s.AccountRepository.FindById(s.DB, req.FromID)
+	// 以帳號號碼查詢雙方帳號 (轉入帳號可由收款人指定)，再鎖定雙方帳號取得最新的餘額
+	fromAcc, err := s.AccountRepository.FindByNumber(ctx, transaction, req.FromAccount)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	toAcc, err := s.AccountRepository.FindById(s.DB, req.ToID)
+	toAcc, p, err := s.transferTarget(ctx, transaction, fromAcc, req)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	fromAcc, toAcc, err = s.lockAccounts(ctx, transaction, accountRef{fromAcc.ID, fromAcc.Number}, accountRef{toAcc.ID, toAcc.Number})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkActive(fromAcc); err != nil {
+		return nil, err
+	}
+	if err := checkActive(toAcc); err != nil {
+		return nil, err
+	}
+	if err := s.checkKYC(ctx, transaction, fromAcc.ID); err != nil {
+		return nil, err
 	}
-	// 檢查餘額是否足夠
-	if fromAcc.Balance.Cmp(req.Amount) < 0 {
-		return "", errors.New("insufficient funds, cannot transfer more than the current balance")
+	if err := s.checkPayeeLimit(ctx, transaction, fromAcc.ID, toAcc.ID, p, amount); err != nil {
+		return nil, err
+	}
+	// 風控檢查：拒絕或待審核時僅記錄決策，不異動餘額
+	screen, err := s.Fraud.Screen(ctx, fraud.Transfer{FromID: fromAcc.ID, ToID: toAcc.ID, FromNumber: fromAcc.Number, ToNumber: toAcc.Number, Amount: amount, At: time.Now()},
+		transferHistory{repo: s.TransactionRepository, db: transaction})
+	if err != nil {
+		return nil, err
+	}
+	decision := newFraudDecision(ctx, fromAcc.ID, toAcc.ID, amount, screen)
+	var reason string
+	switch screen.Decision {
+	case fraud.Block:
+		if err := s.FraudRepository.InsertDecision(ctx, transaction, decision); err != nil {
+			return nil, err
+		}
+		if err := transaction.Commit(); err != nil {
+			return nil, err
+		}
+		slog.WarnContext(ctx, "transfer blocked",
+			"from_account_id", fromAcc.ID,
+			"to_account_id", toAcc.ID,
+			"amount", amount.String(),
+			"rule", screen.Rule,
+		)
+		return nil, fmt.Errorf("%w by rule %s: %s", ErrTransferBlocked, screen.Rule, screen.Reason)
+	case fraud.Review:
+		reason = domain.PendingReasonFraudReview
+	default:
+		if s.Approval.Required(amount) {
+			reason = domain.PendingReasonApproval
+		}
+	}
+	if reason != "" {
+		pt, err := s.holdTransfer(ctx, transaction, fromAcc, toAcc, amount, reason, decision)
+		if err != nil {
+			return nil, err
+		}
+		if err := transaction.Commit(); err != nil {
+			return nil, err
+		}
+		slog.InfoContext(ctx, "transfer held",
+			"pending_transfer_id", pt.ID,
+			"reason", reason,
+			"from_account_id", fromAcc.ID,
+			"to_account_id", toAcc.ID,
+			"amount", amount.String(),
+			"held_amount", pt.HeldAmount.String(),
+			"rule", screen.Rule,
+		)
+		if reason == domain.PendingReasonApproval {
+			return &domain.TransferResult{Status: domain.TransferStatusPendingApproval, PendingTransferID: pt.ID}, nil
+		}
+		return &domain.TransferResult{Status: domain.TransferStatusPendingReview, PendingTransferID: pt.ID, Rule: screen.Rule}, nil
+	}
+	// 執行轉帳
+	t, err := s.executeTransfer(ctx, transaction, fromAcc, toAcc, amount)
+	if err != nil {
+		return nil, err
+	}
+	if s.Fraud.Enabled() {
+		decision.RefID = t.refID
+		if err := s.FraudRepository.InsertDecision(ctx, transaction, decision); err != nil {
+			return nil, err
+		}
+	}
+	// 提交交易
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	s.transferCommitted(ctx, t)
+	return &domain.TransferResult{Status: domain.TransferStatusCompleted, RefID: t.refID}, nil
+}
+
+// executedTransfer 已寫入但尚未提交的轉帳
+type executedTransfer struct {
+	refID  string
+	from   string
+	to     string
+	amount decimal.Decimal
+	fee    decimal.Decimal
+	event  *domain.Event
+}
+
+// 在同一個 SQL transaction 中扣款、入帳並寫入交易紀錄、手續費、稽核紀錄與 outbox 事件
+func (s *AccountService) executeTransfer(ctx context.Context, transaction repository.DBTX, fromAcc, toAcc *domain.Account, amount decimal.Decimal) (*executedTransfer, error) {
+	// 檢查可用餘額是否足夠 (含手續費)
+	charge := s.quoteFee(fromAcc, fee.OpTransfer, amount).Fee
+	debit := amount.Add(charge)
+	if fromAcc.Available().Cmp(debit) < 0 {
+		metrics.InsufficientFunds.WithLabelValues(metrics.TypeTransfer).Inc()
+		return nil, fmt.Errorf("%w, cannot transfer more than the available balance", ErrInsufficientFunds)
 	}
 	// 更新雙方帳號餘額
-	if err := s.AccountRepository.UpdateBalance(transaction, fromAcc.ID, fromAcc.Balance.Sub(amount)); err != nil {
-		return "", err
+	if err := s.AccountRepository.UpdateBalance(ctx, transaction, fromAcc.ID, fromAcc.Balance.Sub(debit)); err != nil {
+		return nil, err
 	}
-	if err := s.AccountRepository.UpdateBalance(transaction, toAcc.ID, toAcc.Balance.Add(amount)); err != nil {
-		return "", err
+	if err := s.AccountRepository.UpdateBalance(ctx, transaction, toAcc.ID, toAcc.Balance.Add(amount)); err != nil {
+		return nil, err
 	}
 	// 寫入交易紀錄
 	refID := uuid.New().String()
-	if err := s.TransactionRepository.InsertTransactions(transaction, fromAcc.ID,
-		newTransaction(fromAcc.Name, 1, req.Amount, refID, "Transfer to "+toAcc.Name)); err != nil {
-		return "", err
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("bank.ref_id", refID))
+	if err := s.insertTransaction(ctx, transaction, fromAcc.ID,
+		s.newTransaction(ctx, fromAcc.Name, 1, domain.TxKindTransferOut, amount, refID, domain.DescTransferOut+toAcc.Name)); err != nil {
+		return nil, err
 	}
-	if err := s.TransactionRepository.InsertTransactions(transaction, toAcc.ID,
-		newTransaction(toAcc.Name, 2, req.Amount, refID, "Transfer from "+fromAcc.Name)); err != nil {
-		return "", err
+	if err := s.insertTransaction(ctx, transaction, toAcc.ID,
+		s.newTransaction(ctx, toAcc.Name, 2, domain.TxKindTransferIn, amount, refID, domain.DescTransferIn+fromAcc.Name)); err != nil {
+		return nil, err
+	}
+	if err := s.chargeFee(ctx, transaction, fromAcc, fee.OpTransfer, charge, refID); err != nil {
+		return nil, err
+	}
+	// 寫入雙方帳號的稽核紀錄
+	fromAfter, toAfter := *fromAcc, *toAcc
+	fromAfter.Balance = fromAcc.Balance.Sub(debit)
+	toAfter.Balance = toAcc.Balance.Add(amount)
+	if err := s.audit(ctx, transaction, domain.AuditAccountBalanceChanged, fromAcc.ID, fromAcc, &fromAfter); err != nil {
+		return nil, err
+	}
+	if err := s.audit(ctx, transaction, domain.AuditAccountBalanceChanged, toAcc.ID, toAcc, &toAfter); err != nil {
+		return nil, err
+	}
+	// 寫入 outbox 事件
+	ev, err := s.recordEvent(ctx, transaction, domain.EventTransferCompleted, fromAcc.Number, domain.TransferCompletedData{
+		RefID:             refID,
+		FromAccountNumber: fromAcc.Number,
+		ToAccountNumber:   toAcc.Number,
+		Amount:            amount,
+	})
+	if err != nil {
+		return nil, err
 	}
-	// 印出轉帳紀錄 log
-	log.Printf(
-		"[Transfer] ref_id=%s | from_acc=%s | to_acc=%s | amount=%s | at=%s",
-		refID, fromAcc.ID, toAcc.ID, amount.String(), time.Now().Format(time.RFC3339),
+	return &executedTransfer{refID: refID, from: fromAcc.ID, to: toAcc.ID, amount: amount, fee: charge, event: ev}, nil
+}
+
+// 轉帳提交後記錄日誌、發佈事件與更新 metrics
+func (s *AccountService) transferCommitted(ctx context.Context, t *executedTransfer) {
+	slog.InfoContext(ctx, "transfer committed",
+		"ref_id", t.refID,
+		"from_account_id", t.from,
+		"to_account_id", t.to,
+		"amount", t.amount.String(),
+		"fee", t.fee.String(),
 	)
-	// 提交交易
-	if err := transaction.Commit(); err != nil {
-		return "", err
+	s.publish(t.event)
+	s.recordMetrics(metrics.TypeTransfer, t.amount)
+}
+
+// 建立待處理轉帳，依設定保留轉出金額 (含手續費) 並記錄風控決策與處理紀錄
+func (s *AccountService) holdTransfer(ctx context.Context, db repository.DBTX, fromAcc, toAcc *domain.Account, amount decimal.Decimal, reason string, decision *domain.FraudDecision) (*domain.PendingTransfer, error) {
+	pt := &domain.PendingTransfer{
+		FromAccountID: fromAcc.ID,
+		ToAccountID:   toAcc.ID,
+		Amount:        amount,
+		Reason:        reason,
+		Status:        domain.PendingTransferPending,
+		RequestedBy:   auth.Actor(ctx),
+		RequestID:     logging.RequestID(ctx),
 	}
-	return refID, nil
+	if s.Approval.Expiry > 0 {
+		expiresAt := time.Now().Add(s.Approval.Expiry)
+		pt.ExpiresAt = &expiresAt
+	}
+	if s.Approval.HoldFunds {
+		held := amount.Add(s.quoteFee(fromAcc, fee.OpTransfer, amount).Fee)
+		if fromAcc.Available().Cmp(held) < 0 {
+			metrics.InsufficientFunds.WithLabelValues(metrics.TypeTransfer).Inc()
+			return nil, fmt.Errorf("%w, cannot transfer more than the available balance", ErrInsufficientFunds)
+		}
+		if err := s.AccountRepository.AddHeldBalance(ctx, db, fromAcc.ID, held); err != nil {
+			return nil, err
+		}
+		pt.HeldAmount = held
+	}
+	if err := s.PendingTransferRepository.Insert(ctx, db, pt); err != nil {
+		return nil, err
+	}
+	if s.Fraud.Enabled() {
+		decision.PendingTransferID = pt.ID
+		if err := s.FraudRepository.InsertDecision(ctx, db, decision); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.PendingTransferRepository.InsertAction(ctx, db, &domain.PendingTransferAction{
+		PendingTransferID: pt.ID,
+		Action:            domain.PendingActionRequested,
+		Actor:             pt.RequestedBy,
+		RequestID:         pt.RequestID,
+	}); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.AuditRepository, db, domain.AuditTransferPending, "pending_transfer", pt.ID, nil, pt); err != nil {
+		return nil, err
+	}
+	return pt, nil
 }
 
 // 查詢帳號交易紀錄
-func (s *AccountService) FindAccountTransactions(id string) ([]*domain.Transaction, error) {
-	transactions, err := s.TransactionRepository.FindById(s.DB, id)
+func (s *AccountService) FindAccountTransactions(ctx context.Context, id string, filter repository.TransactionFilter) (_ []*domain.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.FindAccountTransactions")
+	defer func() { tracing.End(span, err) }()
+
+	transactions, err := s.TransactionRepository.FindById(ctx, s.DB, id, filter)
 	if err != nil {
 		return nil, err
 	}
 	return transactions, nil
 }
 
+// 凍結或解除凍結帳號
+func (s *AccountService) SetAccountStatus(ctx context.Context, id, status, reason string) (_ *domain.Account, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.SetAccountStatus")
+	defer func() { tracing.End(span, err) }()
+
+	if status != domain.AccountActive && status != domain.AccountFrozen {
+		return nil, fmt.Errorf("unknown account status %q", status)
+	}
+	if status == domain.AccountActive {
+		reason = ""
+	}
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	acc, err := s.AccountRepository.FindById(ctx, transaction, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.AccountRepository.UpdateStatus(ctx, transaction, id, status, reason); err != nil {
+		return nil, err
+	}
+	after := *acc
+	after.Status, after.StatusReason = status, reason
+	if err := s.audit(ctx, transaction, domain.AuditAccountStatusChanged, acc.ID, acc, &after); err != nil {
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// 產生帳號在 [from, to) 期間的對帳單，from / to 為零值時代表不限
+// 期初 / 期末餘額由目前餘額扣除之後的交易淨額回推，並在同一個快照中讀取
+func (s *AccountService) Statement(ctx context.Context, id string, from, to time.Time) (_ *domain.Statement, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.Statement")
+	defer func() { tracing.End(span, err) }()
+
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, errors.New("from must be before to")
+	}
+	transaction, err := s.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	acc, err := s.AccountRepository.FindById(ctx, transaction, id)
+	if err != nil {
+		return nil, err
+	}
+	sinceFrom, err := s.TransactionRepository.NetChangeSince(ctx, transaction, id, from)
+	if err != nil {
+		return nil, err
+	}
+	closing := acc.Balance
+	if !to.IsZero() {
+		sinceTo, err := s.TransactionRepository.NetChangeSince(ctx, transaction, id, to)
+		if err != nil {
+			return nil, err
+		}
+		closing = acc.Balance.Sub(sinceTo)
+	}
+	transactions, err := s.TransactionRepository.FindById(ctx, transaction, id, repository.TransactionFilter{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+
+	st := &domain.Statement{
+		AccountNumber:  acc.Number,
+		Name:           acc.Name,
+		OpeningBalance: acc.Balance.Sub(sinceFrom),
+		ClosingBalance: closing,
+		Transactions:   transactions,
+	}
+	if st.Transactions == nil {
+		st.Transactions = []*domain.Transaction{}
+	}
+	if !from.IsZero() {
+		st.From = &from
+	}
+	if !to.IsZero() {
+		st.To = &to
+	}
+	for _, tx := range transactions {
+		if tx.Type == 2 {
+			st.TotalDeposits = st.TotalDeposits.Add(tx.Amount)
+		} else {
+			st.TotalWithdrawals = st.TotalWithdrawals.Add(tx.Amount)
+		}
+	}
+	return st, nil
+}
+
+// 帳號在 at 時 (含，零值為現在) 的餘額：由 at 之前最近一筆快照加上其後的交易紀錄，沒有快照時加總所有交易
+func (s *AccountService) BalanceAt(ctx context.Context, id string, at time.Time) (_ *domain.HistoricalBalance, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.BalanceAt")
+	defer func() { tracing.End(span, err) }()
+
+	now := time.Now()
+	if at.IsZero() {
+		at = now
+	}
+	if at.After(now) {
+		return nil, errors.New("at cannot be in the future")
+	}
+	transaction, err := s.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	acc, err := s.AccountRepository.FindById(ctx, transaction, id)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := s.BalanceSnapshotRepository.FindLatest(ctx, transaction, id, at)
+	if errors.Is(err, sql.ErrNoRows) {
+		snap, err = &domain.BalanceSnapshot{AccountID: id}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	net, err := s.TransactionRepository.NetChangeBetween(ctx, transaction, id, snap.AsOf, at)
+	if err != nil {
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	return &domain.HistoricalBalance{AccountNumber: acc.Number, At: at, Balance: snap.Balance.Add(net)}, nil
+}
+
+// SnapshotBalances 為 until 之前有新交易的帳號建立餘額快照，每批帳號各自提交，回傳建立的筆數
+func (s *AccountService) SnapshotBalances(ctx context.Context, until time.Time, batchSize int) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.SnapshotBalances")
+	defer func() { tracing.End(span, err) }()
+
+	var total int
+	var afterID int64
+	for {
+		lastID, n, err := s.BalanceSnapshotRepository.SnapshotBatch(ctx, s.DB, afterID, batchSize, until)
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if lastID == 0 {
+			return total, nil
+		}
+		afterID = lastID
+	}
+}
+
+// 查詢帳號在 afterID 之後的事件 (依 ID 排序)，用於事件串流補齊
+func (s *AccountService) FindAccountEvents(ctx context.Context, id string, afterID int64, limit int) (_ []*domain.Event, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.FindAccountEvents")
+	defer func() { tracing.End(span, err) }()
+
+	return s.OutboxRepository.FindByAccount(ctx, s.DB, id, afterID, limit)
+}
+
+// 試算手續費，讓使用者在確認前得知實際扣款金額
+func (s *AccountService) PreviewFee(ctx context.Context, id, op string, amount decimal.Decimal) (_ *domain.FeePreview, err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.PreviewFee")
+	defer func() { tracing.End(span, err) }()
+
+	acc, err := s.AccountRepository.FindById(ctx, s.DB, id)
+	if err != nil {
+		return nil, err
+	}
+	q := s.quoteFee(acc, op, amount)
+	return &domain.FeePreview{
+		AccountNumber: acc.Number,
+		Operation:     op,
+		Amount:        amount,
+		Fee:           q.Fee,
+		Total:         amount.Add(q.Fee),
+		Schedule:      q.Schedule,
+	}, nil
+}
+
+// CheckFeeRevenueAccount 確認啟用手續費時設定的收入帳號存在，供啟動時檢查
+func (s *AccountService) CheckFeeRevenueAccount(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "AccountService.CheckFeeRevenueAccount")
+	defer func() { tracing.End(span, err) }()
+
+	number := s.Fees.RevenueAccount()
+	if number == "" {
+		return nil
+	}
+	_, err = s.AccountRepository.FindByNumber(ctx, s.DB, number)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", ErrFeeRevenueAccountMissing, number)
+	}
+	return err
+}
+
+// 依帳號產品計算手續費，手續費收入帳號本身不收費
+func (s *AccountService) quoteFee(acc *domain.Account, op string, amount decimal.Decimal) fee.Quote {
+	if acc.Number == s.Fees.RevenueAccount() {
+		return fee.Quote{}
+	}
+	return s.Fees.Quote(op, acc.Product, amount)
+}
+
+// 在同一個 SQL transaction 中記錄手續費：付款帳號一筆提款、手續費收入帳號一筆存款，皆沿用原交易的 ref_id
+// 付款帳號的餘額由呼叫端一併扣除；收入帳號在其他帳號之後鎖定 (見 lockAccounts)
+func (s *AccountService) chargeFee(ctx context.Context, db repository.DBTX, acc *domain.Account, op string, charge decimal.Decimal, refID string) error {
+	if !charge.IsPositive() {
+		return nil
+	}
+	if err := s.insertTransaction(ctx, db, acc.ID,
+		s.newTransaction(ctx, acc.Name, 1, domain.TxKindFee, charge, refID, domain.DescFee+op)); err != nil {
+		return err
+	}
+	// 收入帳號同時為轉帳的一方時，讀到的是本交易更新後的餘額
+	revenue, err := s.AccountRepository.FindByNumberForUpdate(ctx, db, s.Fees.RevenueAccount())
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", ErrFeeRevenueAccountMissing, s.Fees.RevenueAccount())
+	}
+	if err != nil {
+		return err
+	}
+	after := *revenue
+	after.Balance = revenue.Balance.Add(charge)
+	if err := s.AccountRepository.UpdateBalance(ctx, db, revenue.ID, after.Balance); err != nil {
+		return err
+	}
+	if err := s.insertTransaction(ctx, db, revenue.ID,
+		s.newTransaction(ctx, revenue.Name, 2, domain.TxKindFee, charge, refID, domain.DescFee+op+" from "+acc.Name)); err != nil {
+		return err
+	}
+	return s.audit(ctx, db, domain.AuditAccountBalanceChanged, revenue.ID, revenue, &after)
+}
+
+// 寫入帳號相關的稽核紀錄
+func (s *AccountService) audit(ctx context.Context, db repository.DBTX, action, accountID string, before, after *domain.Account) error {
+	var b, a any
+	if before != nil {
+		b = before
+	}
+	if after != nil {
+		a = after
+	}
+	return recordAudit(ctx, s.AuditRepository, db, action, "account", accountID, b, a)
+}
+
+// 在同一個 SQL transaction 中寫入 outbox 事件，由 relay 於提交後投遞
+// 必須在 audit 之後呼叫：稽核寫入的 advisory lock 讓事件 ID 依提交順序遞增，SSE 補齊事件依賴此順序
+func (s *AccountService) recordEvent(ctx context.Context, db repository.DBTX, eventType, aggregateID string, data any) (*domain.Event, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	ev := &domain.Event{
+		Type:        eventType,
+		AggregateID: aggregateID,
+		RequestID:   logging.RequestID(ctx),
+		OccurredAt:  time.Now().UTC(),
+		Data:        payload,
+	}
+	if err := s.OutboxRepository.Insert(ctx, db, ev); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// 交易提交後發佈事件
+func (s *AccountService) publish(ev *domain.Event) {
+	if s.Publisher != nil {
+		s.Publisher.Publish(ev)
+	}
+}
+
+// 交易提交後更新 metrics
+func (s *AccountService) recordMetrics(txType string, amount decimal.Decimal) {
+	metrics.Transactions.WithLabelValues(txType).Inc()
+	metrics.Volume.WithLabelValues(txType, s.Currency).Add(amount.InexactFloat64())
+}
+
+// 依客戶建立帳號持有人，啟用 KYC 時需至少一位客戶且皆已通過驗證
+func (s *AccountService) newHolders(ctx context.Context, db repository.DBTX, customerIDs []string) ([]*domain.AccountHolder, error) {
+	if s.KYC.Enabled && len(customerIDs) == 0 {
+		return nil, fmt.Errorf("%w: customer_ids is required to open an account", ErrKYCNotVerified)
+	}
+	holders := make([]*domain.AccountHolder, 0, len(customerIDs))
+	for i, id := range customerIDs {
+		c, err := findCustomer(ctx, s.CustomerRepository, db, id)
+		if err != nil {
+			return nil, err
+		}
+		if s.KYC.Enabled && c.KYCStatus != domain.KYCVerified {
+			return nil, fmt.Errorf("%w: customer %s is %s", ErrKYCNotVerified, c.ID, c.KYCStatus)
+		}
+		role := domain.HolderJoint
+		if i == 0 {
+			role = domain.HolderPrimary
+		}
+		holders = append(holders, &domain.AccountHolder{CustomerID: c.ID, LegalName: c.LegalName, Role: role, KYCStatus: c.KYCStatus})
+	}
+	return holders, nil
+}
+
+// 產生帳號號碼並建立帳號，號碼與既有帳號重複時重新產生
+func (s *AccountService) insertAccount(ctx context.Context, db repository.DBTX, acc *domain.Account) error {
+	for range accountNumberAttempts {
+		number, err := s.AccountNumbers.Generate()
+		if err != nil {
+			return err
+		}
+		acc.Number = number
+		if err := s.AccountRepository.CreateUser(ctx, db, acc); !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+	return fmt.Errorf("no unique account number after %d attempts", accountNumberAttempts)
+}
+
+// 啟用 KYC 時，轉出帳號需有持有人且所有持有人 (含聯名) 皆已通過驗證
+func (s *AccountService) checkKYC(ctx context.Context, db repository.DBTX, accountID string) error {
+	if !s.KYC.Enabled {
+		return nil
+	}
+	holders, err := s.CustomerRepository.FindHolders(ctx, db, accountID)
+	if err != nil {
+		return err
+	}
+	if len(holders) == 0 {
+		return fmt.Errorf("%w: account %s has no holders", ErrKYCNotVerified, accountID)
+	}
+	for _, h := range holders {
+		if h.KYCStatus != domain.KYCVerified {
+			return fmt.Errorf("%w: customer %s of account %s is %s", ErrKYCNotVerified, h.CustomerID, accountID, h.KYCStatus)
+		}
+	}
+	return nil
+}
+
+// 轉入帳號：以收款人 ID 指定時需為轉出帳號的收款人，否則以帳號號碼查詢 (此時收款人為 nil)
+func (s *AccountService) transferTarget(ctx context.Context, db repository.DBTX, fromAcc *domain.Account, req *request.TransferRequest) (*domain.Account, *domain.Payee, error) {
+	if req.PayeeID == "" {
+		toAcc, err := s.AccountRepository.FindByNumber(ctx, db, req.ToAccount)
+		return toAcc, nil, err
+	}
+	p, err := findPayee(ctx, s.PayeeRepository, db, fromAcc.ID, req.PayeeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	toAcc, err := s.AccountRepository.FindById(ctx, db, p.PayeeAccountID)
+	return toAcc, p, err
+}
+
+// 在 transaction 內鎖定轉帳雙方帳號並讀取最新的餘額與保留款項
+// 固定依 ID 順序鎖定，避免兩筆方向相反的轉帳互相等待而死結
+// 手續費收入帳號一律最後鎖定，與收取手續費時 (chargeFee) 在付款帳號之後鎖定的順序一致
+func (s *AccountService) lockAccounts(ctx context.Context, db repository.DBTX, from, to accountRef) (fromAcc, toAcc *domain.Account, err error) {
+	fromID, toID := from.id, to.id
+	first, second := fromID, toID
+	if s.isFeeRevenue(from.number) || (!s.isFeeRevenue(to.number) && toID < fromID) {
+		first, second = toID, fromID
+	}
+	a, err := s.AccountRepository.FindByIdForUpdate(ctx, db, first)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := s.AccountRepository.FindByIdForUpdate(ctx, db, second)
+	if err != nil {
+		return nil, nil, err
+	}
+	if first == fromID {
+		return a, b, nil
+	}
+	return b, a, nil
+}
+
+// accountRef 鎖定帳號時所需的內部 ID 與帳號號碼
+type accountRef struct {
+	id, number string
+}
+
+func (s *AccountService) isFeeRevenue(number string) bool {
+	revenue := s.Fees.RevenueAccount()
+	return revenue != "" && number == revenue
+}
+
+// 檢查轉入帳號的單筆與近 24 小時累計上限，收款人在冷靜期內不超過設定的冷靜期上限
+// 未約定的帳號 (含已刪除的收款人) 視同一直在冷靜期內，避免以不約定或刪除收款人繞過冷靜期
+func (s *AccountService) checkPayeeLimit(ctx context.Context, db repository.DBTX, fromID, toID string, p *domain.Payee, amount decimal.Decimal) error {
+	if p == nil {
+		found, err := s.PayeeRepository.FindByPayeeAccount(ctx, db, fromID, toID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		p = found
+	}
+	now := time.Now()
+	target, transferLimit, dailyLimit, coolingOff := "unregistered account", decimal.Zero, decimal.Zero, true
+	if p != nil {
+		target = "payee " + p.ID
+		transferLimit, dailyLimit, coolingOff = p.TransferLimit, p.DailyLimit, p.CoolingOff(now)
+	}
+	exceeded := func(kind string, limit decimal.Decimal) error {
+		err := fmt.Errorf("%w: %s limit for %s is %s", ErrPayeeLimitExceeded, kind, target, limit)
+		if p != nil && coolingOff {
+			err = fmt.Errorf("%w (cooling-off until %s)", err, p.CoolingOffUntil.Format(time.RFC3339))
+		}
+		return err
+	}
+	if limit := s.Payees.Limit(transferLimit, coolingOff); !limit.IsZero() && amount.GreaterThan(limit) {
+		return exceeded("per-transfer", limit)
+	}
+	limit := s.Payees.Limit(dailyLimit, coolingOff)
+	if limit.IsZero() {
+		return nil
+	}
+	sent, err := s.TransactionRepository.TransferredToSince(ctx, db, fromID, toID, now.Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if sent.Add(amount).GreaterThan(limit) {
+		return exceeded("24-hour", limit)
+	}
+	return nil
+}
+
+// 凍結中的帳號不可異動餘額
+func checkActive(acc *domain.Account) error {
+	if acc.Status == domain.AccountFrozen {
+		return fmt.Errorf("%w: account %s", ErrAccountFrozen, acc.ID)
+	}
+	return nil
+}
+
 // 驗證轉帳金額
 func validateAmount(amount decimal.Decimal) error {
 	if amount.IsZero() {
@@ -176,13 +968,57 @@ func validateAmount(amount decimal.Decimal) error {
 	return nil
 }
 
-// 建立交易紀錄
-func newTransaction(name string, txType int, amount decimal.Decimal, refID, desc string) *domain.Transaction {
+// 建立交易紀錄，並帶入當前請求的 request ID 與營業日
+func (s *AccountService) newTransaction(ctx context.Context, name string, txType int, kind string, amount decimal.Decimal, refID, desc string) *domain.Transaction {
 	return &domain.Transaction{
-		Name:        name,
-		Type:        txType,
-		Amount:      amount.Abs(),
-		RefID:       refID,
-		Description: desc,
+		Name:         name,
+		Type:         txType,
+		Kind:         kind,
+		Amount:       amount.Abs(),
+		RefID:        refID,
+		Description:  desc,
+		RequestID:    logging.RequestID(ctx),
+		BusinessDate: s.BusinessDay.Today(time.Now()),
+	}
+}
+
+// 寫入交易紀錄，營業日已結帳時回傳 ErrBusinessDateClosed
+func (s *AccountService) insertTransaction(ctx context.Context, db repository.DBTX, accountID string, tx *domain.Transaction) error {
+	err := s.TransactionRepository.InsertTransactions(ctx, db, accountID, tx)
+	if repository.IsBusinessDateClosed(err) {
+		return fmt.Errorf("%w: %s", ErrBusinessDateClosed, tx.BusinessDate)
+	}
+	return err
+}
+
+// transferHistory 以進行中的 SQL transaction 查詢轉帳紀錄，供風控規則使用
+type transferHistory struct {
+	repo *repository.TransactionRepository
+	db   repository.DBTX
+}
+
+func (h transferHistory) TransferAmountsSince(ctx context.Context, fromID string, since time.Time) ([]decimal.Decimal, error) {
+	return h.repo.TransferAmountsSince(ctx, h.db, fromID, since)
+}
+
+func (h transferHistory) HasTransferredTo(ctx context.Context, fromID, toID string) (bool, error) {
+	return h.repo.HasTransferredTo(ctx, h.db, fromID, toID)
+}
+
+// 依風控檢查結果建立決策紀錄
+func newFraudDecision(ctx context.Context, fromID, toID string, amount decimal.Decimal, res fraud.Result) *domain.FraudDecision {
+	hits, _ := json.Marshal(res.Hits)
+	if res.Hits == nil {
+		hits = []byte("[]")
+	}
+	return &domain.FraudDecision{
+		FromAccountID: fromID,
+		ToAccountID:   toID,
+		Amount:        amount,
+		Decision:      res.Decision,
+		Rule:          res.Rule,
+		Reason:        res.Reason,
+		Hits:          hits,
+		RequestID:     logging.RequestID(ctx),
 	}
 }
@@ -1,14 +1,36 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/accountno"
+	"github.com/yoyo0827/simple-bank-system/internal/approval"
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/businessday"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/fee"
+	"github.com/yoyo0827/simple-bank-system/internal/fraud"
+	"github.com/yoyo0827/simple-bank-system/internal/kyc"
+	"github.com/yoyo0827/simple-bank-system/internal/logging"
+	"github.com/yoyo0827/simple-bank-system/internal/metrics"
+	"github.com/yoyo0827/simple-bank-system/internal/payee"
 	"github.com/yoyo0827/simple-bank-system/internal/repository"
 	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // 單元測試 Transaction (存款)
@@ -20,11 +42,11 @@ func TestTransaction_Deposit(t *testing.T) {
 
 	accountRepo := &repository.AccountRepository{}
 	transactionRepo := &repository.TransactionRepository{}
-	svc := &AccountService{DB: db, AccountRepository: accountRepo, TransactionRepository: transactionRepo}
+	svc := &AccountService{DB: db, AccountRepository: accountRepo, TransactionRepository: transactionRepo, AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}}
 
 	// 模擬帳號查詢
-	rows := sqlmock.NewRows([]string{"id", "name", "balance"}).
-		AddRow("acc1", "Alice", "100")
+	rows := sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).
+		AddRow("acc1", "Alice", "100", "standard", "active", "", "0", "acc1")
 	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1`).
 		WithArgs("acc1").
 		WillReturnRows(rows)
@@ -36,11 +58,13 @@ func TestTransaction_Deposit(t *testing.T) {
 
 	// 模擬 insert transaction
 	mock.ExpectQuery(`INSERT INTO transactions`).
-		WithArgs("acc1", 2, "50", sqlmock.AnyArg(), "").
+		WithArgs("acc1", 2, domain.TxKindDeposit, "50", sqlmock.AnyArg(), "", "", time.Now().UTC().Format("2006-01-02")).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectAudit(mock, "acc1", "")
+	expectEvent(mock, domain.EventDeposited, "acc1", "")
 	mock.ExpectCommit()
 	req := &request.TransactionRequest{Amount: decimal.NewFromInt(50)}
-	refID, err := svc.CreateTransaction("acc1", req)
+	refID, err := svc.CreateTransaction(context.Background(), "acc1", req)
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, refID)
@@ -55,11 +79,11 @@ func TestTransaction_Withdraw(t *testing.T) {
 
 	accountRepo := &repository.AccountRepository{}
 	transactionRepo := &repository.TransactionRepository{}
-	svc := &AccountService{DB: db, AccountRepository: accountRepo, TransactionRepository: transactionRepo}
+	svc := &AccountService{DB: db, AccountRepository: accountRepo, TransactionRepository: transactionRepo, AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}}
 
 	// 模擬帳號查詢
-	rows := sqlmock.NewRows([]string{"id", "name", "balance"}).
-		AddRow("acc1", "Alice", "100")
+	rows := sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).
+		AddRow("acc1", "Alice", "100", "standard", "active", "", "0", "acc1")
 	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1`).
 		WithArgs("acc1").
 		WillReturnRows(rows)
@@ -71,16 +95,167 @@ func TestTransaction_Withdraw(t *testing.T) {
 
 	// 模擬 insert transaction
 	mock.ExpectQuery(`INSERT INTO transactions`).
-		WithArgs("acc1", 1, "50", sqlmock.AnyArg(), "").
+		WithArgs("acc1", 1, domain.TxKindWithdrawal, "50", sqlmock.AnyArg(), "", "", sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectAudit(mock, "acc1", "")
+	expectEvent(mock, domain.EventWithdrawn, "acc1", "")
 	mock.ExpectCommit()
 	req := &request.TransactionRequest{Amount: decimal.NewFromInt(-50)}
-	refID, err := svc.CreateTransaction("acc1", req)
+	refID, err := svc.CreateTransaction(context.Background(), "acc1", req)
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, refID)
 }
 
+// 單元測試 營業日已結帳時拒絕寫入交易
+func TestTransaction_BusinessDateClosed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{},
+		BusinessDay: businessday.Config{TimeZone: "Asia/Taipei"}}
+	today := svc.BusinessDay.Today(time.Now())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1`).
+		WithArgs("acc1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).
+			AddRow("acc1", "Alice", "100", "standard", "active", "", "0", "acc1"))
+	mock.ExpectExec(`UPDATE accounts SET balance = .* WHERE id = .*`).
+		WithArgs("150", "acc1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO transactions`).
+		WithArgs("acc1", 2, domain.TxKindDeposit, "50", sqlmock.AnyArg(), "", "", today).
+		WillReturnError(&pq.Error{Code: "BD001", Message: "business date " + today + " is closed"})
+	mock.ExpectRollback()
+
+	_, err := svc.CreateTransaction(context.Background(), "acc1", &request.TransactionRequest{Amount: decimal.NewFromInt(50)})
+	assert.ErrorIs(t, err, ErrBusinessDateClosed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 提款手續費：扣款含手續費，並以相同 ref_id 寫入手續費收入帳號
+func TestTransaction_WithdrawWithFee(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fees := fee.NewEngine(fee.Config{Enabled: true, RevenueAccount: "rev", Schedules: []fee.Schedule{
+		{Name: "withdrawal", Operation: fee.OpWithdrawal, Percent: decimal.NewFromInt(1), Min: decimal.NewFromInt(2)},
+	}})
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}, Fees: fees}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1`).
+		WithArgs("acc1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("acc1", "Alice", "100", "standard", "active", "", "0", "acc1"))
+	// 100 - 50 - 手續費 2 (1% 低於最低 2)
+	mock.ExpectExec(`UPDATE accounts SET balance = .* WHERE id = .*`).
+		WithArgs("48", "acc1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO transactions`).
+		WithArgs("acc1", 1, domain.TxKindWithdrawal, "50", sqlmock.AnyArg(), "", "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`INSERT INTO transactions`).
+		WithArgs("acc1", 1, domain.TxKindFee, "2", sqlmock.AnyArg(), "Fee: withdrawal", "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1 FOR UPDATE`).
+		WithArgs("rev").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("rev", "Fee Revenue", "1000", "standard", "active", "", "0", "rev"))
+	mock.ExpectExec(`UPDATE accounts SET balance = .* WHERE id = .*`).
+		WithArgs("1002", "rev").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO transactions`).
+		WithArgs("rev", 2, domain.TxKindFee, "2", sqlmock.AnyArg(), "Fee: withdrawal from Alice", "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	expectAudit(mock, "rev", "")
+	expectAudit(mock, "acc1", "")
+	expectEvent(mock, domain.EventWithdrawn, "acc1", "")
+	mock.ExpectCommit()
+
+	refID, err := svc.CreateTransaction(context.Background(), "acc1", &request.TransactionRequest{Amount: decimal.NewFromInt(-50)})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, refID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 手續費收入帳號不存在時回傳設定錯誤，而非帳號不存在
+func TestTransaction_FeeRevenueAccountMissing(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fees := fee.NewEngine(fee.Config{Enabled: true, RevenueAccount: "rev", Schedules: []fee.Schedule{
+		{Name: "withdrawal", Operation: fee.OpWithdrawal, Flat: decimal.NewFromInt(2)},
+	}})
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, Fees: fees}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1`).
+		WithArgs("acc1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("acc1", "Alice", "100", "standard", "active", "", "0", "acc1"))
+	mock.ExpectExec(`UPDATE accounts SET balance = .* WHERE id = .*`).
+		WithArgs("48", "acc1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO transactions`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`INSERT INTO transactions`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1 FOR UPDATE`).
+		WithArgs("rev").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err := svc.CreateTransaction(context.Background(), "acc1", &request.TransactionRequest{Amount: decimal.NewFromInt(-50)})
+	assert.ErrorIs(t, err, ErrFeeRevenueAccountMissing)
+	assert.NotErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 鎖定順序：轉入手續費收入帳號時，不論 ID 大小都最後鎖定收入帳號
+func TestLockAccounts_FeeRevenueLast(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fees := fee.NewEngine(fee.Config{Enabled: true, RevenueAccount: "rev", Schedules: []fee.Schedule{
+		{Name: "transfer", Operation: fee.OpTransfer, Flat: decimal.NewFromInt(1)},
+	}})
+	svc := &AccountService{AccountRepository: &repository.AccountRepository{}, Fees: fees}
+
+	expectLockAccount(mock, "b1", "Alice", "100", "standard", "active", "", "0", "acc-b")
+	expectLockAccount(mock, "a1", "Fee Revenue", "0", "standard", "active", "", "0", "rev")
+
+	fromAcc, toAcc, err := svc.lockAccounts(context.Background(), db, accountRef{"b1", "acc-b"}, accountRef{"a1", "rev"})
+	assert.NoError(t, err)
+	assert.Equal(t, "b1", fromAcc.ID)
+	assert.Equal(t, "a1", toAcc.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 轉帳手續費使餘額不足 (金額本身足夠)
+func TestTransfer_InsufficientFundsForFee(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fees := fee.NewEngine(fee.Config{Enabled: true, RevenueAccount: "rev", Schedules: []fee.Schedule{
+		{Name: "transfer", Operation: fee.OpTransfer, Flat: decimal.NewFromInt(15)},
+	}})
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, Fees: fees}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("from1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("from1", "Alice", "100", "standard", "active", "", "0", "from1"))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("to1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("to1", "Bob", "50", "standard", "active", "", "0", "to1"))
+	expectLockAccount(mock, "from1", "Alice", "100", "standard", "active", "", "0", "from1")
+	expectLockAccount(mock, "to1", "Bob", "50", "standard", "active", "", "0", "to1")
+	expectNoPayee(mock, "from1", "to1")
+	mock.ExpectRollback()
+
+	_, err := svc.Transfer(context.Background(), &request.TransferRequest{FromAccount: "from1", ToAccount: "to1", Amount: decimal.NewFromInt(90)})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 // 單元測試 Transfer (轉帳)
 func TestTransfer(t *testing.T) {
 	db, mock, _ := sqlmock.New()
@@ -88,25 +263,30 @@ func TestTransfer(t *testing.T) {
 
 	accountRepo := &repository.AccountRepository{}
 	transactionRepo := &repository.TransactionRepository{}
-	svc := &AccountService{DB: db, AccountRepository: accountRepo, TransactionRepository: transactionRepo}
+	svc := &AccountService{DB: db, AccountRepository: accountRepo, TransactionRepository: transactionRepo, AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}}
 
 	// 開始 transaction
 	mock.ExpectBegin()
 
 	// 查詢 from 帳號
-	fromRows := sqlmock.NewRows([]string{"id", "name", "balance"}).
-		AddRow("from1", "Alice", "100")
-	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1`).
+	fromRows := sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).
+		AddRow("from1", "Alice", "100", "standard", "active", "", "0", "from1")
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
 		WithArgs("from1").
 		WillReturnRows(fromRows)
 
 	// 查詢 to 帳號
-	toRows := sqlmock.NewRows([]string{"id", "name", "balance"}).
-		AddRow("to1", "Bob", "50")
-	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1`).
+	toRows := sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).
+		AddRow("to1", "Bob", "50", "standard", "active", "", "0", "to1")
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
 		WithArgs("to1").
 		WillReturnRows(toRows)
 
+	// 依 ID 順序鎖定雙方帳號
+	expectLockAccount(mock, "from1", "Alice", "100", "standard", "active", "", "0", "from1")
+	expectLockAccount(mock, "to1", "Bob", "50", "standard", "active", "", "0", "to1")
+	expectNoPayee(mock, "from1", "to1")
+
 	// 更新餘額
 	mock.ExpectExec(`UPDATE accounts SET balance = .* WHERE id = .*`).
 		WithArgs(sqlmock.AnyArg(), "from1").
@@ -117,19 +297,622 @@ func TestTransfer(t *testing.T) {
 
 	//  寫入交易紀錄
 	mock.ExpectQuery(`INSERT INTO transactions`).
-		WithArgs("from1", 1, "30", sqlmock.AnyArg(), "Transfer to Bob").
+		WithArgs("from1", 1, domain.TxKindTransferOut, "30", sqlmock.AnyArg(), "Transfer to Bob", "req-1", sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 	mock.ExpectQuery(`INSERT INTO transactions`).
-		WithArgs("to1", 2, "30", sqlmock.AnyArg(), "Transfer from Alice").
+		WithArgs("to1", 2, domain.TxKindTransferIn, "30", sqlmock.AnyArg(), "Transfer from Alice", "req-1", sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	// 寫入雙方稽核紀錄
+	expectAudit(mock, "from1", "req-1")
+	expectAudit(mock, "to1", "req-1")
+	expectEvent(mock, domain.EventTransferCompleted, "from1", "req-1")
 	// mock commit
 	mock.ExpectCommit()
 
-	// 執行轉帳
-	req := &request.TransferRequest{FromID: "from1", ToID: "to1", Amount: decimal.NewFromInt(30)}
-	refID, err := svc.Transfer(req)
+	// 執行轉帳 (request ID 應寫入交易紀錄)
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+	req := &request.TransferRequest{FromAccount: "from1", ToAccount: "to1", Amount: decimal.NewFromInt(30)}
+	res, err := svc.Transfer(ctx, req)
 
 	assert.NoError(t, err)
-	assert.NotEmpty(t, refID)
-	assert.True(t, uuid.Validate(refID) == nil)
+	assert.Equal(t, domain.TransferStatusCompleted, res.Status)
+	assert.True(t, uuid.Validate(res.RefID) == nil)
+}
+
+// 單元測試 Transfer 風控規則要求審核：建立待處理轉帳，不異動餘額
+func TestTransfer_HeldForReview(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, AuditRepository: &repository.AuditRepository{},
+		PendingTransferRepository: &repository.PendingTransferRepository{}, FraudRepository: &repository.FraudRepository{},
+		Fraud: fraud.NewEngine(fraud.Config{Enabled: true, NewPayee: fraud.NewPayeeConfig{Amount: decimal.NewFromInt(500), Action: fraud.Review}})}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("from1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("from1", "Alice", "1000", "standard", "active", "", "0", "from1"))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("to1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("to1", "Bob", "50", "standard", "active", "", "0", "to1"))
+	expectLockAccount(mock, "from1", "Alice", "1000", "standard", "active", "", "0", "from1")
+	expectLockAccount(mock, "to1", "Bob", "50", "standard", "active", "", "0", "to1")
+	expectNoPayee(mock, "from1", "to1")
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs("from1", "to1", domain.TxKindTransferOut, domain.TxKindTransferIn).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(`INSERT INTO pending_transfers`).
+		WithArgs("from1", "to1", "600", domain.PendingReasonFraudReview, domain.PendingTransferPending, "system", "", "0", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("7", time.Now()))
+	mock.ExpectQuery(`INSERT INTO fraud_decisions`).
+		WithArgs("from1", "to1", "600", fraud.Review, fraud.RuleNewPayee, sqlmock.AnyArg(), sqlmock.AnyArg(), "7", "", "").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+	expectPendingAction(mock, "7", domain.PendingActionRequested, "system", "")
+	expectAuditEntry(mock, domain.AuditTransferPending, "pending_transfer", "7", "")
+	mock.ExpectCommit()
+
+	res, err := svc.Transfer(context.Background(), &request.TransferRequest{FromAccount: "from1", ToAccount: "to1", Amount: decimal.NewFromInt(600)})
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.TransferResult{Status: domain.TransferStatusPendingReview, PendingTransferID: "7", Rule: fraud.RuleNewPayee}, res)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 Transfer 金額超過覆核門檻：保留款項並建立待覆核的轉帳
+func TestTransfer_RequiresApproval(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, AuditRepository: &repository.AuditRepository{},
+		PendingTransferRepository: &repository.PendingTransferRepository{},
+		Approval:                  approval.Config{Enabled: true, Threshold: decimal.NewFromInt(500), HoldFunds: true, Expiry: time.Hour}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("from1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("from1", "Alice", "1000", "standard", "active", "", "300", "from1"))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("to1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("to1", "Bob", "50", "standard", "active", "", "0", "to1"))
+	expectLockAccount(mock, "from1", "Alice", "1000", "standard", "active", "", "300", "from1")
+	expectLockAccount(mock, "to1", "Bob", "50", "standard", "active", "", "0", "to1")
+	expectNoPayee(mock, "from1", "to1")
+	mock.ExpectExec(`UPDATE accounts SET held_balance = held_balance \+ \$1 WHERE id = \$2`).
+		WithArgs("600", "from1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO pending_transfers`).
+		WithArgs("from1", "to1", "600", domain.PendingReasonApproval, domain.PendingTransferPending, "alice", "", "600", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("8", time.Now()))
+	expectPendingAction(mock, "8", domain.PendingActionRequested, "alice", "")
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT hash FROM audit_events`).WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+	mock.ExpectQuery(`INSERT INTO audit_events`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{ID: "alice", Role: auth.RoleUser})
+	res, err := svc.Transfer(ctx, &request.TransferRequest{FromAccount: "from1", ToAccount: "to1", Amount: decimal.NewFromInt(600)})
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.TransferResult{Status: domain.TransferStatusPendingApproval, PendingTransferID: "8"}, res)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 可用餘額不足以保留款項時不建立待覆核的轉帳
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("from1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("from1", "Alice", "1000", "standard", "active", "", "600", "from1"))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("to1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("to1", "Bob", "50", "standard", "active", "", "0", "to1"))
+	expectLockAccount(mock, "from1", "Alice", "1000", "standard", "active", "", "600", "from1")
+	expectLockAccount(mock, "to1", "Bob", "50", "standard", "active", "", "0", "to1")
+	expectNoPayee(mock, "from1", "to1")
+	mock.ExpectRollback()
+
+	_, err = svc.Transfer(ctx, &request.TransferRequest{FromAccount: "from1", ToAccount: "to1", Amount: decimal.NewFromInt(600)})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 開戶產生含檢查碼的帳號號碼，與既有號碼重複時重新產生
+func TestCreateAccount_AccountNumber(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, AuditRepository: &repository.AuditRepository{},
+		OutboxRepository: &repository.OutboxRepository{}, AccountNumbers: accountno.Config{Scheme: accountno.SchemeMod97, Prefix: "812", Length: 14}}
+
+	mock.ExpectBegin()
+	// 第一次產生的號碼重複 (ON CONFLICT DO NOTHING 不回傳資料)
+	mock.ExpectQuery(`INSERT INTO accounts`).
+		WithArgs("Alice", "0", domain.ProductStandard, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`INSERT INTO accounts`).
+		WithArgs("Alice", "0", domain.ProductStandard, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	expectAuditEntry(mock, domain.AuditAccountCreated, "account", "1", "")
+	expectEvent(mock, domain.EventAccountCreated, sqlmock.AnyArg(), "") // 帳號號碼為亂數產生
+	mock.ExpectCommit()
+
+	acc, err := svc.CreateAccount(context.Background(), &request.CreateAccountRequest{Name: "Alice"})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", acc.ID)
+	assert.True(t, svc.AccountNumbers.Valid(acc.Number), acc.Number)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 對外回應不包含內部 ID
+	body, _ := json.Marshal(acc)
+	assert.NotContains(t, string(body), `"id"`)
+	assert.Contains(t, string(body), `"account_number":"`+acc.Number+`"`)
+}
+
+// 單元測試 啟用 KYC 時開戶需指定已驗證的客戶，未指定名稱時使用主要持有人的姓名
+func TestCreateAccount_WithCustomers(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, AuditRepository: &repository.AuditRepository{},
+		OutboxRepository: &repository.OutboxRepository{}, CustomerRepository: &repository.CustomerRepository{}, KYC: kyc.Config{Enabled: true, HashKey: testHashKey},
+		AccountNumbers: accountno.DefaultConfig()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM customers WHERE public_id = \$1`).
+		WithArgs(aliceID).
+		WillReturnRows(customerRows(aliceID, "Alice Wang", domain.KYCVerified))
+	mock.ExpectQuery(`SELECT (.+) FROM customers WHERE public_id = \$1`).
+		WithArgs(bobID).
+		WillReturnRows(customerRows(bobID, "Bob Lin", domain.KYCVerified))
+	mock.ExpectQuery(`INSERT INTO accounts`).
+		WithArgs("Alice Wang", "0", domain.ProductStandard, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+	mock.ExpectQuery(`INSERT INTO account_holders`).
+		WithArgs("1", aliceID, domain.HolderPrimary).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	mock.ExpectQuery(`INSERT INTO account_holders`).
+		WithArgs("1", bobID, domain.HolderJoint).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	expectAuditEntry(mock, domain.AuditAccountCreated, "account", "1", "")
+	expectEvent(mock, domain.EventAccountCreated, sqlmock.AnyArg(), "") // 帳號號碼為亂數產生
+	mock.ExpectCommit()
+
+	acc, err := svc.CreateAccount(context.Background(), &request.CreateAccountRequest{CustomerIDs: []string{aliceID, bobID}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice Wang", acc.Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 未驗證的客戶不可開戶
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM customers WHERE public_id = \$1`).
+		WithArgs(carolID).
+		WillReturnRows(customerRows(carolID, "Carol Chen", domain.KYCRejected))
+	mock.ExpectRollback()
+
+	_, err = svc.CreateAccount(context.Background(), &request.CreateAccountRequest{CustomerIDs: []string{carolID}})
+	assert.ErrorIs(t, err, ErrKYCNotVerified)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	_, err = svc.CreateAccount(context.Background(), &request.CreateAccountRequest{Name: "Dave"})
+	assert.ErrorIs(t, err, ErrKYCNotVerified)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 啟用 KYC 時聯名持有人未通過驗證，不可轉出
+func TestTransfer_KYCNotVerified(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{},
+		CustomerRepository: &repository.CustomerRepository{}, KYC: kyc.Config{Enabled: true, HashKey: testHashKey}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("from1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("from1", "Alice", "1000", "standard", "active", "", "0", "from1"))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("to1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("to1", "Bob", "50", "standard", "active", "", "0", "to1"))
+	expectLockAccount(mock, "from1", "Alice", "1000", "standard", "active", "", "0", "from1")
+	expectLockAccount(mock, "to1", "Bob", "50", "standard", "active", "", "0", "to1")
+	mock.ExpectQuery(`FROM account_holders h JOIN customers c`).
+		WithArgs("from1").
+		WillReturnRows(holderRows().
+			AddRow("from1", "from1", aliceID, "Alice Wang", domain.HolderPrimary, domain.KYCVerified, time.Now()).
+			AddRow("from1", "from1", bobID, "Bob Lin", domain.HolderJoint, domain.KYCPending, time.Now()))
+	mock.ExpectRollback()
+
+	_, err := svc.Transfer(context.Background(), &request.TransferRequest{FromAccount: "from1", ToAccount: "to1", Amount: decimal.NewFromInt(10)})
+	assert.ErrorIs(t, err, ErrKYCNotVerified)
+	assert.ErrorContains(t, err, "customer "+bobID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 Transfer 風控規則拒絕：僅記錄決策並提交
+func TestTransfer_Blocked(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{},
+		FraudRepository: &repository.FraudRepository{}, Fraud: fraud.NewEngine(fraud.Config{Enabled: true, Blocklist: []string{"to1"}})}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("from1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("from1", "Alice", "1000", "standard", "active", "", "0", "from1"))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("to1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("to1", "Bob", "50", "standard", "active", "", "0", "to1"))
+	expectLockAccount(mock, "from1", "Alice", "1000", "standard", "active", "", "0", "from1")
+	expectLockAccount(mock, "to1", "Bob", "50", "standard", "active", "", "0", "to1")
+	expectNoPayee(mock, "from1", "to1")
+	mock.ExpectQuery(`INSERT INTO fraud_decisions`).
+		WithArgs("from1", "to1", "10", fraud.Block, fraud.RuleBlocklist, "account to1 is blocklisted", sqlmock.AnyArg(), "", "", "").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+	mock.ExpectCommit()
+
+	_, err := svc.Transfer(context.Background(), &request.TransferRequest{FromAccount: "from1", ToAccount: "to1", Amount: decimal.NewFromInt(10)})
+	assert.ErrorIs(t, err, ErrTransferBlocked)
+	assert.ErrorContains(t, err, "blocklist")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 Transfer 餘額不足 (應計入 metrics 且不寫入任何資料)
+func TestTransfer_InsufficientFunds(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}}
+	before := testutil.ToFloat64(metrics.InsufficientFunds.WithLabelValues(metrics.TypeTransfer))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("from1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("from1", "Alice", "10", "standard", "active", "", "0", "from1"))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("to1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("to1", "Bob", "50", "standard", "active", "", "0", "to1"))
+	expectLockAccount(mock, "from1", "Alice", "10", "standard", "active", "", "0", "from1")
+	expectLockAccount(mock, "to1", "Bob", "50", "standard", "active", "", "0", "to1")
+	expectNoPayee(mock, "from1", "to1")
+	mock.ExpectRollback()
+
+	req := &request.TransferRequest{FromAccount: "from1", ToAccount: "to1", Amount: decimal.NewFromInt(30)}
+	_, err := svc.Transfer(context.Background(), req)
+
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.Equal(t, before+1, testutil.ToFloat64(metrics.InsufficientFunds.WithLabelValues(metrics.TypeTransfer)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 Transfer 轉入帳號已凍結 (不寫入任何資料)
+func TestTransfer_FrozenAccount(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("from1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("from1", "Alice", "100", "standard", "active", "", "0", "from1"))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("to1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("to1", "Bob", "50", "standard", "frozen", "fraud review", "0", "to1"))
+	expectLockAccount(mock, "from1", "Alice", "100", "standard", "active", "", "0", "from1")
+	expectLockAccount(mock, "to1", "Bob", "50", "standard", "frozen", "fraud review", "0", "to1")
+	mock.ExpectRollback()
+
+	req := &request.TransferRequest{FromAccount: "from1", ToAccount: "to1", Amount: decimal.NewFromInt(30)}
+	_, err := svc.Transfer(context.Background(), req)
+
+	assert.ErrorIs(t, err, ErrAccountFrozen)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試對帳單 (期初 / 期末餘額由目前餘額回推)
+func TestStatement(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1`).
+		WithArgs("acc1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("acc1", "Alice", "150", "standard", "active", "", "0", "acc1"))
+	// 期初之後淨增加 70、期末之後淨增加 20
+	mock.ExpectQuery(`SELECT COALESCE\(SUM`).WithArgs("acc1", from).
+		WillReturnRows(sqlmock.NewRows([]string{"net"}).AddRow("70"))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM`).WithArgs("acc1", to).
+		WillReturnRows(sqlmock.NewRows([]string{"net"}).AddRow("20"))
+	mock.ExpectQuery(`SELECT t.id, (.+) ORDER BY t.id`).WithArgs("acc1", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "type", "kind", "amount", "ref_id", "description", "request_id", "business_date", "created_at"}).
+			AddRow(1, "Alice", 2, "deposit", "80", "r1", "", "", "2025-01-01", from.Add(time.Hour)).
+			AddRow(2, "Alice", 1, "withdrawal", "30", "r2", "", "", "2025-01-01", from.Add(2*time.Hour)))
+	mock.ExpectCommit()
+
+	st, err := svc.Statement(context.Background(), "acc1", from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, "80", st.OpeningBalance.String())  // 150 - 70
+	assert.Equal(t, "130", st.ClosingBalance.String()) // 150 - 20
+	assert.Equal(t, "80", st.TotalDeposits.String())
+	assert.Equal(t, "30", st.TotalWithdrawals.String())
+	assert.True(t, st.OpeningBalance.Add(st.TotalDeposits).Sub(st.TotalWithdrawals).Equal(st.ClosingBalance))
+	assert.Len(t, st.Transactions, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 歷史餘額：由最近一筆快照加上其後至 at 的交易，沒有快照時加總所有交易
+func TestBalanceAt(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{},
+		BalanceSnapshotRepository: &repository.BalanceSnapshotRepository{}}
+
+	at := time.Date(2026, 6, 30, 23, 59, 59, 0, time.UTC)
+	asOf := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	accountRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("acc1", "Alice", "150", "standard", "active", "", "0", "100000000016")
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1`).WithArgs("acc1").WillReturnRows(accountRows())
+	mock.ExpectQuery(`FROM balance_snapshots WHERE account_id = \$1 AND as_of <= \$2`).WithArgs("acc1", at).
+		WillReturnRows(sqlmock.NewRows([]string{"account_id", "as_of", "balance"}).AddRow("acc1", asOf, "1000"))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM(.+) AND created_at > \$2 AND created_at <= \$3`).WithArgs("acc1", asOf, at).
+		WillReturnRows(sqlmock.NewRows([]string{"net"}).AddRow("-250.5"))
+	mock.ExpectCommit()
+
+	b, err := svc.BalanceAt(context.Background(), "acc1", at)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.HistoricalBalance{AccountNumber: "100000000016", At: at, Balance: decimal.RequireFromString("749.5")}, b)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1`).WithArgs("acc1").WillReturnRows(accountRows())
+	mock.ExpectQuery(`FROM balance_snapshots`).WillReturnRows(sqlmock.NewRows([]string{"account_id", "as_of", "balance"}))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM`).WithArgs("acc1", time.Time{}, at).
+		WillReturnRows(sqlmock.NewRows([]string{"net"}).AddRow("80"))
+	mock.ExpectCommit()
+
+	b, err = svc.BalanceAt(context.Background(), "acc1", at)
+	assert.NoError(t, err)
+	assert.Equal(t, "80", b.Balance.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = svc.BalanceAt(context.Background(), "acc1", time.Now().Add(time.Hour))
+	assert.EqualError(t, err, "at cannot be in the future")
+}
+
+// 單元測試 餘額快照依帳號 ID 分批建立，直到沒有下一批
+func TestSnapshotBalances(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &AccountService{DB: db, BalanceSnapshotRepository: &repository.BalanceSnapshotRepository{}}
+
+	until := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`INSERT INTO balance_snapshots`).WithArgs(int64(0), 2, until).
+		WillReturnRows(sqlmock.NewRows([]string{"max", "count"}).AddRow(2, 2))
+	mock.ExpectQuery(`INSERT INTO balance_snapshots`).WithArgs(int64(2), 2, until).
+		WillReturnRows(sqlmock.NewRows([]string{"max", "count"}).AddRow(3, 0))
+	mock.ExpectQuery(`INSERT INTO balance_snapshots`).WithArgs(int64(3), 2, until).
+		WillReturnRows(sqlmock.NewRows([]string{"max", "count"}).AddRow(nil, 0))
+
+	n, err := svc.SnapshotBalances(context.Background(), until, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 Transfer 的 span 結構 (使用 in-memory exporter)
+func TestTransfer_Spans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(prev)
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("from1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("from1", "Alice", "100", "standard", "active", "", "0", "from1"))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("to1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("to1", "Bob", "50", "standard", "active", "", "0", "to1"))
+	expectLockAccount(mock, "from1", "Alice", "100", "standard", "active", "", "0", "from1")
+	expectLockAccount(mock, "to1", "Bob", "50", "standard", "active", "", "0", "to1")
+	expectNoPayee(mock, "from1", "to1")
+	mock.ExpectExec(`UPDATE accounts SET balance`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE accounts SET balance`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO transactions`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`INSERT INTO transactions`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	expectAudit(mock, "from1", "")
+	expectAudit(mock, "to1", "")
+	expectEvent(mock, domain.EventTransferCompleted, "from1", "")
+	mock.ExpectCommit()
+
+	req := &request.TransferRequest{FromAccount: "from1", ToAccount: "to1", Amount: decimal.NewFromInt(30)}
+	res, err := svc.Transfer(context.Background(), req)
+	assert.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	var root tracetest.SpanStub
+	var children []string
+	for _, s := range spans {
+		if s.Name == "AccountService.Transfer" {
+			root = s
+		}
+	}
+	assert.Equal(t, "AccountService.Transfer", root.Name)
+	assert.Contains(t, root.Attributes, attribute.String("bank.ref_id", res.RefID))
+	for _, s := range spans {
+		if s.Parent.SpanID() == root.SpanContext.SpanID() {
+			children = append(children, s.Name)
+			assert.Equal(t, root.SpanContext.TraceID(), s.SpanContext.TraceID())
+		}
+	}
+	assert.Equal(t, []string{
+		"AccountRepository.FindByNumber",
+		"AccountRepository.FindByNumber",
+		"AccountRepository.FindByIdForUpdate",
+		"AccountRepository.FindByIdForUpdate",
+		"PayeeRepository.FindByPayeeAccount",
+		"AccountRepository.UpdateBalance",
+		"AccountRepository.UpdateBalance",
+		"TransactionRepository.InsertTransactions",
+		"TransactionRepository.InsertTransactions",
+		"AuditRepository.LockLastHash",
+		"AuditRepository.Insert",
+		"AuditRepository.LockLastHash",
+		"AuditRepository.Insert",
+		"OutboxRepository.Insert",
+	}, children)
+
+	// repository span 需記錄 SQL 與影響筆數
+	for _, s := range spans {
+		if s.Name == "AccountRepository.UpdateBalance" {
+			assert.Contains(t, s.Attributes, attribute.String("db.query.text", `UPDATE accounts SET balance = $1 WHERE id = $2`))
+			assert.Contains(t, s.Attributes, attribute.Int64("db.rows_affected", 1))
+		}
+	}
+}
+
+// 單元測試 以收款人 ID 轉帳：冷靜期內單筆金額不可超過冷靜期上限
+func TestTransfer_ByPayeeCoolingOff(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{},
+		PayeeRepository: &repository.PayeeRepository{}, Payees: payee.Config{CoolingOff: 24 * time.Hour, CoolingOffLimit: decimal.NewFromInt(1000)}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("from1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("from1", "Alice", "5000", "standard", "active", "", "0", "from1"))
+	mock.ExpectQuery(`FROM payees p JOIN accounts a ON (.+) WHERE p.account_id = \$1 AND p.id = \$2`).
+		WithArgs("from1", "3").
+		WillReturnRows(payeeRows().AddRow("3", "from1", "to1", "to1", "Bob", "0", "0", time.Now().Add(time.Hour), time.Now(), time.Now()))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1`).
+		WithArgs("to1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("to1", "Bob", "50", "standard", "active", "", "0", "to1"))
+	expectLockAccount(mock, "from1", "Alice", "5000", "standard", "active", "", "0", "from1")
+	expectLockAccount(mock, "to1", "Bob", "50", "standard", "active", "", "0", "to1")
+	mock.ExpectRollback()
+
+	_, err := svc.Transfer(context.Background(), &request.TransferRequest{FromAccount: "from1", PayeeID: "3", Amount: decimal.NewFromInt(1500)})
+	assert.ErrorIs(t, err, ErrPayeeLimitExceeded)
+	assert.ErrorContains(t, err, "per-transfer limit for payee 3 is 1000 (cooling-off until")
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 不屬於轉出帳號的收款人
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("from1", "Alice", "5000", "standard", "active", "", "0", "from1"))
+	mock.ExpectQuery(`FROM payees p JOIN accounts a ON (.+) WHERE p.account_id = \$1 AND p.id = \$2`).
+		WithArgs("from1", "4").
+		WillReturnRows(payeeRows())
+	mock.ExpectRollback()
+
+	_, err = svc.Transfer(context.Background(), &request.TransferRequest{FromAccount: "from1", PayeeID: "4", Amount: decimal.NewFromInt(10)})
+	assert.ErrorIs(t, err, ErrPayeeNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 以帳號號碼轉給已約定的收款人時同樣適用近 24 小時累計上限，未約定的帳號適用冷靜期上限
+func TestTransfer_PayeeDailyLimit(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	svc := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{},
+		PayeeRepository: &repository.PayeeRepository{}, Payees: payee.DefaultConfig()}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("from1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("from1", "Alice", "5000", "standard", "active", "", "0", "from1"))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("to1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("to1", "Bob", "50", "standard", "active", "", "0", "to1"))
+	expectLockAccount(mock, "from1", "Alice", "5000", "standard", "active", "", "0", "from1")
+	expectLockAccount(mock, "to1", "Bob", "50", "standard", "active", "", "0", "to1")
+	past := time.Now().Add(-48 * time.Hour)
+	mock.ExpectQuery(`FROM payees p JOIN accounts a ON (.+) WHERE p.account_id = \$1 AND p.payee_account_id = \$2`).
+		WithArgs("from1", "to1").
+		WillReturnRows(payeeRows().AddRow("3", "from1", "to1", "to1", "Bob", "0", "2000", past, past, past))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(o.amount\), 0\)`).
+		WithArgs("from1", "to1", domain.TxKindTransferOut, domain.TxKindTransferIn, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow("1500"))
+	mock.ExpectRollback()
+
+	_, err := svc.Transfer(context.Background(), &request.TransferRequest{FromAccount: "from1", ToAccount: "to1", Amount: decimal.NewFromInt(600)})
+	assert.ErrorIs(t, err, ErrPayeeLimitExceeded)
+	assert.ErrorContains(t, err, "24-hour limit for payee 3 is 2000")
+	assert.NotContains(t, err.Error(), "cooling-off")
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 未約定 (或已刪除) 的收款帳號適用冷靜期上限，並計入刪除前已轉出的金額
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("from1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("from1", "Alice", "50000", "standard", "active", "", "0", "from1"))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("to1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("to1", "Bob", "50", "standard", "active", "", "0", "to1"))
+	expectLockAccount(mock, "from1", "Alice", "50000", "standard", "active", "", "0", "from1")
+	expectLockAccount(mock, "to1", "Bob", "50", "standard", "active", "", "0", "to1")
+	expectNoPayee(mock, "from1", "to1")
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\(o.amount\), 0\)`).
+		WithArgs("from1", "to1", domain.TxKindTransferOut, domain.TxKindTransferIn, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow("9500"))
+	mock.ExpectRollback()
+
+	_, err = svc.Transfer(context.Background(), &request.TransferRequest{FromAccount: "from1", ToAccount: "to1", Amount: decimal.NewFromInt(600)})
+	assert.ErrorIs(t, err, ErrPayeeLimitExceeded)
+	assert.ErrorContains(t, err, "24-hour limit for unregistered account is 10000")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 模擬在同一個 transaction 中寫入稽核紀錄
+func expectAudit(mock sqlmock.Sqlmock, accountID, requestID string) {
+	expectAuditEntry(mock, domain.AuditAccountBalanceChanged, "account", accountID, requestID)
+}
+
+// 模擬在同一個 transaction 中寫入指定動作的稽核紀錄
+func expectAuditEntry(mock sqlmock.Sqlmock, action, entityType, entityID, requestID string) {
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT hash FROM audit_events`).WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+	mock.ExpectQuery(`INSERT INTO audit_events`).
+		WithArgs(action, entityType, entityID, "system", "", requestID,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), repository.GenesisHash, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+// 模擬寫入待處理轉帳的處理紀錄
+func expectPendingAction(mock sqlmock.Sqlmock, id, action, actor, note string) {
+	mock.ExpectQuery(`INSERT INTO pending_transfer_history`).
+		WithArgs(id, action, actor, note, "").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("1", time.Now()))
+}
+
+// 模擬在同一個 transaction 中寫入 outbox 事件
+func expectEvent(mock sqlmock.Sqlmock, eventType string, aggregate any, requestID string) {
+	mock.ExpectQuery(`INSERT INTO outbox_events`).
+		WithArgs(eventType, aggregate, requestID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+// 模擬在 transaction 內鎖定帳號，row 為帳號各欄位的值
+func expectLockAccount(mock sqlmock.Sqlmock, row ...driver.Value) {
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1 FOR UPDATE`).
+		WithArgs(row[0]).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow(row...))
+}
+
+// 模擬轉入帳號不是轉出帳號的收款人
+func expectNoPayee(mock sqlmock.Sqlmock, fromID, toID string) {
+	mock.ExpectQuery(`FROM payees p JOIN accounts a ON (.+) WHERE p.account_id = \$1 AND p.payee_account_id = \$2`).
+		WithArgs(fromID, toID).
+		WillReturnError(sql.ErrNoRows)
 }
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/logging"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+)
+
+// 驗證 hash chain 時每批讀取的筆數
+const auditVerifyBatch = 1000
+
+type AuditService struct {
+	DB              *sql.DB
+	AuditRepository *repository.AuditRepository
+}
+
+// 查詢稽核紀錄
+func (s *AuditService) FindEvents(ctx context.Context, filter repository.AuditFilter) (_ []*domain.AuditEvent, err error) {
+	ctx, span := tracing.Start(ctx, "AuditService.FindEvents")
+	defer func() { tracing.End(span, err) }()
+
+	return s.AuditRepository.Find(ctx, s.DB, filter)
+}
+
+// 從第一筆開始重算 hash chain，找出第一筆被竄改的事件
+func (s *AuditService) Verify(ctx context.Context) (_ *domain.AuditVerification, err error) {
+	ctx, span := tracing.Start(ctx, "AuditService.Verify")
+	defer func() { tracing.End(span, err) }()
+
+	result := &domain.AuditVerification{Valid: true}
+	prev := repository.GenesisHash
+	var afterID int64
+	for {
+		events, err := s.AuditRepository.Find(ctx, s.DB, repository.AuditFilter{AfterID: afterID, Limit: auditVerifyBatch})
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range events {
+			result.EventsChecked++
+			if ev.PrevHash != prev {
+				return broken(result, ev.ID, "prev_hash does not match the previous event"), nil
+			}
+			if auditHash(ev) != ev.Hash {
+				return broken(result, ev.ID, "hash does not match event content"), nil
+			}
+			prev = ev.Hash
+			afterID = ev.ID
+		}
+		if len(events) < auditVerifyBatch {
+			return result, nil
+		}
+	}
+}
+
+func broken(result *domain.AuditVerification, id int64, reason string) *domain.AuditVerification {
+	result.Valid = false
+	result.BrokenAtID = id
+	result.Reason = reason
+	return result
+}
+
+// recordAudit 在同一個 SQL transaction 中寫入稽核事件
+// 操作者、來源 IP 與 request ID 取自 context
+func recordAudit(ctx context.Context, repo *repository.AuditRepository, db repository.DBTX,
+	action, entityType, entityID string, before, after any) error {
+	ev := &domain.AuditEvent{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Actor:      auth.Actor(ctx),
+		SourceIP:   auth.ClientIP(ctx),
+		RequestID:  logging.RequestID(ctx),
+		CreatedAt:  time.Now().UTC().Truncate(time.Microsecond), // PostgreSQL 只保存到微秒
+	}
+	var err error
+	if ev.Before, err = snapshot(before); err != nil {
+		return err
+	}
+	if ev.After, err = snapshot(after); err != nil {
+		return err
+	}
+	if ev.PrevHash, err = repo.LockLastHash(ctx, db); err != nil {
+		return err
+	}
+	ev.Hash = auditHash(ev)
+	return repo.Insert(ctx, db, ev)
+}
+
+func snapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// auditHash = SHA-256(prev_hash 與事件內容)，任一欄位被修改都會使 hash 不符
+func auditHash(ev *domain.AuditEvent) string {
+	payload := strings.Join([]string{
+		ev.PrevHash,
+		ev.Action,
+		ev.EntityType,
+		ev.EntityID,
+		ev.Actor,
+		ev.SourceIP,
+		ev.RequestID,
+		string(ev.Before),
+		string(ev.After),
+		ev.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}, "\x1f")
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+)
+
+var auditColumns = []string{"id", "action", "entity_type", "entity_id", "actor", "source_ip", "request_id",
+	"before_state", "after_state", "prev_hash", "hash", "created_at"}
+
+// 建立一條合法的 hash chain
+func auditChain(n int) []*domain.AuditEvent {
+	prev := repository.GenesisHash
+	events := make([]*domain.AuditEvent, 0, n)
+	for i := 1; i <= n; i++ {
+		ev := &domain.AuditEvent{
+			ID:         int64(i),
+			Action:     domain.AuditAccountBalanceChanged,
+			EntityType: "account",
+			EntityID:   "1",
+			Actor:      "alice",
+			SourceIP:   "10.0.0.1",
+			Before:     json.RawMessage(`{"id":"1","name":"Alice","balance":"100"}`),
+			After:      json.RawMessage(`{"id":"1","name":"Alice","balance":"150"}`),
+			PrevHash:   prev,
+			CreatedAt:  time.Date(2026, 1, 1, 0, 0, i, 0, time.UTC),
+		}
+		ev.Hash = auditHash(ev)
+		prev = ev.Hash
+		events = append(events, ev)
+	}
+	return events
+}
+
+func auditRows(events []*domain.AuditEvent) *sqlmock.Rows {
+	rows := sqlmock.NewRows(auditColumns)
+	for _, ev := range events {
+		rows.AddRow(ev.ID, ev.Action, ev.EntityType, ev.EntityID, ev.Actor, ev.SourceIP, ev.RequestID,
+			[]byte(ev.Before), []byte(ev.After), ev.PrevHash, ev.Hash, ev.CreatedAt)
+	}
+	return rows
+}
+
+// 單元測試 Verify：完整的 hash chain 驗證通過
+func TestAuditVerify_Valid(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &AuditService{DB: db, AuditRepository: &repository.AuditRepository{}}
+
+	mock.ExpectQuery(`SELECT (.+) FROM audit_events ORDER BY id LIMIT \$1`).
+		WillReturnRows(auditRows(auditChain(3)))
+
+	result, err := svc.Verify(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.AuditVerification{Valid: true, EventsChecked: 3}, result)
+}
+
+// 單元測試 Verify：竄改快照或刪除事件皆可偵測
+func TestAuditVerify_Tampered(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &AuditService{DB: db, AuditRepository: &repository.AuditRepository{}}
+
+	// 修改第 2 筆的異動後餘額
+	events := auditChain(3)
+	events[1].After = json.RawMessage(`{"id":"1","name":"Alice","balance":"999"}`)
+	mock.ExpectQuery(`SELECT (.+) FROM audit_events`).WillReturnRows(auditRows(events))
+
+	result, err := svc.Verify(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(2), result.BrokenAtID)
+	assert.Equal(t, "hash does not match event content", result.Reason)
+
+	// 刪除第 2 筆
+	events = auditChain(3)
+	mock.ExpectQuery(`SELECT (.+) FROM audit_events`).
+		WillReturnRows(auditRows([]*domain.AuditEvent{events[0], events[2]}))
+
+	result, err = svc.Verify(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(3), result.BrokenAtID)
+	assert.Equal(t, "prev_hash does not match the previous event", result.Reason)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/businessday"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+)
+
+// ErrBusinessDateClosed 營業日已結帳，不可再寫入或修改交易
+var ErrBusinessDateClosed = errors.New("business date is closed")
+
+// ErrBusinessDateOpen 營業日尚未結束或尚未結帳
+var ErrBusinessDateOpen = errors.New("business date is not closed")
+
+// BusinessDayService 日終結帳：鎖定營業日並寫入每個帳號的日終快照
+type BusinessDayService struct {
+	DB                    *sql.DB
+	BusinessDayRepository *repository.BusinessDayRepository
+	AuditRepository       *repository.AuditRepository
+	BusinessDay           businessday.Config
+}
+
+// 目前的營業日與結帳進度
+func (s *BusinessDayService) Status(ctx context.Context) (_ *domain.BusinessDayStatus, err error) {
+	ctx, span := tracing.Start(ctx, "BusinessDayService.Status")
+	defer func() { tracing.End(span, err) }()
+
+	closed, err := s.BusinessDayRepository.ClosedThrough(ctx, s.DB)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.BusinessDayStatus{
+		BusinessDate:  s.BusinessDay.Today(time.Now()),
+		ClosedThrough: closed,
+		TimeZone:      s.BusinessDay.Zone(),
+	}, nil
+}
+
+// 結帳 date (含) 之前所有尚未結帳的營業日，依序寫入日終快照
+// 只能結帳已結束的營業日；首次結帳只為 date 建立快照，之前的營業日一併鎖定
+func (s *BusinessDayService) CloseBusinessDate(ctx context.Context, date string) (_ *domain.BusinessDayClose, err error) {
+	ctx, span := tracing.Start(ctx, "BusinessDayService.CloseBusinessDate")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := businessday.ParseDate(date); err != nil {
+		return nil, err
+	}
+	if today := s.BusinessDay.Today(time.Now()); date >= today {
+		return nil, fmt.Errorf("%w: %s has not ended (current business date is %s)", ErrBusinessDateOpen, date, today)
+	}
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	// 等待進行中寫入交易的 transaction 提交，之後的寫入由 trigger 檢查結帳紀錄
+	if err := s.BusinessDayRepository.LockClose(ctx, transaction); err != nil {
+		return nil, err
+	}
+	closed, err := s.BusinessDayRepository.ClosedThrough(ctx, transaction)
+	if err != nil {
+		return nil, err
+	}
+	if closed != "" && date <= closed {
+		return nil, fmt.Errorf("%w: closed through %s", ErrBusinessDateClosed, closed)
+	}
+	result := &domain.BusinessDayClose{BusinessDate: date, ClosedBy: auth.Actor(ctx)}
+	from := date
+	if closed != "" {
+		from = businessday.AddDays(closed, 1)
+	}
+	for d := from; d <= date; d = businessday.AddDays(d, 1) {
+		n, err := s.BusinessDayRepository.InsertEODBalances(ctx, transaction, d, s.BusinessDay.Zone())
+		if err != nil {
+			return nil, fmt.Errorf("snapshot business date %s: %w", d, err)
+		}
+		result.ClosedDates = append(result.ClosedDates, d)
+		result.Snapshots += int(n)
+	}
+	if result.ClosedAt, err = s.BusinessDayRepository.InsertClose(ctx, transaction, date, result.ClosedBy); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.AuditRepository, transaction, domain.AuditBusinessDayClosed, "business_day", date, nil, result); err != nil {
+		return nil, err
+	}
+	return result, transaction.Commit()
+}
+
+// 供日終排程使用：結帳 through (含) 之前尚未結帳的營業日，已結帳時不視為錯誤
+func (s *BusinessDayService) CloseOpenDates(ctx context.Context, through string) (int, error) {
+	result, err := s.CloseBusinessDate(ctx, through)
+	if errors.Is(err, ErrBusinessDateClosed) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(result.ClosedDates), nil
+}
+
+// 查詢已結帳營業日的日終快照
+func (s *BusinessDayService) FindEODBalances(ctx context.Context, date string) (_ []*domain.EODBalance, err error) {
+	ctx, span := tracing.Start(ctx, "BusinessDayService.FindEODBalances")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := businessday.ParseDate(date); err != nil {
+		return nil, err
+	}
+	closed, err := s.BusinessDayRepository.ClosedThrough(ctx, s.DB)
+	if err != nil {
+		return nil, err
+	}
+	if closed == "" || date > closed {
+		return nil, fmt.Errorf("%w: %s", ErrBusinessDateOpen, date)
+	}
+	balances, err := s.BusinessDayRepository.FindEODBalances(ctx, s.DB, date)
+	if err != nil {
+		return nil, err
+	}
+	if balances == nil {
+		balances = []*domain.EODBalance{}
+	}
+	return balances, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/businessday"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+)
+
+// 單元測試 日終結帳：補齊上次結帳之後的營業日，依序寫入日終快照
+func TestCloseBusinessDate(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &BusinessDayService{
+		DB:                    db,
+		BusinessDayRepository: &repository.BusinessDayRepository{},
+		AuditRepository:       &repository.AuditRepository{},
+		BusinessDay:           businessday.Config{TimeZone: "Asia/Taipei"},
+	}
+	today := svc.BusinessDay.Today(time.Now())
+	closed, date := businessday.AddDays(today, -4), businessday.AddDays(today, -2)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\('business_day_close'\)\)`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT (.+) FROM business_day_closes`).
+		WillReturnRows(sqlmock.NewRows([]string{"date"}).AddRow(closed))
+	mock.ExpectExec(`INSERT INTO eod_balances`).
+		WithArgs(businessday.AddDays(today, -3), "Asia/Taipei").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO eod_balances`).
+		WithArgs(date, "Asia/Taipei").
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectQuery(`INSERT INTO business_day_closes`).
+		WithArgs(date, "system").
+		WillReturnRows(sqlmock.NewRows([]string{"closed_at"}).AddRow(time.Now()))
+	expectAuditEntry(mock, domain.AuditBusinessDayClosed, "business_day", date, "")
+	mock.ExpectCommit()
+
+	result, err := svc.CloseBusinessDate(context.Background(), date)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{businessday.AddDays(today, -3), date}, result.ClosedDates)
+	assert.Equal(t, 7, result.Snapshots)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 不可結帳尚未結束或已結帳的營業日
+func TestCloseBusinessDate_Rejected(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &BusinessDayService{
+		DB:                    db,
+		BusinessDayRepository: &repository.BusinessDayRepository{},
+		AuditRepository:       &repository.AuditRepository{},
+		BusinessDay:           businessday.Config{TimeZone: "Asia/Taipei"},
+	}
+	today := svc.BusinessDay.Today(time.Now())
+
+	_, err := svc.CloseBusinessDate(context.Background(), today)
+	assert.ErrorIs(t, err, ErrBusinessDateOpen)
+	_, err = svc.CloseBusinessDate(context.Background(), "2026/01/01")
+	assert.ErrorContains(t, err, "expected YYYY-MM-DD")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT (.+) FROM business_day_closes`).
+		WillReturnRows(sqlmock.NewRows([]string{"date"}).AddRow(businessday.AddDays(today, -1)))
+	mock.ExpectRollback()
+
+	n, err := svc.CloseOpenDates(context.Background(), businessday.AddDays(today, -1))
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/kyc"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+)
+
+// ErrKYCNotVerified 客戶尚未通過身分驗證
+var ErrKYCNotVerified = errors.New("customer KYC is not verified")
+
+// ErrCustomerNotFound 指定的客戶不存在
+var ErrCustomerNotFound = errors.New("customer not found")
+
+// ErrCustomerExists 身分證號已登記為其他客戶
+var ErrCustomerExists = errors.New("customer with this national ID already exists")
+
+// ErrHolderExists 客戶已是帳號的持有人
+var ErrHolderExists = errors.New("customer is already a holder of this account")
+
+// CustomerService 客戶資料、KYC 狀態與帳號持有人
+type CustomerService struct {
+	DB                 *sql.DB
+	CustomerRepository *repository.CustomerRepository
+	AccountRepository  *repository.AccountRepository
+	AuditRepository    *repository.AuditRepository
+	KYC                kyc.Config
+}
+
+// 建立客戶，KYC 狀態為 pending，身分證號僅保存雜湊
+func (s *CustomerService) CreateCustomer(ctx context.Context, req *request.CreateCustomerRequest) (_ *domain.Customer, err error) {
+	ctx, span := tracing.Start(ctx, "CustomerService.CreateCustomer")
+	defer func() { tracing.End(span, err) }()
+
+	c := &domain.Customer{
+		LegalName:      strings.TrimSpace(req.LegalName),
+		DateOfBirth:    req.DateOfBirth,
+		NationalIDHash: kyc.HashNationalID(s.KYC.HashKey, req.NationalID),
+		Email:          req.Email,
+		Phone:          req.Phone,
+		Address:        req.Address,
+		KYCStatus:      domain.KYCPending,
+	}
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	if err := s.CustomerRepository.Insert(ctx, transaction, c); err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrCustomerExists
+		}
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.AuditRepository, transaction, domain.AuditCustomerCreated, "customer", c.ID, nil, c); err != nil {
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// 查詢客戶與持有的帳號
+func (s *CustomerService) FindCustomer(ctx context.Context, id string) (_ *domain.Customer, err error) {
+	ctx, span := tracing.Start(ctx, "CustomerService.FindCustomer")
+	defer func() { tracing.End(span, err) }()
+
+	c, err := s.CustomerRepository.FindById(ctx, s.DB, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Accounts, err = s.CustomerRepository.FindAccounts(ctx, s.DB, id); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// 更新客戶的 KYC 狀態
+func (s *CustomerService) SetKYCStatus(ctx context.Context, id, status, note string) (_ *domain.Customer, err error) {
+	ctx, span := tracing.Start(ctx, "CustomerService.SetKYCStatus")
+	defer func() { tracing.End(span, err) }()
+
+	switch status {
+	case domain.KYCPending, domain.KYCVerified, domain.KYCRejected:
+	default:
+		return nil, fmt.Errorf("unknown KYC status %q", status)
+	}
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	before, err := s.CustomerRepository.FindById(ctx, transaction, id)
+	if err != nil {
+		return nil, err
+	}
+	after := *before
+	after.KYCStatus, after.KYCNote = status, note
+	if err := s.CustomerRepository.UpdateKYC(ctx, transaction, &after); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.AuditRepository, transaction, domain.AuditCustomerKYCChanged, "customer", id, before, &after); err != nil {
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// 查詢帳號的持有人
+func (s *CustomerService) FindAccountHolders(ctx context.Context, accountID string) (_ []*domain.AccountHolder, err error) {
+	ctx, span := tracing.Start(ctx, "CustomerService.FindAccountHolders")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := s.AccountRepository.FindById(ctx, s.DB, accountID); err != nil {
+		return nil, err
+	}
+	holders, err := s.CustomerRepository.FindHolders(ctx, s.DB, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if holders == nil {
+		holders = []*domain.AccountHolder{}
+	}
+	return holders, nil
+}
+
+// 新增帳號持有人，帳號尚無持有人時成為主要持有人，否則為聯名持有人
+// 啟用 KYC 時客戶需已通過驗證
+func (s *CustomerService) AddAccountHolder(ctx context.Context, accountID, customerID string) (_ *domain.AccountHolder, err error) {
+	ctx, span := tracing.Start(ctx, "CustomerService.AddAccountHolder")
+	defer func() { tracing.End(span, err) }()
+
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	acc, err := s.AccountRepository.FindById(ctx, transaction, accountID)
+	if err != nil {
+		return nil, err
+	}
+	c, err := findCustomer(ctx, s.CustomerRepository, transaction, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if s.KYC.Enabled && c.KYCStatus != domain.KYCVerified {
+		return nil, fmt.Errorf("%w: customer %s is %s", ErrKYCNotVerified, c.ID, c.KYCStatus)
+	}
+	existing, err := s.CustomerRepository.FindHolders(ctx, transaction, accountID)
+	if err != nil {
+		return nil, err
+	}
+	h := &domain.AccountHolder{AccountID: acc.ID, AccountNumber: acc.Number, CustomerID: c.ID, LegalName: c.LegalName, Role: domain.HolderJoint, KYCStatus: c.KYCStatus}
+	if len(existing) == 0 {
+		h.Role = domain.HolderPrimary
+	}
+	if err := s.CustomerRepository.AddHolder(ctx, transaction, h); err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrHolderExists
+		}
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.AuditRepository, transaction, domain.AuditAccountHolderAdded, "account", accountID, nil, h); err != nil {
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// 查詢指定的客戶，不存在時回傳 ErrCustomerNotFound (與帳號不存在區分)
+func findCustomer(ctx context.Context, repo *repository.CustomerRepository, db repository.DBTX, id string) (*domain.Customer, error) {
+	c, err := repo.FindById(ctx, db, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrCustomerNotFound, id)
+	}
+	return c, err
+}
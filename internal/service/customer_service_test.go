@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/kyc"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+)
+
+const testHashKey = "0123456789abcdef"
+
+// 測試用的客戶 ID (隨機 UUID)
+const (
+	aliceID = "0b6f4c8e-5c1a-4e0e-9d7a-6a1f3e2b7c05"
+	bobID   = "0b6f4c8e-5c1a-4e0e-9d7a-6a1f3e2b7c06"
+	carolID = "0b6f4c8e-5c1a-4e0e-9d7a-6a1f3e2b7c07"
+)
+
+func customerRows(id, name, status string) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"public_id", "legal_name", "date_of_birth", "national_id_hash", "email", "phone", "address",
+		"kyc_status", "kyc_note", "created_at", "updated_at"}).
+		AddRow(id, name, "1990-01-01", "hash", "", "", "", status, "", time.Now(), time.Now())
+}
+
+func holderRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"account_id", "account_number", "public_id", "legal_name", "role", "kyc_status", "created_at"})
+}
+
+// 單元測試 建立客戶：只保存身分證號雜湊，重複的證號回傳 ErrCustomerExists
+func TestCreateCustomer(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &CustomerService{
+		DB:                 db,
+		CustomerRepository: &repository.CustomerRepository{},
+		AccountRepository:  &repository.AccountRepository{},
+		AuditRepository:    &repository.AuditRepository{},
+		KYC:                kyc.Config{HashKey: testHashKey},
+	}
+	req := &request.CreateCustomerRequest{LegalName: "Alice Wang", DateOfBirth: "1990-01-01", NationalID: "A123456789"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO customers`).
+		WithArgs("Alice Wang", "1990-01-01", kyc.HashNationalID(testHashKey, "A123456789"), "", "", "", domain.KYCPending).
+		WillReturnRows(sqlmock.NewRows([]string{"public_id", "created_at", "updated_at"}).AddRow(aliceID, time.Now(), time.Now()))
+	expectAuditEntry(mock, domain.AuditCustomerCreated, "customer", aliceID, "")
+	mock.ExpectCommit()
+
+	c, err := svc.CreateCustomer(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, aliceID, c.ID)
+	assert.Equal(t, domain.KYCPending, c.KYCStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO customers`).WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err = svc.CreateCustomer(context.Background(), req)
+	assert.ErrorIs(t, err, ErrCustomerExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 新增聯名持有人：帳號已有主要持有人時為 joint
+func TestAddAccountHolder(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &CustomerService{
+		DB:                 db,
+		CustomerRepository: &repository.CustomerRepository{},
+		AccountRepository:  &repository.AccountRepository{},
+		AuditRepository:    &repository.AuditRepository{},
+		KYC:                kyc.Config{Enabled: true, HashKey: testHashKey},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1`).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("1", "Alice", "100", "standard", "active", "", "0", "1"))
+	mock.ExpectQuery(`SELECT (.+) FROM customers WHERE public_id = \$1`).
+		WithArgs(bobID).
+		WillReturnRows(customerRows(bobID, "Bob Lin", domain.KYCVerified))
+	mock.ExpectQuery(`FROM account_holders h JOIN customers c`).
+		WithArgs("1").
+		WillReturnRows(holderRows().AddRow("1", "1", aliceID, "Alice Wang", domain.HolderPrimary, domain.KYCVerified, time.Now()))
+	mock.ExpectQuery(`INSERT INTO account_holders`).
+		WithArgs("1", bobID, domain.HolderJoint).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+	expectAuditEntry(mock, domain.AuditAccountHolderAdded, "account", "1", "")
+	mock.ExpectCommit()
+
+	h, err := svc.AddAccountHolder(context.Background(), "1", bobID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.HolderJoint, h.Role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 啟用 KYC 時未驗證的客戶不可成為持有人
+func TestAddAccountHolder_KYCNotVerified(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &CustomerService{
+		DB:                 db,
+		CustomerRepository: &repository.CustomerRepository{},
+		AccountRepository:  &repository.AccountRepository{},
+		AuditRepository:    &repository.AuditRepository{},
+		KYC:                kyc.Config{Enabled: true, HashKey: testHashKey},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE id = \$1`).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("1", "Alice", "100", "standard", "active", "", "0", "1"))
+	mock.ExpectQuery(`SELECT (.+) FROM customers WHERE public_id = \$1`).
+		WithArgs(bobID).
+		WillReturnRows(customerRows(bobID, "Bob Lin", domain.KYCPending))
+	mock.ExpectRollback()
+
+	_, err := svc.AddAccountHolder(context.Background(), "1", bobID)
+	assert.ErrorIs(t, err, ErrKYCNotVerified)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
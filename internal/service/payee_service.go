@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/payee"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+)
+
+// ErrPayeeNotFound 收款人不存在或不屬於該帳號
+var ErrPayeeNotFound = errors.New("payee not found")
+
+// ErrPayeeAccountNotFound 收款帳號不存在
+var ErrPayeeAccountNotFound = errors.New("payee account not found")
+
+// ErrPayeeExists 收款帳號或暱稱已登記為該帳號的其他收款人
+var ErrPayeeExists = errors.New("payee with this account number or nickname already exists")
+
+// ErrPayeeSelf 不可將帳號本身設為收款人
+var ErrPayeeSelf = errors.New("cannot add the account itself as a payee")
+
+// ErrTooManyPayees 帳號的收款人數量已達上限
+var ErrTooManyPayees = errors.New("too many payees")
+
+// ErrPayeeLimitExceeded 轉帳金額超過收款人的上限，或超過未約定帳號適用的冷靜期上限
+var ErrPayeeLimitExceeded = errors.New("payee transfer limit exceeded")
+
+// PayeeService 帳號的收款人 (約定轉入帳號)
+type PayeeService struct {
+	DB                *sql.DB
+	PayeeRepository   *repository.PayeeRepository
+	AccountRepository *repository.AccountRepository
+	AuditRepository   *repository.AuditRepository
+	Payees            payee.Config
+}
+
+// 查詢帳號的收款人
+func (s *PayeeService) FindPayees(ctx context.Context, accountID string) (_ []*domain.Payee, err error) {
+	ctx, span := tracing.Start(ctx, "PayeeService.FindPayees")
+	defer func() { tracing.End(span, err) }()
+
+	payees, err := s.PayeeRepository.FindByAccount(ctx, s.DB, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if payees == nil {
+		payees = []*domain.Payee{}
+	}
+	return payees, nil
+}
+
+// 新增收款人，冷靜期自新增時起算
+func (s *PayeeService) AddPayee(ctx context.Context, accountID string, req *request.CreatePayeeRequest) (_ *domain.Payee, err error) {
+	ctx, span := tracing.Start(ctx, "PayeeService.AddPayee")
+	defer func() { tracing.End(span, err) }()
+
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	n, err := s.PayeeRepository.Count(ctx, transaction, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if n >= s.Payees.MaxPerAccount {
+		return nil, fmt.Errorf("%w: account already has %d payees", ErrTooManyPayees, n)
+	}
+	target, err := s.AccountRepository.FindByNumber(ctx, transaction, req.AccountNumber)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrPayeeAccountNotFound, req.AccountNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if target.ID == accountID {
+		return nil, ErrPayeeSelf
+	}
+	p := &domain.Payee{
+		AccountID:       accountID,
+		PayeeAccountID:  target.ID,
+		AccountNumber:   target.Number,
+		Nickname:        strings.TrimSpace(req.Nickname),
+		TransferLimit:   req.TransferLimit,
+		DailyLimit:      req.DailyLimit,
+		CoolingOffUntil: s.Payees.CoolingOffUntil(time.Now()),
+	}
+	if err := s.PayeeRepository.Insert(ctx, transaction, p); err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrPayeeExists
+		}
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.AuditRepository, transaction, domain.AuditPayeeAdded, "payee", p.ID, nil, p); err != nil {
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// 更新收款人暱稱與上限，提高或取消任一上限時重新起算冷靜期
+func (s *PayeeService) UpdatePayee(ctx context.Context, accountID, id string, req *request.UpdatePayeeRequest) (_ *domain.Payee, err error) {
+	ctx, span := tracing.Start(ctx, "PayeeService.UpdatePayee")
+	defer func() { tracing.End(span, err) }()
+
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	before, err := findPayee(ctx, s.PayeeRepository, transaction, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+	after := *before
+	after.Nickname = strings.TrimSpace(req.Nickname)
+	after.TransferLimit, after.DailyLimit = req.TransferLimit, req.DailyLimit
+	if raisesLimit(before.TransferLimit, after.TransferLimit) || raisesLimit(before.DailyLimit, after.DailyLimit) {
+		if until := s.Payees.CoolingOffUntil(time.Now()); until.After(after.CoolingOffUntil) {
+			after.CoolingOffUntil = until
+		}
+	}
+	if err := s.PayeeRepository.Update(ctx, transaction, &after); err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrPayeeExists
+		}
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.AuditRepository, transaction, domain.AuditPayeeUpdated, "payee", id, before, &after); err != nil {
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
+
+// 刪除收款人
+func (s *PayeeService) DeletePayee(ctx context.Context, accountID, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "PayeeService.DeletePayee")
+	defer func() { tracing.End(span, err) }()
+
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer transaction.Rollback()
+
+	before, err := findPayee(ctx, s.PayeeRepository, transaction, accountID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.PayeeRepository.Delete(ctx, transaction, accountID, id); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, s.AuditRepository, transaction, domain.AuditPayeeDeleted, "payee", id, before, nil); err != nil {
+		return err
+	}
+	return transaction.Commit()
+}
+
+// 查詢帳號的收款人，不存在時回傳 ErrPayeeNotFound (與帳號不存在區分)
+func findPayee(ctx context.Context, repo *repository.PayeeRepository, db repository.DBTX, accountID, id string) (*domain.Payee, error) {
+	p, err := repo.FindById(ctx, db, accountID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrPayeeNotFound, id)
+	}
+	return p, err
+}
+
+// 上限 0 代表不限，提高或改為不限皆視為放寬
+func raisesLimit(before, after decimal.Decimal) bool {
+	if before.IsZero() {
+		return false
+	}
+	return after.IsZero() || after.GreaterThan(before)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/payee"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+)
+
+func payeeRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "account_id", "payee_account_id", "account_number", "nickname", "transfer_limit", "daily_limit",
+		"cooling_off_until", "created_at", "updated_at"})
+}
+
+// 單元測試 新增收款人：冷靜期自新增時起算，不可新增帳號本身
+func TestAddPayee(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &PayeeService{
+		DB:                db,
+		PayeeRepository:   &repository.PayeeRepository{},
+		AccountRepository: &repository.AccountRepository{},
+		AuditRepository:   &repository.AuditRepository{},
+		Payees:            payee.DefaultConfig(),
+	}
+	req := &request.CreatePayeeRequest{Nickname: " Mom ", AccountNumber: "100000000024", DailyLimit: decimal.NewFromInt(5000)}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM payees WHERE account_id = \$1`).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WithArgs("100000000024").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("2", "Bob", "50", "standard", "active", "", "0", "100000000024"))
+	mock.ExpectQuery(`INSERT INTO payees`).
+		WithArgs("1", "2", "Mom", "0", "5000", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow("3", time.Now(), time.Now()))
+	expectAuditEntry(mock, domain.AuditPayeeAdded, "payee", "3", "")
+	mock.ExpectCommit()
+
+	p, err := svc.AddPayee(context.Background(), "1", req)
+	assert.NoError(t, err)
+	assert.Equal(t, "100000000024", p.AccountNumber)
+	assert.True(t, p.CoolingOff(time.Now()))
+	assert.False(t, p.CoolingOff(time.Now().Add(25*time.Hour)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM payees WHERE account_id = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT (.+) FROM accounts WHERE account_number = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "balance", "product", "status", "status_reason", "held_balance", "account_number"}).AddRow("1", "Alice", "100", "standard", "active", "", "0", "100000000016"))
+	mock.ExpectRollback()
+
+	_, err = svc.AddPayee(context.Background(), "1", &request.CreatePayeeRequest{Nickname: "Me", AccountNumber: "100000000016"})
+	assert.ErrorIs(t, err, ErrPayeeSelf)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 更新收款人：提高上限時重新起算冷靜期，降低上限則不影響
+func TestUpdatePayee(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &PayeeService{
+		DB:                db,
+		PayeeRepository:   &repository.PayeeRepository{},
+		AccountRepository: &repository.AccountRepository{},
+		AuditRepository:   &repository.AuditRepository{},
+		Payees:            payee.DefaultConfig(),
+	}
+	past := time.Now().Add(-time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM payees p JOIN accounts a ON (.+) WHERE p.account_id = \$1 AND p.id = \$2`).
+		WithArgs("1", "3").
+		WillReturnRows(payeeRows().AddRow("3", "1", "2", "100000000024", "Mom", "1000", "5000", past, past, past))
+	mock.ExpectQuery(`UPDATE payees`).
+		WithArgs("1", "3", "Mom", "2000", "5000", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
+	expectAuditEntry(mock, domain.AuditPayeeUpdated, "payee", "3", "")
+	mock.ExpectCommit()
+
+	p, err := svc.UpdatePayee(context.Background(), "1", "3", &request.UpdatePayeeRequest{Nickname: "Mom", TransferLimit: decimal.NewFromInt(2000), DailyLimit: decimal.NewFromInt(5000)})
+	assert.NoError(t, err)
+	assert.True(t, p.CoolingOff(time.Now()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM payees p JOIN accounts a ON (.+) WHERE p.account_id = \$1 AND p.id = \$2`).
+		WillReturnRows(payeeRows().AddRow("3", "1", "2", "100000000024", "Mom", "1000", "5000", past, past, past))
+	mock.ExpectQuery(`UPDATE payees`).
+		WithArgs("1", "3", "Mom", "500", "5000", past).
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
+	expectAuditEntry(mock, domain.AuditPayeeUpdated, "payee", "3", "")
+	mock.ExpectCommit()
+
+	p, err = svc.UpdatePayee(context.Background(), "1", "3", &request.UpdatePayeeRequest{Nickname: "Mom", TransferLimit: decimal.NewFromInt(500), DailyLimit: decimal.NewFromInt(5000)})
+	assert.NoError(t, err)
+	assert.False(t, p.CoolingOff(time.Now()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM payees p JOIN accounts a ON (.+) WHERE p.account_id = \$1 AND p.id = \$2`).
+		WillReturnRows(payeeRows())
+	mock.ExpectRollback()
+
+	err = svc.DeletePayee(context.Background(), "1", "9")
+	assert.ErrorIs(t, err, ErrPayeeNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/logging"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+)
+
+// ErrPendingTransferDecided 待處理轉帳已核准、拒絕或失效
+var ErrPendingTransferDecided = errors.New("pending transfer has already been decided")
+
+// ErrPendingTransferExpired 待處理轉帳已逾期
+var ErrPendingTransferExpired = errors.New("pending transfer has expired")
+
+// ErrSelfApproval 轉帳的發起者不可自行核准或拒絕
+var ErrSelfApproval = errors.New("pending transfer must be decided by a different user")
+
+// ErrReviewRequiresAdmin 風控審核中的轉帳只能由管理者核准或拒絕
+var ErrReviewRequiresAdmin = errors.New("fraud review must be decided by an admin")
+
+// PendingTransferService 人工處理暫緩執行的轉帳
+type PendingTransferService struct {
+	DB                        *sql.DB
+	AccountService            *AccountService
+	PendingTransferRepository *repository.PendingTransferRepository
+	FraudRepository           *repository.FraudRepository
+	AuditRepository           *repository.AuditRepository
+}
+
+// 查詢待處理轉帳
+func (s *PendingTransferService) FindPendingTransfers(ctx context.Context, filter repository.PendingTransferFilter) (_ []*domain.PendingTransfer, err error) {
+	ctx, span := tracing.Start(ctx, "PendingTransferService.FindPendingTransfers")
+	defer func() { tracing.End(span, err) }()
+
+	return s.PendingTransferRepository.Find(ctx, s.DB, filter)
+}
+
+// 查詢單筆待處理轉帳與處理紀錄
+func (s *PendingTransferService) FindPendingTransfer(ctx context.Context, id string) (_ *domain.PendingTransfer, err error) {
+	ctx, span := tracing.Start(ctx, "PendingTransferService.FindPendingTransfer")
+	defer func() { tracing.End(span, err) }()
+
+	pt, err := s.PendingTransferRepository.FindById(ctx, s.DB, id)
+	if err != nil {
+		return nil, err
+	}
+	if pt.History, err = s.PendingTransferRepository.FindActions(ctx, s.DB, id); err != nil {
+		return nil, err
+	}
+	return pt, nil
+}
+
+// 查詢風控決策
+func (s *PendingTransferService) FindFraudDecisions(ctx context.Context, filter repository.FraudDecisionFilter) (_ []*domain.FraudDecision, err error) {
+	ctx, span := tracing.Start(ctx, "PendingTransferService.FindFraudDecisions")
+	defer func() { tracing.End(span, err) }()
+
+	return s.FraudRepository.FindDecisions(ctx, s.DB, filter)
+}
+
+// 核准並執行待處理轉帳，需由發起者以外的使用者核准
+// 先釋放保留款項再執行，執行失敗 (例如餘額不足、持有人未通過 KYC) 時維持待處理狀態
+func (s *PendingTransferService) Approve(ctx context.Context, id, note string) (_ *domain.PendingTransfer, err error) {
+	ctx, span := tracing.Start(ctx, "PendingTransferService.Approve")
+	defer func() { tracing.End(span, err) }()
+
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	pt, err := s.lockPending(ctx, transaction, id)
+	if err != nil {
+		return nil, err
+	}
+	// 先鎖定雙方帳號再釋放保留款項，鎖定順序與一般轉帳相同
+	fromAcc, toAcc, err := s.AccountService.lockAccounts(ctx, transaction,
+		accountRef{pt.FromAccountID, pt.FromAccount}, accountRef{pt.ToAccountID, pt.ToAccount})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.releaseHold(ctx, transaction, pt); err != nil {
+		return nil, err
+	}
+	fromAcc.HeldBalance = fromAcc.HeldBalance.Sub(pt.HeldAmount)
+	if err := checkActive(fromAcc); err != nil {
+		return nil, err
+	}
+	if err := checkActive(toAcc); err != nil {
+		return nil, err
+	}
+	if err := s.AccountService.checkKYC(ctx, transaction, fromAcc.ID); err != nil {
+		return nil, err
+	}
+	t, err := s.AccountService.executeTransfer(ctx, transaction, fromAcc, toAcc, pt.Amount)
+	if err != nil {
+		return nil, err
+	}
+	before := *pt
+	pt.Status, pt.RefID = domain.PendingTransferApproved, t.refID
+	if err := s.decide(ctx, transaction, &before, pt, note, domain.PendingActionApproved, domain.AuditTransferApproved); err != nil {
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	s.AccountService.transferCommitted(ctx, t)
+	return pt, nil
+}
+
+// 拒絕待處理轉帳並釋放保留款項，需由發起者以外的使用者拒絕
+func (s *PendingTransferService) Reject(ctx context.Context, id, note string) (_ *domain.PendingTransfer, err error) {
+	ctx, span := tracing.Start(ctx, "PendingTransferService.Reject")
+	defer func() { tracing.End(span, err) }()
+
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	pt, err := s.lockPending(ctx, transaction, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.releaseHold(ctx, transaction, pt); err != nil {
+		return nil, err
+	}
+	before := *pt
+	pt.Status = domain.PendingTransferRejected
+	if err := s.decide(ctx, transaction, &before, pt, note, domain.PendingActionRejected, domain.AuditTransferRejected); err != nil {
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "pending transfer rejected", "pending_transfer_id", pt.ID, "decided_by", pt.DecidedBy)
+	return pt, nil
+}
+
+// 將逾期未處理的轉帳標記為失效並釋放保留款項，回傳處理的筆數
+func (s *PendingTransferService) ExpireDue(ctx context.Context) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "PendingTransferService.ExpireDue")
+	defer func() { tracing.End(span, err) }()
+
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer transaction.Rollback()
+
+	expired, err := s.PendingTransferRepository.ClaimExpired(ctx, transaction, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	for _, pt := range expired {
+		if err := s.releaseHold(ctx, transaction, pt); err != nil {
+			return 0, err
+		}
+		before := *pt
+		pt.Status = domain.PendingTransferExpired
+		if err := s.decide(ctx, transaction, &before, pt, "", domain.PendingActionExpired, domain.AuditTransferExpired); err != nil {
+			return 0, err
+		}
+	}
+	if err := transaction.Commit(); err != nil {
+		return 0, err
+	}
+	for _, pt := range expired {
+		slog.InfoContext(ctx, "pending transfer expired", "pending_transfer_id", pt.ID, "held_amount", pt.HeldAmount.String())
+	}
+	return len(expired), nil
+}
+
+// 鎖定仍在等待處理的轉帳，避免重複核准，並確認處理者不是發起者 (maker-checker)
+// 大額覆核可由發起者以外的任何使用者處理，風控審核涉及規則與黑名單判斷，只限管理者
+func (s *PendingTransferService) lockPending(ctx context.Context, db repository.DBTX, id string) (*domain.PendingTransfer, error) {
+	pt, err := s.PendingTransferRepository.FindByIdForUpdate(ctx, db, id)
+	if err != nil {
+		return nil, err
+	}
+	if pt.Status != domain.PendingTransferPending {
+		return nil, fmt.Errorf("%w: %s is %s", ErrPendingTransferDecided, pt.ID, pt.Status)
+	}
+	if pt.ExpiresAt != nil && !time.Now().Before(*pt.ExpiresAt) {
+		return nil, fmt.Errorf("%w: %s expired at %s", ErrPendingTransferExpired, pt.ID, pt.ExpiresAt.Format(time.RFC3339))
+	}
+	if auth.Actor(ctx) == pt.RequestedBy {
+		return nil, fmt.Errorf("%w: %s was requested by %s", ErrSelfApproval, pt.ID, pt.RequestedBy)
+	}
+	if p, ok := auth.FromContext(ctx); ok && pt.Reason == domain.PendingReasonFraudReview && !p.IsAdmin() {
+		return nil, fmt.Errorf("%w: %s", ErrReviewRequiresAdmin, pt.ID)
+	}
+	return pt, nil
+}
+
+// 釋放轉出帳號保留的款項
+func (s *PendingTransferService) releaseHold(ctx context.Context, db repository.DBTX, pt *domain.PendingTransfer) error {
+	if !pt.HeldAmount.IsPositive() {
+		return nil
+	}
+	return s.AccountService.AccountRepository.AddHeldBalance(ctx, db, pt.FromAccountID, pt.HeldAmount.Neg())
+}
+
+// 記錄處理者與結果，並寫入處理紀錄與稽核紀錄
+func (s *PendingTransferService) decide(ctx context.Context, db repository.DBTX, before, pt *domain.PendingTransfer, note, action, auditAction string) error {
+	pt.DecidedBy, pt.DecisionNote = auth.Actor(ctx), note
+	if err := s.PendingTransferRepository.Decide(ctx, db, pt); err != nil {
+		return err
+	}
+	if err := s.PendingTransferRepository.InsertAction(ctx, db, &domain.PendingTransferAction{
+		PendingTransferID: pt.ID,
+		Action:            action,
+		Actor:             pt.DecidedBy,
+		Note:              note,
+		RequestID:         logging.RequestID(ctx),
+	}); err != nil {
+		return err
+	}
+	return recordAudit(ctx, s.AuditRepository, db, auditAction, "pending_transfer", pt.ID, before, pt)
+}
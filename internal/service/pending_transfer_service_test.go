@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+)
+
+func pendingTransferRows(status, held string) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "from_account_id", "to_account_id", "from_account", "to_account", "amount", "reason", "status", "requested_by",
+		"request_id", "decided_by", "decision_note", "ref_id", "held_amount", "expires_at", "created_at", "decided_at"}).
+		AddRow("7", "from1", "to1", "100000000016", "100000000024", "600", domain.PendingReasonFraudReview, status, "bob", "", "", "", "", held, nil, time.Now(), nil)
+}
+
+// 單元測試 核准待處理轉帳：執行轉帳並記錄 ref_id 與處理者
+func TestPendingTransfer_Approve(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	accounts := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}}
+	svc := &PendingTransferService{
+		DB:                        db,
+		AccountService:            accounts,
+		PendingTransferRepository: &repository.PendingTransferRepository{},
+		AuditRepository:           &repository.AuditRepository{},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM pending_transfers WHERE id = \$1 FOR UPDATE`).
+		WithArgs("7").
+		WillReturnRows(pendingTransferRows(domain.PendingTransferPending, "600"))
+	expectLockAccount(mock, "from1", "Alice", "1000", "standard", "active", "", "600", "from1")
+	expectLockAccount(mock, "to1", "Bob", "50", "standard", "active", "", "0", "to1")
+	mock.ExpectExec(`UPDATE accounts SET held_balance = held_balance \+ \$1 WHERE id = \$2`).
+		WithArgs("-600", "from1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE accounts SET balance = .* WHERE id = .*`).
+		WithArgs("400", "from1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE accounts SET balance = .* WHERE id = .*`).
+		WithArgs("650", "to1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO transactions`).
+		WithArgs("from1", 1, domain.TxKindTransferOut, "600", sqlmock.AnyArg(), "Transfer to Bob", "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`INSERT INTO transactions`).
+		WithArgs("to1", 2, domain.TxKindTransferIn, "600", sqlmock.AnyArg(), "Transfer from Alice", "", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	expectAudit(mock, "from1", "")
+	expectAudit(mock, "to1", "")
+	expectEvent(mock, domain.EventTransferCompleted, "from1", "")
+	mock.ExpectQuery(`UPDATE pending_transfers`).
+		WithArgs("7", domain.PendingTransferApproved, "system", "verified by phone", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"decided_at"}).AddRow(time.Now()))
+	expectPendingAction(mock, "7", domain.PendingActionApproved, "system", "verified by phone")
+	expectAuditEntry(mock, domain.AuditTransferApproved, "pending_transfer", "7", "")
+	mock.ExpectCommit()
+
+	pt, err := svc.Approve(context.Background(), "7", "verified by phone")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PendingTransferApproved, pt.Status)
+	assert.NotEmpty(t, pt.RefID)
+	assert.NotNil(t, pt.DecidedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 已處理的轉帳不可再次核准或拒絕
+func TestPendingTransfer_AlreadyDecided(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	accounts := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}}
+	svc := &PendingTransferService{
+		DB:                        db,
+		AccountService:            accounts,
+		PendingTransferRepository: &repository.PendingTransferRepository{},
+		AuditRepository:           &repository.AuditRepository{},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM pending_transfers WHERE id = \$1 FOR UPDATE`).
+		WithArgs("7").
+		WillReturnRows(pendingTransferRows(domain.PendingTransferRejected, "0"))
+	mock.ExpectRollback()
+
+	_, err := svc.Approve(context.Background(), "7", "")
+	assert.ErrorIs(t, err, ErrPendingTransferDecided)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 發起者不可自行核准或拒絕 (maker-checker)
+func TestPendingTransfer_SelfApproval(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	accounts := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}}
+	svc := &PendingTransferService{
+		DB:                        db,
+		AccountService:            accounts,
+		PendingTransferRepository: &repository.PendingTransferRepository{},
+		AuditRepository:           &repository.AuditRepository{},
+	}
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{ID: "bob", Role: auth.RoleAdmin})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM pending_transfers WHERE id = \$1 FOR UPDATE`).
+		WithArgs("7").
+		WillReturnRows(pendingTransferRows(domain.PendingTransferPending, "600"))
+	mock.ExpectRollback()
+
+	_, err := svc.Reject(ctx, "7", "changed my mind")
+	assert.ErrorIs(t, err, ErrSelfApproval)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 風控審核中的轉帳只能由管理者處理
+func TestPendingTransfer_ReviewRequiresAdmin(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	accounts := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}}
+	svc := &PendingTransferService{
+		DB:                        db,
+		AccountService:            accounts,
+		PendingTransferRepository: &repository.PendingTransferRepository{},
+		AuditRepository:           &repository.AuditRepository{},
+	}
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{ID: "carol", Role: auth.RoleUser})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM pending_transfers WHERE id = \$1 FOR UPDATE`).
+		WithArgs("7").
+		WillReturnRows(pendingTransferRows(domain.PendingTransferPending, "600"))
+	mock.ExpectRollback()
+
+	_, err := svc.Approve(ctx, "7", "")
+	assert.ErrorIs(t, err, ErrReviewRequiresAdmin)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 拒絕待處理轉帳：釋放保留款項並寫入處理紀錄
+func TestPendingTransfer_Reject(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	accounts := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}}
+	svc := &PendingTransferService{
+		DB:                        db,
+		AccountService:            accounts,
+		PendingTransferRepository: &repository.PendingTransferRepository{},
+		AuditRepository:           &repository.AuditRepository{},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM pending_transfers WHERE id = \$1 FOR UPDATE`).
+		WithArgs("7").
+		WillReturnRows(pendingTransferRows(domain.PendingTransferPending, "600"))
+	mock.ExpectExec(`UPDATE accounts SET held_balance = held_balance \+ \$1 WHERE id = \$2`).
+		WithArgs("-600", "from1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`UPDATE pending_transfers`).
+		WithArgs("7", domain.PendingTransferRejected, "system", "unknown payee", "").
+		WillReturnRows(sqlmock.NewRows([]string{"decided_at"}).AddRow(time.Now()))
+	expectPendingAction(mock, "7", domain.PendingActionRejected, "system", "unknown payee")
+	expectAuditEntry(mock, domain.AuditTransferRejected, "pending_transfer", "7", "")
+	mock.ExpectCommit()
+
+	pt, err := svc.Reject(context.Background(), "7", "unknown payee")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PendingTransferRejected, pt.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 逾期的待處理轉帳標記為失效並釋放保留款項，逾期後不可再核准
+func TestPendingTransfer_Expire(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	accounts := &AccountService{DB: db, AccountRepository: &repository.AccountRepository{}, TransactionRepository: &repository.TransactionRepository{}, AuditRepository: &repository.AuditRepository{}, OutboxRepository: &repository.OutboxRepository{}}
+	svc := &PendingTransferService{
+		DB:                        db,
+		AccountService:            accounts,
+		PendingTransferRepository: &repository.PendingTransferRepository{},
+		AuditRepository:           &repository.AuditRepository{},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM pending_transfers WHERE status = \$1 AND expires_at <= \$2 ORDER BY id FOR UPDATE SKIP LOCKED`).
+		WithArgs(domain.PendingTransferPending, sqlmock.AnyArg()).
+		WillReturnRows(pendingTransferRows(domain.PendingTransferPending, "600"))
+	mock.ExpectExec(`UPDATE accounts SET held_balance = held_balance \+ \$1 WHERE id = \$2`).
+		WithArgs("-600", "from1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`UPDATE pending_transfers`).
+		WithArgs("7", domain.PendingTransferExpired, "system", "", "").
+		WillReturnRows(sqlmock.NewRows([]string{"decided_at"}).AddRow(time.Now()))
+	expectPendingAction(mock, "7", domain.PendingActionExpired, "system", "")
+	expectAuditEntry(mock, domain.AuditTransferExpired, "pending_transfer", "7", "")
+	mock.ExpectCommit()
+
+	n, err := svc.ExpireDue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	expired := time.Now().Add(-time.Minute)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM pending_transfers WHERE id = \$1 FOR UPDATE`).
+		WithArgs("7").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "from_account_id", "to_account_id", "from_account", "to_account", "amount", "reason", "status", "requested_by",
+			"request_id", "decided_by", "decision_note", "ref_id", "held_amount", "expires_at", "created_at", "decided_at"}).
+			AddRow("7", "from1", "to1", "100000000016", "100000000024", "600", domain.PendingReasonApproval, domain.PendingTransferPending, "bob", "", "", "", "", "600", expired, time.Now(), nil))
+	mock.ExpectRollback()
+
+	_, err = svc.Approve(context.Background(), "7", "")
+	assert.ErrorIs(t, err, ErrPendingTransferExpired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
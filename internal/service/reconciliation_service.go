@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+)
+
+type ReconciliationService struct {
+	DB                       *sql.DB
+	ReconciliationRepository *repository.ReconciliationRepository
+}
+
+// 核對所有帳號的餘額是否等於交易紀錄加總
+// 使用 REPEATABLE READ 快照，避免核對期間的交易造成誤判
+func (s *ReconciliationService) Reconcile(ctx context.Context) (_ *domain.Reconciliation, err error) {
+	ctx, span := tracing.Start(ctx, "ReconciliationService.Reconcile")
+	defer func() { tracing.End(span, err) }()
+
+	transaction, err := s.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	checked, err := s.ReconciliationRepository.CountAccounts(ctx, transaction)
+	if err != nil {
+		return nil, err
+	}
+	mismatches, err := s.ReconciliationRepository.FindMismatches(ctx, transaction)
+	if err != nil {
+		return nil, err
+	}
+	if mismatches == nil {
+		mismatches = []domain.ReconciliationMismatch{}
+	}
+	return &domain.Reconciliation{
+		Balanced:        len(mismatches) == 0,
+		AccountsChecked: checked,
+		Mismatches:      mismatches,
+	}, transaction.Commit()
+}
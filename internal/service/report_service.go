@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/businessday"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/gl"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+)
+
+// ReportService 總帳報表：試算表與每日總帳彙總
+type ReportService struct {
+	DB                    *sql.DB
+	ReportRepository      *repository.ReportRepository
+	BusinessDayRepository *repository.BusinessDayRepository
+	Chart                 gl.Config          // 會計科目表
+	BusinessDay           businessday.Config // 未指定日期時使用目前營業日
+	FeeRevenueAccount     string             // 手續費收入帳號號碼，交易記入手續費收入科目
+}
+
+// 截至營業日 (含) 的試算表，未指定日期時為目前營業日
+func (s *ReportService) TrialBalance(ctx context.Context, date string) (_ *domain.TrialBalance, err error) {
+	ctx, span := tracing.Start(ctx, "ReportService.TrialBalance")
+	defer func() { tracing.End(span, err) }()
+
+	date, totals, final, err := s.glTotals(ctx, date, true)
+	if err != nil {
+		return nil, err
+	}
+	tb := &domain.TrialBalance{BusinessDate: date, Final: final, Lines: []domain.TrialBalanceLine{}}
+	for _, e := range s.Chart.Chart() {
+		t := totals[e.Key]
+		line := domain.TrialBalanceLine{Code: e.Code, Name: e.Name, Type: e.Type}
+		// 依淨額方向列於借方或貸方
+		if net := t.Debits.Sub(t.Credits); net.IsPositive() {
+			line.Debit = net
+		} else {
+			line.Credit = net.Neg()
+		}
+		tb.Lines = append(tb.Lines, line)
+		tb.TotalDebits = tb.TotalDebits.Add(line.Debit)
+		tb.TotalCredits = tb.TotalCredits.Add(line.Credit)
+	}
+	tb.Balanced = tb.TotalDebits.Equal(tb.TotalCredits)
+	return tb, nil
+}
+
+// 營業日當日各科目的借貸方發生額，未指定日期時為目前營業日
+func (s *ReportService) GLSummary(ctx context.Context, date string) (_ *domain.GLSummary, err error) {
+	ctx, span := tracing.Start(ctx, "ReportService.GLSummary")
+	defer func() { tracing.End(span, err) }()
+
+	date, totals, final, err := s.glTotals(ctx, date, false)
+	if err != nil {
+		return nil, err
+	}
+	sum := &domain.GLSummary{BusinessDate: date, Final: final, Lines: []domain.GLSummaryLine{}}
+	for _, e := range s.Chart.Chart() {
+		t := totals[e.Key]
+		sum.Lines = append(sum.Lines, domain.GLSummaryLine{
+			Code:    e.Code,
+			Name:    e.Name,
+			Type:    e.Type,
+			Debits:  t.Debits,
+			Credits: t.Credits,
+			Entries: t.Entries,
+		})
+		sum.TotalDebits = sum.TotalDebits.Add(t.Debits)
+		sum.TotalCredits = sum.TotalCredits.Add(t.Credits)
+	}
+	sum.Balanced = sum.TotalDebits.Equal(sum.TotalCredits)
+	return sum, nil
+}
+
+// 以同一個 REPEATABLE READ 快照查詢結帳進度與科目合計
+// cumulative 為 true 時加總營業日 (含) 之前的所有交易，否則只加總當日
+func (s *ReportService) glTotals(ctx context.Context, date string, cumulative bool) (string, map[string]repository.GLTotal, bool, error) {
+	if date == "" {
+		date = s.BusinessDay.Today(time.Now())
+	}
+	if _, err := businessday.ParseDate(date); err != nil {
+		return "", nil, false, err
+	}
+	transaction, err := s.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return "", nil, false, err
+	}
+	defer transaction.Rollback()
+
+	closed, err := s.BusinessDayRepository.ClosedThrough(ctx, transaction)
+	if err != nil {
+		return "", nil, false, err
+	}
+	from := date
+	if cumulative {
+		from = ""
+	}
+	rows, err := s.ReportRepository.GLTotals(ctx, transaction, from, date, s.FeeRevenueAccount, s.Chart.InterestExpenseAccount)
+	if err != nil {
+		return "", nil, false, err
+	}
+	totals := make(map[string]repository.GLTotal, len(rows))
+	for _, t := range rows {
+		totals[t.Key] = t
+	}
+	return date, totals, closed != "" && date <= closed, transaction.Commit()
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/gl"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+)
+
+func glTotalRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"gl", "debits", "credits", "entries"})
+}
+
+// 單元測試 試算表：存款 1000、提款 200 (手續費 2)，各科目依淨額列於借方或貸方
+func TestTrialBalance(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &ReportService{
+		DB:                    db,
+		ReportRepository:      &repository.ReportRepository{},
+		BusinessDayRepository: &repository.BusinessDayRepository{},
+		Chart:                 gl.DefaultConfig(),
+		FeeRevenueAccount:     "100000000099",
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM business_day_closes`).
+		WillReturnRows(sqlmock.NewRows([]string{"date"}).AddRow("2025-01-31"))
+	mock.ExpectQuery(`WITH t AS`).
+		WithArgs("", "2025-01-31", "100000000099", "", gl.KeyCash, gl.KeyFeeRevenue, gl.KeyInterestExpense, gl.KeyCustomerDeposits,
+			domain.TxKindTransferOut, domain.TxKindTransferIn, domain.TxKindFee).
+		WillReturnRows(glTotalRows().
+			AddRow(gl.KeyCash, "1000", "200", 2).
+			AddRow(gl.KeyCustomerDeposits, "202", "1000", 3).
+			AddRow(gl.KeyFeeRevenue, "0", "2", 1))
+	mock.ExpectCommit()
+
+	tb, err := svc.TrialBalance(context.Background(), "2025-01-31")
+	assert.NoError(t, err)
+	assert.True(t, tb.Final)
+	assert.True(t, tb.Balanced)
+	assert.Len(t, tb.Lines, 4)
+	assert.Equal(t, "1000", tb.Lines[0].Code)
+	assert.True(t, decimal.NewFromInt(800).Equal(tb.Lines[0].Debit))
+	assert.True(t, decimal.NewFromInt(798).Equal(tb.Lines[1].Credit))
+	assert.True(t, decimal.NewFromInt(2).Equal(tb.Lines[2].Credit))
+	assert.True(t, tb.Lines[3].Debit.IsZero() && tb.Lines[3].Credit.IsZero())
+	assert.True(t, decimal.NewFromInt(800).Equal(tb.TotalDebits))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試 每日總帳彙總：只加總當日交易，缺少對方分錄時借貸不平衡
+func TestGLSummary(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+	svc := &ReportService{
+		DB:                    db,
+		ReportRepository:      &repository.ReportRepository{},
+		BusinessDayRepository: &repository.BusinessDayRepository{},
+		Chart:                 gl.DefaultConfig(),
+		FeeRevenueAccount:     "100000000099",
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM business_day_closes`).
+		WillReturnRows(sqlmock.NewRows([]string{"date"}).AddRow(""))
+	mock.ExpectQuery(`WITH t AS`).
+		WithArgs("2025-02-01", "2025-02-01", "100000000099", "", gl.KeyCash, gl.KeyFeeRevenue, gl.KeyInterestExpense, gl.KeyCustomerDeposits,
+			domain.TxKindTransferOut, domain.TxKindTransferIn, domain.TxKindFee).
+		WillReturnRows(glTotalRows().
+			AddRow(gl.KeyCustomerDeposits, "32", "30", 3).
+			AddRow(gl.KeyFeeRevenue, "0", "1", 1))
+	mock.ExpectCommit()
+
+	sum, err := svc.GLSummary(context.Background(), "2025-02-01")
+	assert.NoError(t, err)
+	assert.False(t, sum.Final)
+	assert.False(t, sum.Balanced)
+	assert.Equal(t, 3, sum.Lines[1].Entries)
+	assert.True(t, decimal.NewFromInt(32).Equal(sum.TotalDebits))
+	assert.True(t, decimal.NewFromInt(31).Equal(sum.TotalCredits))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/request"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+	"github.com/yoyo0827/simple-bank-system/internal/webhook"
+)
+
+type WebhookService struct {
+	DB                *sql.DB
+	WebhookRepository *repository.WebhookRepository
+	AuditRepository   *repository.AuditRepository
+}
+
+// 建立訂閱，回傳的 secret 只會在此時出現
+func (s *WebhookService) CreateSubscription(ctx context.Context, req *request.WebhookSubscriptionRequest) (_ *domain.WebhookSubscription, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.CreateSubscription")
+	defer func() { tracing.End(span, err) }()
+
+	sub := &domain.WebhookSubscription{}
+	if err := applySubscription(sub, req); err != nil {
+		return nil, err
+	}
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	if err := s.WebhookRepository.CreateSubscription(ctx, transaction, sub); err != nil {
+		return nil, err
+	}
+	if err := s.audit(ctx, transaction, domain.AuditWebhookCreated, sub.ID, nil, sub); err != nil {
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// 查詢訂閱
+func (s *WebhookService) FindSubscription(ctx context.Context, id string) (_ *domain.WebhookSubscription, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.FindSubscription")
+	defer func() { tracing.End(span, err) }()
+
+	sub, err := s.WebhookRepository.FindSubscription(ctx, s.DB, id)
+	if err != nil {
+		return nil, err
+	}
+	sub.Secret = ""
+	return sub, nil
+}
+
+// 查詢所有訂閱
+func (s *WebhookService) ListSubscriptions(ctx context.Context) (_ []*domain.WebhookSubscription, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.ListSubscriptions")
+	defer func() { tracing.End(span, err) }()
+
+	subs, err := s.WebhookRepository.ListSubscriptions(ctx, s.DB)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		sub.Secret = ""
+	}
+	return subs, nil
+}
+
+// 更新訂閱，有指定 secret 時更換並回傳新的 secret
+func (s *WebhookService) UpdateSubscription(ctx context.Context, id string, req *request.WebhookSubscriptionRequest) (_ *domain.WebhookSubscription, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.UpdateSubscription")
+	defer func() { tracing.End(span, err) }()
+
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	before, err := s.WebhookRepository.FindSubscription(ctx, transaction, id)
+	if err != nil {
+		return nil, err
+	}
+	after := *before
+	if err := applySubscription(&after, req); err != nil {
+		return nil, err
+	}
+	if err := s.WebhookRepository.UpdateSubscription(ctx, transaction, &after); err != nil {
+		return nil, err
+	}
+	if err := s.audit(ctx, transaction, domain.AuditWebhookUpdated, id, before, &after); err != nil {
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	if req.Secret == "" {
+		after.Secret = ""
+	}
+	return &after, nil
+}
+
+// 刪除訂閱
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeleteSubscription")
+	defer func() { tracing.End(span, err) }()
+
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer transaction.Rollback()
+
+	before, err := s.WebhookRepository.FindSubscription(ctx, transaction, id)
+	if err != nil {
+		return err
+	}
+	if err := s.WebhookRepository.DeleteSubscription(ctx, transaction, id); err != nil {
+		return err
+	}
+	if err := s.audit(ctx, transaction, domain.AuditWebhookDeleted, id, before, nil); err != nil {
+		return err
+	}
+	return transaction.Commit()
+}
+
+// 查詢訂閱的投遞紀錄
+func (s *WebhookService) FindDeliveries(ctx context.Context, filter repository.DeliveryFilter) (_ []*domain.WebhookDelivery, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.FindDeliveries")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := s.WebhookRepository.FindSubscription(ctx, s.DB, filter.SubscriptionID); err != nil {
+		return nil, err
+	}
+	return s.WebhookRepository.FindDeliveries(ctx, s.DB, filter)
+}
+
+// 手動重送 (包含已進入 dead letter 的紀錄)
+func (s *WebhookService) Redeliver(ctx context.Context, id string) (_ *domain.WebhookDelivery, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Redeliver")
+	defer func() { tracing.End(span, err) }()
+
+	transaction, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer transaction.Rollback()
+
+	before, err := s.WebhookRepository.FindDeliveryForUpdate(ctx, transaction, id)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.WebhookRepository.Redeliver(ctx, transaction, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.AuditRepository, transaction, domain.AuditWebhookRedelivered, "webhook_delivery", id, before, after); err != nil {
+		return nil, err
+	}
+	if err := transaction.Commit(); err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// 寫入訂閱相關的稽核紀錄 (不含 secret)
+func (s *WebhookService) audit(ctx context.Context, db repository.DBTX, action, id string, before, after *domain.WebhookSubscription) error {
+	var b, a any
+	if before != nil {
+		redacted := *before
+		redacted.Secret = ""
+		b = redacted
+	}
+	if after != nil {
+		redacted := *after
+		redacted.Secret = ""
+		a = redacted
+	}
+	return recordAudit(ctx, s.AuditRepository, db, action, "webhook", id, b, a)
+}
+
+// 將請求內容套用到訂閱，secret 未指定且原本沒有時自動產生
+func applySubscription(sub *domain.WebhookSubscription, req *request.WebhookSubscriptionRequest) error {
+	sub.URL = req.URL
+	sub.EventTypes = req.EventTypes
+	if sub.EventTypes == nil {
+		sub.EventTypes = []string{}
+	}
+	sub.AccountNumber = req.AccountNumber
+	sub.Active = req.Active == nil || *req.Active
+	switch {
+	case req.Secret != "":
+		sub.Secret = req.Secret
+	case sub.Secret == "":
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			return err
+		}
+		sub.Secret = secret
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package snapshot
+
+import (
+	"errors"
+	"time"
+)
+
+// Config 定期餘額快照設定，快照讓歷史餘額查詢只需加總快照之後的交易
+type Config struct {
+	Interval  time.Duration `yaml:"interval" toml:"interval" env:"BALANCE_SNAPSHOT_INTERVAL"` // 0 代表不建立快照
+	Settle    time.Duration `yaml:"settle" toml:"settle" env:"BALANCE_SNAPSHOT_SETTLE"`       // 快照時間點落後現在的時間，避免進行中的交易在快照後才寫入
+	BatchSize int           `yaml:"batch_size" toml:"batch_size" env:"BALANCE_SNAPSHOT_BATCH_SIZE"`
+}
+
+// DefaultConfig 預設每天建立一次快照
+func DefaultConfig() Config {
+	return Config{
+		Interval:  24 * time.Hour,
+		Settle:    5 * time.Minute,
+		BatchSize: 500,
+	}
+}
+
+// Validate 檢查期間與批次大小
+func (c Config) Validate() error {
+	var errs []error
+	if c.Interval < 0 {
+		errs = append(errs, errors.New("balance_snapshots.interval cannot be negative"))
+	}
+	if c.Settle < 0 {
+		errs = append(errs, errors.New("balance_snapshots.settle cannot be negative"))
+	}
+	if c.Interval > 0 && c.BatchSize <= 0 {
+		errs = append(errs, errors.New("balance_snapshots.batch_size must be positive when interval is set"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,18 @@
+package snapshot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig().Validate())
+	assert.NoError(t, Config{}.Validate())
+
+	err := Config{Interval: time.Hour, Settle: -time.Second}.Validate()
+	assert.ErrorContains(t, err, "balance_snapshots.settle")
+	assert.ErrorContains(t, err, "balance_snapshots.batch_size")
+	assert.ErrorContains(t, Config{Interval: -time.Hour}.Validate(), "balance_snapshots.interval")
+}
@@ -0,0 +1,31 @@
+package snapshot
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/worker"
+)
+
+// Snapshotter 為 until 之前有新交易的帳號建立餘額快照，回傳建立的筆數
+type Snapshotter interface {
+	SnapshotBalances(ctx context.Context, until time.Time, batchSize int) (int, error)
+}
+
+// Worker 定期建立餘額快照
+type Worker struct {
+	Service Snapshotter
+	Config  Config
+}
+
+// Run 持續處理直到 ctx 結束
+func (w *Worker) Run(ctx context.Context) {
+	worker.RunEvery(ctx, "balance snapshot worker", w.Config.Interval, func(ctx context.Context) error {
+		n, err := w.Service.SnapshotBalances(ctx, time.Now().Add(-w.Config.Settle), w.Config.BatchSize)
+		if n > 0 {
+			slog.Info("balance snapshots created", "count", n)
+		}
+		return err
+	})
+}
@@ -0,0 +1,109 @@
+package stream
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+// ErrClosed broker 已關閉 (服務關閉中)
+var ErrClosed = errors.New("event stream is shutting down")
+
+// Broker 行程內的帳號事件 pub/sub
+// 訂閱者處理太慢 (buffer 已滿) 時會被中斷，由客戶端以 Last-Event-ID 重新連線補齊
+type Broker struct {
+	mu     sync.Mutex
+	buffer int
+	subs   map[string]map[*Subscription]struct{}
+	closed bool
+}
+
+// Subscription 單一帳號的事件訂閱，C 被關閉代表訂閱已結束
+type Subscription struct {
+	C         <-chan *domain.Event
+	ch        chan *domain.Event
+	accountID string
+	broker    *Broker
+}
+
+func NewBroker(buffer int) *Broker {
+	return &Broker{buffer: buffer, subs: make(map[string]map[*Subscription]struct{})}
+}
+
+// Subscribe 訂閱帳號事件
+func (b *Broker) Subscribe(accountID string) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+	ch := make(chan *domain.Event, b.buffer)
+	sub := &Subscription{C: ch, ch: ch, accountID: accountID, broker: b}
+	if b.subs[accountID] == nil {
+		b.subs[accountID] = make(map[*Subscription]struct{})
+	}
+	b.subs[accountID][sub] = struct{}{}
+	return sub, nil
+}
+
+// Close 取消訂閱，可重複呼叫
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s)
+}
+
+// Publish 將事件送給所有相關帳號的訂閱者，不會阻塞
+func (b *Broker) Publish(ev *domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, id := range ev.AccountNumbers() {
+		for sub := range b.subs[id] {
+			select {
+			case sub.ch <- ev:
+			default:
+				b.remove(sub)
+			}
+		}
+	}
+}
+
+// Reset 中斷所有訂閱，用於可能遺漏事件時 (例如 LISTEN 連線重建) 讓客戶端重新補齊
+func (b *Broker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removeAll()
+}
+
+// Close 中斷所有訂閱並拒絕新的訂閱，讓 graceful shutdown 不必等待長連線
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.removeAll()
+}
+
+func (b *Broker) removeAll() {
+	for _, subs := range b.subs {
+		for sub := range subs {
+			b.remove(sub)
+		}
+	}
+}
+
+// 呼叫端需持有 b.mu
+func (b *Broker) remove(sub *Subscription) {
+	subs, ok := b.subs[sub.accountID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.subs, sub.accountID)
+	}
+	close(sub.ch)
+}
@@ -0,0 +1,48 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+// 單元測試只推送給相關帳號的訂閱者
+func TestBroker_Publish(t *testing.T) {
+	b := NewBroker(4)
+	a, _ := b.Subscribe("1")
+	other, _ := b.Subscribe("2")
+
+	b.Publish(&domain.Event{ID: 1, Type: domain.EventDeposited, AggregateID: "1"})
+	assert.Equal(t, int64(1), (<-a.C).ID)
+	assert.Len(t, other.C, 0)
+}
+
+// 單元測試處理太慢的訂閱者會被中斷，其他訂閱者不受影響
+func TestBroker_SlowSubscriber(t *testing.T) {
+	b := NewBroker(1)
+	slow, _ := b.Subscribe("1")
+	fast, _ := b.Subscribe("1")
+
+	b.Publish(&domain.Event{ID: 1, AggregateID: "1"})
+	<-fast.C
+	b.Publish(&domain.Event{ID: 2, AggregateID: "1"})
+
+	assert.Equal(t, int64(1), (<-slow.C).ID)
+	_, ok := <-slow.C
+	assert.False(t, ok)
+	assert.Equal(t, int64(2), (<-fast.C).ID)
+	slow.Close() // 已中斷的訂閱可再次 Close
+}
+
+// 單元測試關閉後中斷所有訂閱並拒絕新訂閱
+func TestBroker_Close(t *testing.T) {
+	b := NewBroker(1)
+	sub, _ := b.Subscribe("1")
+	b.Close()
+
+	_, ok := <-sub.C
+	assert.False(t, ok)
+	_, err := b.Subscribe("1")
+	assert.ErrorIs(t, err, ErrClosed)
+}
@@ -0,0 +1,41 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 事件來源
+const (
+	BackendPostgres = "postgres" // LISTEN/NOTIFY，適用多個服務實例
+	BackendMemory   = "memory"   // 行程內直接發佈，適用單一實例
+)
+
+// Config 帳號事件串流 (SSE) 設定
+type Config struct {
+	Backend    string        `yaml:"backend" toml:"backend" env:"STREAM_BACKEND"`
+	Heartbeat  time.Duration `yaml:"heartbeat" toml:"heartbeat" env:"STREAM_HEARTBEAT"`
+	BufferSize int           `yaml:"buffer_size" toml:"buffer_size" env:"STREAM_BUFFER_SIZE"`
+}
+
+// DefaultConfig 串流預設設定
+func DefaultConfig() Config {
+	return Config{
+		Backend:    BackendPostgres,
+		Heartbeat:  15 * time.Second,
+		BufferSize: 64,
+	}
+}
+
+// Validate 檢查事件來源與 heartbeat 設定
+func (c Config) Validate() error {
+	var errs []error
+	if c.Backend != BackendPostgres && c.Backend != BackendMemory {
+		errs = append(errs, fmt.Errorf("stream.backend must be %q or %q", BackendPostgres, BackendMemory))
+	}
+	if c.Heartbeat <= 0 || c.BufferSize <= 0 {
+		errs = append(errs, errors.New("stream heartbeat and buffer_size must be positive"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,60 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+)
+
+// Channel outbox_events 寫入時 NOTIFY 的 channel (見 db/migrations/0001_init.up.sql 的 trigger)
+const Channel = "account_events"
+
+// Listener 以 PostgreSQL LISTEN 接收已提交的事件並轉發給 Broker
+// 多個服務實例各自 LISTEN，任一實例寫入的事件都能推送給所有實例的訂閱者
+type Listener struct {
+	DSN    string
+	Broker *Broker
+}
+
+// Run 持續 LISTEN 直到 ctx 結束，連線中斷時由 pq.Listener 自動重連
+func (l *Listener) Run(ctx context.Context) error {
+	pql := pq.NewListener(l.DSN, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Warn("event stream listener connection error", "error", err)
+		}
+	})
+	defer pql.Close()
+	if err := pql.Listen(Channel); err != nil {
+		return err
+	}
+	slog.Info("event stream listener started", "channel", Channel)
+
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("event stream listener stopped")
+			return nil
+		case <-ping.C:
+			go pql.Ping()
+		case n := <-pql.Notify:
+			if n == nil {
+				// 重新連線期間的通知已遺失，讓訂閱者以 Last-Event-ID 重新補齊
+				slog.Warn("event stream listener reconnected, resetting subscriptions")
+				l.Broker.Reset()
+				continue
+			}
+			var ev domain.Event
+			if err := json.Unmarshal([]byte(n.Extra), &ev); err != nil {
+				slog.Error("invalid event notification", "error", err)
+				continue
+			}
+			l.Broker.Publish(&ev)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/yoyo0827/simple-bank-system/internal/route"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware 為每個請求建立 server span，並沿用上游的 traceparent header
+// 路由比對完成後，以 ServeMux 的 pattern (例如 "GET /accounts/{id}") 作為 span 名稱
+// pattern 由路由的 handler 經 route.Record 寫回，不受中間 middleware 複製請求影響
+func Middleware(next http.Handler) http.Handler {
+	named := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = route.Track(r)
+		next.ServeHTTP(w, r)
+		pattern := route.Pattern(r)
+		if pattern == "" {
+			return
+		}
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(pattern)
+		httpRoute := pattern
+		if _, path, ok := strings.Cut(pattern, " "); ok {
+			httpRoute = path
+		}
+		span.SetAttributes(semconv.HTTPRoute(httpRoute))
+	})
+	return otelhttp.NewHandler(named, "http.request")
+}
@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+)
+
+// ErrAddressNotAllowed 訂閱 URL 解析到內部網路、loopback 或 link-local (例如雲端 metadata 169.254.169.254) 位址
+var ErrAddressNotAllowed = errors.New("webhook address is not allowed")
+
+// NewClient 投遞用的 HTTP client
+// 未設定 AllowPrivateNetworks 時，每次連線 (含 redirect) 都檢查解析後的位址，DNS 之後改指向內部位址也會被拒絕
+// 不使用環境變數的 proxy，避免經由 proxy 繞過檢查
+func NewClient(c Config) *http.Client {
+	dialer := &net.Dialer{Timeout: c.Timeout}
+	if !c.AllowPrivateNetworks {
+		dialer.Control = checkAddress
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: c.Timeout, Transport: transport}
+}
+
+// 只允許連線到公開的 unicast 位址
+func checkAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || cgnat.Contains(ip) {
+		return fmt.Errorf("%w: %s", ErrAddressNotAllowed, ip)
+	}
+	return nil
+}
+
+// 電信業者共用 NAT 位址 (RFC 6598)，雲端環境常用於內部服務
+var cgnat = netip.MustParsePrefix("100.64.0.0/10")
@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"errors"
+	"time"
+)
+
+// Config webhook 投遞設定
+type Config struct {
+	Enabled         bool          `yaml:"enabled" toml:"enabled" env:"WEBHOOKS_ENABLED"`
+	Timeout         time.Duration `yaml:"timeout" toml:"timeout" env:"WEBHOOKS_TIMEOUT"`
+	PollInterval    time.Duration `yaml:"poll_interval" toml:"poll_interval" env:"WEBHOOKS_POLL_INTERVAL"`
+	BatchSize       int           `yaml:"batch_size" toml:"batch_size" env:"WEBHOOKS_BATCH_SIZE"`
+	MaxAttempts     int           `yaml:"max_attempts" toml:"max_attempts" env:"WEBHOOKS_MAX_ATTEMPTS"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff" toml:"retry_backoff" env:"WEBHOOKS_RETRY_BACKOFF"`
+	RetryMaxBackoff time.Duration `yaml:"retry_max_backoff" toml:"retry_max_backoff" env:"WEBHOOKS_RETRY_MAX_BACKOFF"`
+	Lease           time.Duration `yaml:"lease" toml:"lease" env:"WEBHOOKS_LEASE"` // 認領後未記錄結果 (例如 dispatcher 中途停止) 時，經過多久可再次投遞
+	// 允許投遞到內部網路與 loopback 位址，僅供開發與測試環境使用
+	AllowPrivateNetworks bool `yaml:"allow_private_networks" toml:"allow_private_networks" env:"WEBHOOKS_ALLOW_PRIVATE_NETWORKS"`
+}
+
+// DefaultConfig webhook 預設設定
+func DefaultConfig() Config {
+	return Config{
+		Timeout:         10 * time.Second,
+		PollInterval:    time.Second,
+		BatchSize:       50,
+		MaxAttempts:     8,
+		RetryBackoff:    10 * time.Second,
+		RetryMaxBackoff: time.Hour,
+		Lease:           10 * time.Minute,
+	}
+}
+
+// Validate 檢查投遞與重試設定
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	var errs []error
+	if c.Timeout <= 0 || c.PollInterval <= 0 || c.BatchSize <= 0 || c.MaxAttempts <= 0 {
+		errs = append(errs, errors.New("webhooks timeout, poll_interval, batch_size and max_attempts must be positive"))
+	}
+	if c.RetryBackoff <= 0 || c.RetryMaxBackoff < c.RetryBackoff {
+		errs = append(errs, errors.New("webhooks retry backoff must be positive and max >= initial"))
+	}
+	if c.Lease < c.Timeout {
+		errs = append(errs, errors.New("webhooks lease must be at least the timeout"))
+	}
+	return errors.Join(errs...)
+}
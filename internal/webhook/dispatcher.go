@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+	"github.com/yoyo0827/simple-bank-system/internal/worker"
+)
+
+// Dispatcher 將到期的投遞紀錄送到訂閱的 URL
+// 失敗時以指數退避重試，超過 MaxAttempts 後標記為 dead
+type Dispatcher struct {
+	DB                *sql.DB
+	WebhookRepository *repository.WebhookRepository
+	Client            *http.Client
+	Config            Config
+}
+
+// Run 持續投遞直到 ctx 結束
+func (d *Dispatcher) Run(ctx context.Context) {
+	worker.Poll(ctx, "webhook dispatcher", d.Config.PollInterval, d.Config.BatchSize, d.ProcessBatch)
+}
+
+// ProcessBatch 認領一批到期的紀錄並投遞，回傳處理的筆數
+// 認領後立即提交，送出請求時不持有 transaction 或列鎖；不同訂閱並行投遞，同一訂閱依紀錄順序逐筆投遞
+func (d *Dispatcher) ProcessBatch(ctx context.Context) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookDispatcher.ProcessBatch")
+	defer func() { tracing.End(span, err) }()
+
+	due, err := d.WebhookRepository.ClaimDue(ctx, d.DB, d.Config.BatchSize, d.Config.Lease)
+	if err != nil {
+		return 0, err
+	}
+	bySubscription := make(map[string][]repository.DueDelivery)
+	for _, item := range due {
+		bySubscription[item.Delivery.SubscriptionID] = append(bySubscription[item.Delivery.SubscriptionID], item)
+	}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, items := range bySubscription {
+		wg.Go(func() {
+			for _, item := range items {
+				if err := d.deliver(ctx, item); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}
+		})
+	}
+	wg.Wait()
+	return len(due), errors.Join(errs...)
+}
+
+// 投遞一筆紀錄並記錄結果，記錄失敗時租約到期後會再次投遞
+func (d *Dispatcher) deliver(ctx context.Context, item repository.DueDelivery) error {
+	status, next, lastErr := domain.WebhookDeliveryDelivered, time.Now(), ""
+	code, derr := d.send(ctx, item)
+	if derr != nil {
+		attempts := item.Delivery.Attempts + 1
+		lastErr = derr.Error()
+		if attempts >= d.Config.MaxAttempts {
+			status = domain.WebhookDeliveryDead
+			slog.ErrorContext(ctx, "webhook delivery moved to dead letter",
+				"delivery_id", item.Delivery.ID, "subscription_id", item.Delivery.SubscriptionID, "attempts", attempts, "error", derr)
+		} else {
+			status = domain.WebhookDeliveryPending
+			next = time.Now().Add(worker.Backoff(d.Config.RetryBackoff, d.Config.RetryMaxBackoff, attempts))
+			slog.WarnContext(ctx, "webhook delivery failed, will retry",
+				"delivery_id", item.Delivery.ID, "subscription_id", item.Delivery.SubscriptionID, "attempts", attempts, "retry_at", next, "error", derr)
+		}
+	}
+	return d.WebhookRepository.SaveAttempt(ctx, d.DB, item.Delivery.ID, status, next, code, lastErr)
+}
+
+// 送出已簽章的請求，回傳 HTTP status (連線失敗時為 0)
+func (d *Dispatcher) send(ctx context.Context, item repository.DueDelivery) (int, error) {
+	body, err := json.Marshal(item.Event)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, item.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDeliveryID, item.Delivery.ID)
+	req.Header.Set(HeaderEvent, item.Event.Type)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(item.Secret, ts, body))
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,184 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+)
+
+const testSecret = "whsec_test_secret_value"
+
+// 接收端：驗證簽章後依設定的 status 回應
+type receiver struct {
+	status int
+	events []domain.Event
+	valid  []bool
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.valid = append(rc.valid, Verify(testSecret, r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, time.Minute))
+	var ev domain.Event
+	_ = json.Unmarshal(body, &ev)
+	rc.events = append(rc.events, ev)
+	w.WriteHeader(rc.status)
+}
+
+func newDispatcher(t *testing.T) (*Dispatcher, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	cfg := DefaultConfig()
+	cfg.MaxAttempts = 3
+	return &Dispatcher{DB: db, WebhookRepository: &repository.WebhookRepository{}, Client: http.DefaultClient, Config: cfg}, mock
+}
+
+func dueRows(url string, attempts int) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{"id", "subscription_id", "event_id", "status", "attempts", "next_attempt_at",
+		"response_status", "last_error", "created_at", "delivered_at", "url", "secret",
+		"event_type", "aggregate_id", "request_id", "payload", "occurred_at"}).
+		AddRow("10", "1", int64(42), domain.WebhookDeliveryPending, attempts, now, 0, "", now, nil, url, testSecret,
+			domain.EventDeposited, "acc1", "req-1", []byte(`{"account_number":"acc1","amount":"50"}`), now)
+}
+
+// 單元測試投遞成功：帶有可驗證的簽章，並標記為 delivered
+func TestDispatcher_Delivered(t *testing.T) {
+	rc := &receiver{status: http.StatusNoContent}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+	d, mock := newDispatcher(t)
+
+	mock.ExpectQuery(`UPDATE webhook_deliveries SET next_attempt_at`).WithArgs(d.Config.BatchSize, d.Config.Lease.Milliseconds()).WillReturnRows(dueRows(srv.URL, 0))
+	mock.ExpectExec(`UPDATE webhook_deliveries`).
+		WithArgs("10", domain.WebhookDeliveryDelivered, sqlmock.AnyArg(), http.StatusNoContent, "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	n, err := d.ProcessBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []bool{true}, rc.valid)
+	assert.Equal(t, int64(42), rc.events[0].ID)
+	assert.Equal(t, domain.EventDeposited, rc.events[0].Type)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試投遞失敗：未達上限時排程重試，達上限時進入 dead letter
+func TestDispatcher_RetryAndDead(t *testing.T) {
+	rc := &receiver{status: http.StatusInternalServerError}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	cases := []struct {
+		attempts int
+		status   string
+	}{
+		{attempts: 0, status: domain.WebhookDeliveryPending},
+		{attempts: 2, status: domain.WebhookDeliveryDead},
+	}
+	for _, c := range cases {
+		d, mock := newDispatcher(t)
+		mock.ExpectQuery(`UPDATE webhook_deliveries SET next_attempt_at`).WillReturnRows(dueRows(srv.URL, c.attempts))
+		mock.ExpectExec(`UPDATE webhook_deliveries`).
+			WithArgs("10", c.status, sqlmock.AnyArg(), http.StatusInternalServerError, "webhook responded with status 500").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := d.ProcessBatch(context.Background())
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}
+}
+
+// 單元測試不同訂閱並行投遞：一個訂閱的接收端尚未回應時不會阻擋其他訂閱
+func TestDispatcher_ParallelSubscriptions(t *testing.T) {
+	fastDone := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-fastDone:
+			w.WriteHeader(http.StatusOK)
+		case <-time.After(5 * time.Second):
+			w.WriteHeader(http.StatusGatewayTimeout)
+		}
+	}))
+	defer slow.Close()
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(fastDone)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer fast.Close()
+	d, mock := newDispatcher(t)
+	mock.MatchExpectationsInOrder(false)
+
+	now := time.Now()
+	mock.ExpectQuery(`UPDATE webhook_deliveries SET next_attempt_at`).WillReturnRows(dueRows(slow.URL, 0).
+		AddRow("11", "2", int64(43), domain.WebhookDeliveryPending, 0, now, 0, "", now, nil, fast.URL, testSecret,
+			domain.EventDeposited, "acc1", "req-1", []byte(`{"account_number":"acc1","amount":"50"}`), now))
+	for _, id := range []string{"10", "11"} {
+		mock.ExpectExec(`UPDATE webhook_deliveries`).
+			WithArgs(id, domain.WebhookDeliveryDelivered, sqlmock.AnyArg(), http.StatusOK, "").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	n, err := d.ProcessBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試連線失敗時沒有 HTTP status
+func TestDispatcher_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+	d, mock := newDispatcher(t)
+
+	mock.ExpectQuery(`UPDATE webhook_deliveries SET next_attempt_at`).WillReturnRows(dueRows(url, 0))
+	mock.ExpectExec(`UPDATE webhook_deliveries`).
+		WithArgs("10", domain.WebhookDeliveryPending, sqlmock.AnyArg(), 0, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := d.ProcessBatch(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// 單元測試投遞用 client 拒絕連線到 loopback 與 metadata 位址，開發環境可允許內部網路
+func TestNewClient_PrivateNetworks(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	_, err := NewClient(cfg).Get(srv.URL)
+	assert.ErrorIs(t, err, ErrAddressNotAllowed)
+	_, err = NewClient(cfg).Get("http://169.254.169.254/latest/meta-data/")
+	assert.ErrorIs(t, err, ErrAddressNotAllowed)
+
+	cfg.AllowPrivateNetworks = true
+	resp, err := NewClient(cfg).Get(srv.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+}
+
+// 單元測試簽章驗證：secret、內容或時間不符皆失敗
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	now := time.Now().Unix()
+	ts := strconv.FormatInt(now, 10)
+	sig := Sign(testSecret, now, body)
+
+	assert.True(t, Verify(testSecret, ts, sig, body, time.Minute))
+	assert.False(t, Verify("other-secret", ts, sig, body, time.Minute))
+	assert.False(t, Verify(testSecret, ts, sig, []byte(`{"id":2}`), time.Minute))
+	old := now - 3600
+	assert.False(t, Verify(testSecret, strconv.FormatInt(old, 10), Sign(testSecret, old, body), body, time.Minute))
+}
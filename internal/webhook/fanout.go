@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+)
+
+// FanoutSinkName fan-out 在 outbox 中使用的 sink 名稱
+const FanoutSinkName = "webhook_subscriptions"
+
+// Fanout 作為 outbox sink，將每個事件展開為符合條件訂閱的投遞紀錄
+// 實際的 HTTP 投遞由 Dispatcher 負責，個別訂閱失敗不會阻塞 outbox
+type Fanout struct {
+	DB                *sql.DB
+	WebhookRepository *repository.WebhookRepository
+}
+
+func (f *Fanout) Name() string { return FanoutSinkName }
+
+func (f *Fanout) Deliver(ctx context.Context, ev *domain.Event) error {
+	return f.WebhookRepository.EnqueueDeliveries(ctx, f.DB, ev)
+}
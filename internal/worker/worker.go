@@ -0,0 +1,49 @@
+// Package worker 背景工作共用的定期執行、輪詢迴圈與重試退避
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// RunEvery 立即呼叫一次 fn，之後每 interval 呼叫一次直到 ctx 結束；attrs 會附加在日誌中
+// fn 回傳的錯誤只記錄，下次仍會再呼叫
+func RunEvery(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error, attrs ...any) {
+	slog.Info(name+" started", append([]any{"interval", interval}, attrs...)...)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := fn(ctx); err != nil && ctx.Err() == nil {
+			slog.Error(name+" failed", append(attrs, "error", err)...)
+		}
+		select {
+		case <-ctx.Done():
+			slog.Info(name+" stopped", attrs...)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll 以 RunEvery 每 interval 處理一次，process 回傳該批處理的筆數
+// 一批處理滿 batchSize 時立即處理下一批，否則等待下次輪詢
+func Poll(ctx context.Context, name string, interval time.Duration, batchSize int, process func(context.Context) (int, error), attrs ...any) {
+	RunEvery(ctx, name, interval, func(ctx context.Context) error {
+		for {
+			n, err := process(ctx)
+			if err != nil || n < batchSize {
+				return err
+			}
+		}
+	}, attrs...)
+}
+
+// Backoff 指數退避：base * 2^(attempts-1)，不超過 max
+func Backoff(base, max time.Duration, attempts int) time.Duration {
+	d := base
+	for i := 1; i < attempts && d < max; i++ {
+		d *= 2
+	}
+	return min(d, max)
+}
@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, Backoff(time.Second, 5*time.Second, 1))
+	assert.Equal(t, 2*time.Second, Backoff(time.Second, 5*time.Second, 2))
+	assert.Equal(t, 4*time.Second, Backoff(time.Second, 5*time.Second, 3))
+	assert.Equal(t, 5*time.Second, Backoff(time.Second, 5*time.Second, 4))
+	assert.Equal(t, 5*time.Second, Backoff(time.Second, 5*time.Second, 20))
+}
+
+// 單元測試 Poll：一批處理滿時立即處理下一批，ctx 結束後停止
+func TestPoll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	batches := []int{2, 2, 1}
+	var calls int
+	Poll(ctx, "test worker", time.Hour, 2, func(context.Context) (int, error) {
+		n := batches[calls]
+		if calls++; calls == len(batches) {
+			cancel()
+		}
+		return n, nil
+	})
+	assert.Equal(t, 3, calls)
+}
+
+// 單元測試 RunEvery：錯誤不會中斷，ctx 結束後停止
+func TestRunEvery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+	RunEvery(ctx, "test worker", time.Millisecond, func(context.Context) error {
+		if calls++; calls == 3 {
+			cancel()
+		}
+		return errors.New("temporary failure")
+	})
+	assert.Equal(t, 3, calls)
+}
@@ -1,47 +1,92 @@
 package main
 
 import (
-	"log"
-	"net/http"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
-	"github.com/yoyo0827/simple-bank-system/internal/api"
 	"github.com/yoyo0827/simple-bank-system/internal/config"
-	"github.com/yoyo0827/simple-bank-system/internal/repository"
-	"github.com/yoyo0827/simple-bank-system/internal/router"
-	"github.com/yoyo0827/simple-bank-system/internal/service"
 
 	_ "github.com/yoyo0827/simple-bank-system/docs" // swagger docs
 )
 
+const usage = `Usage: bank-server <command> [flags]
+
+Commands:
+  serve       start the HTTP and gRPC servers (default)
+  migrate     apply or revert schema migrations: migrate up | down [-steps N] | status
+  seed        create demo / load-test accounts with transaction history
+  reconcile   check every account balance against its transactions
+  close-day   close a business date and write end-of-day balances: close-day [-date YYYY-MM-DD]
+  gl-export   export the daily general-ledger summary: gl-export [-date YYYY-MM-DD] [-format csv|json] [-out FILE]
+  import-accounts
+              import accounts and opening balances from CSV: import-accounts -file FILE [-dry-run]
+
+Every command accepts the configuration flags (run "bank-server <command> -h").
+`
+
 // @title Simple Bank System API
 // @version 1.0
 // @description A simple banking system implemented in Go with RESTful APIs.
 // @host localhost:8080
 // @BasePath /
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
-	// 本機開發使用 .env
-	if err := godotenv.Load(); err == nil {
-		log.Println("loaded .env file")
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	// 未指定子命令 (或直接帶參數) 時啟動服務，與舊版用法相容
+	cmd := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
 	}
-	// 初始化 DB
-	config.InitDatabase()
-	defer config.DB.Close()
-
-	// 初始化 Handler & Service & Repository
-	accountRepo := &repository.AccountRepository{}
-	transactionRepo := &repository.TransactionRepository{}
-	service := &service.AccountService{
-		DB:                    config.DB,
-		AccountRepository:     accountRepo,
-		TransactionRepository: transactionRepo,
+	switch cmd {
+	case "serve":
+		return serve(args)
+	case "migrate":
+		return migrateCmd(args)
+	case "seed":
+		return seedCmd(args)
+	case "reconcile":
+		return reconcileCmd(args)
+	case "close-day":
+		return closeDayCmd(args)
+	case "gl-export":
+		return glExportCmd(args)
+	case "import-accounts":
+		return importAccountsCmd(args)
+	case "help":
+		fmt.Print(usage)
+		return 0
 	}
-	handler := &api.ApiHandler{AccountService: service}
-	// 啟動 server
-	mux := router.NewRouter(handler)
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, usage)
+	return 2
+}
+
+// loadConfig 載入 .env 與設定，子命令可先在 fs 註冊自己的參數
+func loadConfig(fs *flag.FlagSet, args []string) (cfg *config.Config, envLoaded bool, err error) {
+	// 本機開發使用 .env
+	envLoaded = godotenv.Load() == nil
+	load := config.Bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, envLoaded, err
+	}
+	cfg, err = load()
+	return cfg, envLoaded, err
+}
 
-	log.Println("Server running on :8080")
-	if err := http.ListenAndServe(":8080", mux); err != nil {
-		log.Fatal(err)
+// 設定錯誤回傳 exit code 2 (-h 顯示說明時為 0)
+func configError(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
 	}
+	slog.Error("invalid configuration", "error", err)
+	return 2
 }
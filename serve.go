@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/yoyo0827/simple-bank-system/internal/api"
+	"github.com/yoyo0827/simple-bank-system/internal/approval"
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/businessday"
+	"github.com/yoyo0827/simple-bank-system/internal/config"
+	"github.com/yoyo0827/simple-bank-system/internal/grpcapi"
+	"github.com/yoyo0827/simple-bank-system/internal/logging"
+	"github.com/yoyo0827/simple-bank-system/internal/metrics"
+	"github.com/yoyo0827/simple-bank-system/internal/migrate"
+	"github.com/yoyo0827/simple-bank-system/internal/outbox"
+	"github.com/yoyo0827/simple-bank-system/internal/ratelimit"
+	"github.com/yoyo0827/simple-bank-system/internal/repository"
+	"github.com/yoyo0827/simple-bank-system/internal/router"
+	"github.com/yoyo0827/simple-bank-system/internal/server"
+	"github.com/yoyo0827/simple-bank-system/internal/snapshot"
+	"github.com/yoyo0827/simple-bank-system/internal/stream"
+	"github.com/yoyo0827/simple-bank-system/internal/tracing"
+	"github.com/yoyo0827/simple-bank-system/internal/webhook"
+)
+
+// serve 啟動 HTTP 與 gRPC server 及背景工作
+func serve(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	autoMigrate := fs.Bool("migrate", false, "apply pending migrations before starting")
+	// 載入設定
+	cfg, envLoaded, err := loadConfig(fs, args)
+	if err != nil {
+		return configError(err)
+	}
+	// 初始化 logger
+	slog.SetDefault(logging.New(os.Stdout, cfg.Log))
+	if envLoaded {
+		slog.Info("loaded .env file")
+	}
+	// 初始化 tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		slog.Error("failed to set up tracing", "error", err)
+		return 1
+	}
+	defer func() {
+		if terr := shutdownTracing(context.Background()); terr != nil {
+			slog.Error("failed to flush traces", "error", terr)
+		}
+	}()
+	// 初始化 DB
+	db, err := config.OpenDatabase(cfg.Database)
+	if err != nil {
+		slog.Error("failed to open database", "error", err)
+		return 1
+	}
+	defer func() {
+		if cerr := db.Close(); cerr != nil {
+			slog.Error("failed to close database", "error", cerr)
+		}
+	}()
+	migrator, err := newMigrator(db)
+	if err != nil {
+		slog.Error("invalid migrations", "error", err)
+		return 1
+	}
+	if *autoMigrate {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			slog.Error("failed to apply migrations", "error", err)
+			return 1
+		}
+		for _, m := range applied {
+			slog.Info("applied migration", "version", m.Version, "name", m.Name)
+		}
+	}
+
+	authn, err := auth.NewAuthenticator(cfg.Auth)
+	if err != nil {
+		slog.Error("invalid auth configuration", "error", err)
+		return 1
+	}
+	if !authn.Enabled() {
+		slog.Warn("no API keys configured, requests are anonymous and admin APIs are disabled")
+	}
+
+	// 初始化 Handler & Service & Repository
+	a := newApp(cfg, db)
+	// 手續費收入帳號不存在時每筆收費交易都會失敗，啟動時即回報設定錯誤
+	if err := a.accountService.CheckFeeRevenueAccount(context.Background()); err != nil {
+		slog.Error("invalid fee configuration", "error", err)
+		return 1
+	}
+	broker := stream.NewBroker(cfg.Stream.BufferSize)
+	if cfg.Stream.Backend == stream.BackendMemory {
+		a.accountService.Publisher = broker
+	}
+	// rate limit：postgres 時由所有實例共用令牌桶
+	var limitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimit.Backend == ratelimit.BackendPostgres {
+		limitStore = &ratelimit.PostgresStore{DB: db, RateLimitRepository: &repository.RateLimitRepository{}}
+	}
+	limiter := ratelimit.New(cfg.RateLimit, limitStore)
+	health := &api.HealthHandler{DB: db, PingTimeout: cfg.Server.ReadinessTimeout, SchemaVersion: migrate.Latest(migrator.Migrations)}
+	mux := router.NewRouter(router.Handlers{
+		Account:        &api.ApiHandler{AccountService: a.accountService, Validator: a.validator},
+		Health:         health,
+		Audit:          &api.AuditHandler{AuditService: a.auditService, Validator: a.validator},
+		Webhook:        &api.WebhookHandler{WebhookService: a.webhookService, Validator: a.validator},
+		Stream:         &api.StreamHandler{AccountService: a.accountService, Validator: a.validator, Broker: broker, Heartbeat: cfg.Stream.Heartbeat},
+		Reconciliation: &api.ReconciliationHandler{ReconciliationService: a.reconciliationService},
+		Transfer:       &api.PendingTransferHandler{PendingTransferService: a.pendingService, Validator: a.validator},
+		Customer:       &api.CustomerHandler{CustomerService: a.customerService, AccountService: a.accountService, Validator: a.validator},
+		Payee:          &api.PayeeHandler{PayeeService: a.payeeService, AccountService: a.accountService, Validator: a.validator},
+		BusinessDay:    &api.BusinessDayHandler{BusinessDayService: a.businessDayService},
+		Report:         &api.ReportHandler{ReportService: a.reportService, Validator: a.validator},
+		Limiter:        limiter,
+	}, cfg.Features)
+
+	// 收到 SIGINT / SIGTERM 時進行 graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+
+	// 啟動 outbox relay，每個 sink 一個 goroutine
+	var workers sync.WaitGroup
+	closeSinks := func() error { return nil }
+	// 結束時 (含啟動途中失敗) 先停止背景工作，進行中的請求與背景工作結束後才關閉 sink 與 DB
+	defer func() {
+		stop()
+		workers.Wait()
+		if cerr := closeSinks(); cerr != nil {
+			slog.Error("failed to close outbox sinks", "error", cerr)
+		}
+	}()
+	if cfg.Outbox.Enabled {
+		sinks, closeAll, err := outbox.NewSinks(cfg.Outbox)
+		if err != nil {
+			slog.Error("failed to set up outbox sinks", "error", err)
+			return 1
+		}
+		closeSinks = closeAll
+		for _, sink := range sinks {
+			relay := &outbox.Relay{DB: db, OutboxRepository: a.outboxRepo, Sink: sink, Config: cfg.Outbox}
+			if err := relay.Register(ctx); err != nil {
+				slog.Error("failed to register outbox sink", "sink", sink.Name(), "error", err)
+				return 1
+			}
+			workers.Go(func() { relay.Run(ctx) })
+		}
+	}
+	// 刪除超過保留期限的事件
+	if cfg.Outbox.Retention > 0 {
+		pruner := &outbox.Pruner{DB: db, OutboxRepository: a.outboxRepo, Config: cfg.Outbox}
+		workers.Go(func() { pruner.Run(ctx) })
+	}
+	// 刪除已補滿的共用令牌桶
+	if limiter != nil && cfg.RateLimit.Backend == ratelimit.BackendPostgres {
+		pruner := &ratelimit.Pruner{DB: db, RateLimitRepository: &repository.RateLimitRepository{}, Config: cfg.RateLimit}
+		workers.Go(func() { pruner.Run(ctx) })
+	}
+	// 事件串流：由 LISTEN/NOTIFY 接收所有實例提交的事件
+	if cfg.Stream.Backend == stream.BackendPostgres {
+		listener := &stream.Listener{DSN: cfg.Database.DSN(), Broker: broker}
+		workers.Go(func() {
+			if err := listener.Run(ctx); err != nil {
+				slog.Error("event stream listener failed", "error", err)
+			}
+		})
+	}
+	// webhook：由 outbox 展開為各訂閱的投遞紀錄，再由 dispatcher 投遞
+	if cfg.Webhooks.Enabled {
+		fanout := &outbox.Relay{
+			DB:               db,
+			OutboxRepository: a.outboxRepo,
+			Sink:             &webhook.Fanout{DB: db, WebhookRepository: a.webhookRepo},
+			Config:           cfg.Outbox,
+		}
+		dispatcher := &webhook.Dispatcher{
+			DB:                db,
+			WebhookRepository: a.webhookRepo,
+			Client:            webhook.NewClient(cfg.Webhooks),
+			Config:            cfg.Webhooks,
+		}
+		if err := fanout.Register(ctx); err != nil {
+			slog.Error("failed to register outbox sink", "sink", webhook.FanoutSinkName, "error", err)
+			return 1
+		}
+		workers.Go(func() { fanout.Run(ctx) })
+		workers.Go(func() { dispatcher.Run(ctx) })
+	}
+	// 逾期未處理的待處理轉帳標記為失效並釋放保留款項
+	if cfg.Approval.Expiry > 0 {
+		expirer := &approval.Expirer{Service: a.pendingService, Interval: cfg.Approval.SweepInterval}
+		workers.Go(func() { expirer.Run(ctx) })
+	}
+
+	// 定期建立餘額快照，加快歷史餘額查詢
+	if cfg.BalanceSnapshots.Interval > 0 {
+		snapshots := &snapshot.Worker{Service: a.accountService, Config: cfg.BalanceSnapshots}
+		workers.Go(func() { snapshots.Run(ctx) })
+	}
+	// 換日後自動結帳前一營業日 (多個實例同時執行時只有一個會寫入)
+	if cfg.BusinessDay.AutoClose {
+		eod := &businessday.Worker{Service: a.businessDayService, Config: cfg.BusinessDay}
+		workers.Go(func() { eod.Run(ctx) })
+	}
+
+	// 啟動 gRPC server (獨立 port)，啟動失敗時一併關閉 HTTP server
+	if cfg.GRPC.Enabled {
+		grpcServer := grpcapi.NewServer(&grpcapi.BankServer{AccountService: a.accountService, Validator: a.validator}, authn, limiter, cfg.GRPC.Reflection)
+		workers.Go(func() {
+			if err := grpcapi.Serve(ctx, grpcServer, cfg.GRPC.Addr(), cfg.Server.ShutdownTimeout); err != nil {
+				slog.Error("grpc server error", "error", err)
+				stop()
+			}
+		})
+	}
+
+	// 啟動 server
+	if cfg.Features.Metrics {
+		metrics.RegisterDB(db, cfg.Database.Name)
+	}
+	srv := server.New(cfg.Server, httpHandler(mux, authn, cfg.Features))
+	srv.OnDrain(health.SetDraining)
+	srv.OnDrain(broker.Close)
+	if err := srv.Run(ctx); err != nil {
+		slog.Error("server error", "error", err)
+		return 1
+	}
+	return 0
+}
+
+// httpHandler 依序套用 tracing、access log、metrics 與認證 middleware
+// 路由 pattern 由 router 經 route.Record 寫回，外層 middleware 皆可取得
+func httpHandler(mux http.Handler, authn *auth.Authenticator, features config.FeatureConfig) http.Handler {
+	h := authn.Middleware(mux)
+	if features.Metrics {
+		h = metrics.Middleware(h)
+	}
+	h = logging.Middleware(h)
+	return tracing.Middleware(h)
+}
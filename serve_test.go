@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/yoyo0827/simple-bank-system/internal/api"
+	"github.com/yoyo0827/simple-bank-system/internal/auth"
+	"github.com/yoyo0827/simple-bank-system/internal/config"
+	"github.com/yoyo0827/simple-bank-system/internal/metrics"
+	"github.com/yoyo0827/simple-bank-system/internal/router"
+)
+
+// 單元測試 經過完整 middleware 鏈 (認證會複製請求) 後，metrics 與 access log 仍取得路由 pattern
+func TestHTTPHandler_Route(t *testing.T) {
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	defer slog.SetDefault(prev)
+
+	authn, err := auth.NewAuthenticator(auth.Config{APIKeys: []string{"k1:alice:user"}})
+	assert.NoError(t, err)
+	features := config.FeatureConfig{Metrics: true}
+	mux := router.NewRouter(router.Handlers{Health: &api.HealthHandler{}}, features)
+	h := httpHandler(mux, authn, features)
+
+	healthz := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "GET /healthz", "200")
+	audit := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "GET /admin/audit", "403")
+	before, beforeAudit := testutil.ToFloat64(healthz), testutil.ToFloat64(audit)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 非 admin 在 handler 之前被拒絕，仍記錄比對到的路由
+	req := httptest.NewRequest(http.MethodGet, "/admin/audit", nil)
+	req.Header.Set(auth.APIKeyHeader, "k1")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	assert.Equal(t, before+1, testutil.ToFloat64(healthz))
+	assert.Equal(t, beforeAudit+1, testutil.ToFloat64(audit))
+	assert.Contains(t, logs.String(), `route="GET /healthz"`)
+	assert.Contains(t, logs.String(), `route="GET /admin/audit"`)
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 
@@ -8,6 +9,10 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 
+	schema "github.com/yoyo0827/simple-bank-system/db"
+	"github.com/yoyo0827/simple-bank-system/internal/accountno"
+	"github.com/yoyo0827/simple-bank-system/internal/domain"
+	"github.com/yoyo0827/simple-bank-system/internal/migrate"
 	"github.com/yoyo0827/simple-bank-system/internal/repository"
 	"github.com/yoyo0827/simple-bank-system/internal/request"
 	"github.com/yoyo0827/simple-bank-system/internal/service"
@@ -22,63 +27,80 @@ func setupIntegrationDB(t *testing.T) *service.AccountService {
 	if err := db.Ping(); err != nil {
 		t.Fatalf("cannot ping test db: %v", err)
 	}
+	// 套用 schema migration
+	migrations, err := migrate.Load(schema.Migrations, "migrations")
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	if _, err := (&migrate.Migrator{DB: db, Migrations: migrations}).Up(context.Background()); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
 
 	return &service.AccountService{
 		DB:                    db,
 		AccountRepository:     &repository.AccountRepository{},
 		TransactionRepository: &repository.TransactionRepository{},
+		AuditRepository:       &repository.AuditRepository{},
+		OutboxRepository:      &repository.OutboxRepository{},
+		AccountNumbers:        accountno.DefaultConfig(),
 	}
 }
 
 func TestIntegration(t *testing.T) {
 	svc := setupIntegrationDB(t)
+	ctx := context.Background()
 
 	// === 建立帳號 ===
-	acc1, err := svc.CreateAccount("test1", 100)
+	acc1, err := svc.CreateAccount(ctx, &request.CreateAccountRequest{Name: "test1", Balance: decimal.NewFromInt(100)})
 	assert.NoError(t, err)
-	acc2, err := svc.CreateAccount("test2", 50)
+	acc2, err := svc.CreateAccount(ctx, &request.CreateAccountRequest{Name: "test2", Balance: decimal.NewFromInt(50)})
 	assert.NoError(t, err)
 
-	// 驗證帳號正確建立
-	got1, _ := svc.FindAccount(acc1.ID)
+	// 驗證帳號正確建立，並可以帳號號碼查詢
+	assert.True(t, accountno.DefaultConfig().Valid(acc1.Number))
+	byNumber, err := svc.FindAccountByNumber(ctx, acc1.Number)
+	assert.NoError(t, err)
+	assert.Equal(t, acc1.ID, byNumber.ID)
+	got1, _ := svc.FindAccount(ctx, acc1.ID)
 	assert.Equal(t, "test1", got1.Name)
 	assert.Equal(t, "100", got1.Balance.String())
 
-	got2, _ := svc.FindAccount(acc2.ID)
+	got2, _ := svc.FindAccount(ctx, acc2.ID)
 	assert.Equal(t, "test2", got2.Name)
 	assert.Equal(t, "50", got2.Balance.String())
 
 	// === 存款 ===
 	depReq := &request.TransactionRequest{Amount: decimal.NewFromInt(40)} // 存 40
-	depRefID, err := svc.CreateTransaction(acc1.ID, depReq)
+	depRefID, err := svc.CreateTransaction(ctx, acc1.ID, depReq)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, depRefID)
 
-	afterDeposit, _ := svc.FindAccount(acc1.ID)
+	afterDeposit, _ := svc.FindAccount(ctx, acc1.ID)
 	assert.Equal(t, "140", afterDeposit.Balance.String()) // 100 + 40
 
 	// === 提款 ===
 	wdReq := &request.TransactionRequest{Amount: decimal.NewFromInt(-20)} // 提 20
-	wdRefID, err := svc.CreateTransaction(acc1.ID, wdReq)
+	wdRefID, err := svc.CreateTransaction(ctx, acc1.ID, wdReq)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, wdRefID)
 
-	afterWithdraw, _ := svc.FindAccount(acc1.ID)
+	afterWithdraw, _ := svc.FindAccount(ctx, acc1.ID)
 	assert.Equal(t, "120", afterWithdraw.Balance.String()) // 140 - 20
 
 	// === 轉帳 ===
-	tfReq := &request.TransferRequest{FromID: acc1.ID, ToID: acc2.ID, Amount: decimal.NewFromInt(30)}
-	tfRefID, err := svc.Transfer(tfReq)
+	tfReq := &request.TransferRequest{FromAccount: acc1.Number, ToAccount: acc2.Number, Amount: decimal.NewFromInt(30)}
+	tfRes, err := svc.Transfer(ctx, tfReq)
 	assert.NoError(t, err)
-	assert.NotEmpty(t, tfRefID)
+	assert.Equal(t, domain.TransferStatusCompleted, tfRes.Status)
+	tfRefID := tfRes.RefID
 
-	afterTF1, _ := svc.FindAccount(acc1.ID)
-	afterTF2, _ := svc.FindAccount(acc2.ID)
+	afterTF1, _ := svc.FindAccount(ctx, acc1.ID)
+	afterTF2, _ := svc.FindAccount(ctx, acc2.ID)
 	assert.Equal(t, "90", afterTF1.Balance.String()) // 120 - 30
 	assert.Equal(t, "80", afterTF2.Balance.String()) // 50 + 30
 
 	// === 查交易紀錄 ===
-	txs1, err := svc.FindAccountTransactions(acc1.ID)
+	txs1, err := svc.FindAccountTransactions(ctx, acc1.ID, repository.TransactionFilter{})
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(txs1), 3) // 存款 / 提款 / 轉帳
 
@@ -86,7 +108,7 @@ func TestIntegration(t *testing.T) {
 	assert.Equal(t, tfRefID, lastTx.RefID) // 最新一筆應該是轉帳
 	assert.Equal(t, 1, lastTx.Type)        // acc1 這邊的轉帳是提款
 
-	txs2, err := svc.FindAccountTransactions(acc2.ID)
+	txs2, err := svc.FindAccountTransactions(ctx, acc2.ID, repository.TransactionFilter{})
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(txs2), 1) // 至少一筆轉帳紀錄
 	assert.Equal(t, tfRefID, txs2[len(txs2)-1].RefID)